@@ -0,0 +1,269 @@
+package api
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+	"unicode/utf8"
+)
+
+const (
+	attachmentFormField = "file"
+	// maxMultipartOverhead leaves room for the multipart boundaries and headers around the file
+	maxMultipartOverhead = 64 << 10
+	maxFileNameLength    = 255
+)
+
+// attachmentExtensions lists the accepted content types with the extension their blobs are stored under.
+var attachmentExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"video/mp4":       ".mp4",
+	"video/webm":      ".webm",
+	"audio/mpeg":      ".mp3",
+	"application/pdf": ".pdf",
+}
+
+var (
+	errAttachmentTooLarge = errors.New("the attachment exceeds the size limit")
+	errAttachmentType     = errors.New("the attachment must be a JPEG, PNG, GIF or WebP image, an MP4 or WebM video, an MP3 or a PDF")
+)
+
+type deleteAttachmentRequest struct {
+	ID           int `uri:"id" binding:"required"`
+	AttachmentID int `uri:"attachmentId" binding:"required,min=1"`
+}
+
+type AttachmentResponse struct {
+	ID          int    `json:"id"`
+	URL         string `json:"url"`
+	FileName    string `json:"fileName"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+	CreatedAt   string `json:"createdAt"`
+}
+
+// @Summary Attach a file to a post
+// @Tags Attachment
+// @Description Upload an image, video, audio or PDF file to a post. The content type is detected from the file itself,
+// @Description the one declared by the client is ignored
+// @ID create-attachment
+// @Accept mpfd
+// @Produce json
+// @Param id path string true "the specific post id"
+// @Param file formData file true "the file to attach"
+// @Success 200 {object} AttachmentResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 413 {object} ErrResponse
+// @Failure 415 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/{id}/attachments [post]
+func (s *Server) createAttachment(context *gin.Context) {
+	var request getPostRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	post, err := s.store.GetPostById(context, int32(request.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			context.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	context.Request.Body = http.MaxBytesReader(context.Writer, context.Request.Body, s.config.AttachmentMaxSize+maxMultipartOverhead)
+	fileHeader, err := context.FormFile(attachmentFormField)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			context.JSON(http.StatusRequestEntityTooLarge, errorResponse(errAttachmentTooLarge))
+			return
+		}
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if fileHeader.Size > s.config.AttachmentMaxSize {
+		context.JSON(http.StatusRequestEntityTooLarge, errorResponse(errAttachmentTooLarge))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	defer file.Close()
+
+	contentType, err := sniffContentType(file)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	extension, ok := attachmentExtensions[contentType]
+	if !ok {
+		context.JSON(http.StatusUnsupportedMediaType, errorResponse(errAttachmentType))
+		return
+	}
+
+	key, err := attachmentKey(post.ID, extension)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if err = s.blobs.Put(context, key, file, fileHeader.Size, contentType); err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	attachment, err := s.store.CreateAttachment(context, db.CreateAttachmentParams{
+		PostID:      post.ID,
+		BlobKey:     key,
+		FileName:    attachmentFileName(fileHeader),
+		ContentType: contentType,
+		Size:        fileHeader.Size,
+	})
+	if err != nil {
+		s.deleteBlobs(context, key)
+
+		var pqError *pq.Error
+		if errors.As(err, &pqError) && pqError.Code.Name() == "foreign_key_violation" {
+			context.JSON(http.StatusNotFound, errorResponse(sql.ErrNoRows))
+			return
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	context.JSON(http.StatusOK, s.mapToAttachmentResponse(attachment))
+}
+
+// @Summary Delete attachment
+// @Tags Attachment
+// @Description Remove a file from a post and from the storage
+// @ID delete-attachment
+// @Produce json
+// @Param id path string true "the specific post id"
+// @Param attachmentId path string true "the specific attachment id"
+// @Success 200
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/{id}/attachments/{attachmentId} [delete]
+func (s *Server) deleteAttachment(context *gin.Context) {
+	var request deleteAttachmentRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	attachment, err := s.store.DeleteAttachment(context, db.DeleteAttachmentParams{
+		ID:     int32(request.AttachmentID),
+		PostID: int32(request.ID),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			context.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	s.deleteBlobs(context, attachment.BlobKey)
+	context.Status(http.StatusOK)
+}
+
+// deleteBlobs removes blobs no attachment refers to anymore. A blob that fails to be removed is only wasted space,
+// so failures are logged instead of failing the request.
+func (s *Server) deleteBlobs(context *gin.Context, keys ...string) {
+	for _, key := range keys {
+		if err := s.blobs.Delete(context, key); err != nil {
+			log.Printf("failed to delete blob %s: %v", key, err)
+		}
+	}
+}
+
+// loadAttachments fetches the attachments of the given posts in a single query.
+func (s *Server) loadAttachments(context *gin.Context, postIDs []int32) (map[int32][]AttachmentResponse, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+
+	attachments, err := s.store.GetAttachmentsByPostIds(context, postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	attachmentsByPostId := make(map[int32][]AttachmentResponse, len(postIDs))
+	for _, attachment := range attachments {
+		attachmentsByPostId[attachment.PostID] = append(attachmentsByPostId[attachment.PostID], s.mapToAttachmentResponse(attachment))
+	}
+	return attachmentsByPostId, nil
+}
+
+// sniffContentType detects the content type from the first bytes of the file and rewinds it.
+func sniffContentType(file multipart.File) (string, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if err != nil {
+		return "", err
+	}
+	return mediaType, nil
+}
+
+// attachmentKey names the blob of a new attachment. The random part keeps keys unguessable and unique.
+func attachmentKey(postID int32, extension string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("posts/%d/%s%s", postID, hex.EncodeToString(random), extension), nil
+}
+
+// attachmentFileName is the name the client gave the file, cut to fit the column.
+func attachmentFileName(fileHeader *multipart.FileHeader) string {
+	name := fileHeader.Filename
+	for len(name) > maxFileNameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
+
+func (s *Server) mapToAttachmentResponse(attachment db.Attachment) AttachmentResponse {
+	return AttachmentResponse{
+		ID:          int(attachment.ID),
+		URL:         s.blobs.URL(attachment.BlobKey),
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		CreatedAt:   attachment.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
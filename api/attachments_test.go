@@ -0,0 +1,330 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pngHeader is enough of a PNG file for the content type to be detected
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestCreateAttachment(t *testing.T) {
+	randomPost := generateRandomPost()
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		fileName      string
+		file          []byte
+		blobErr       error
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore)
+	}{
+		{
+			name:     "positive_CreateAttachment",
+			fileName: "cover.png",
+			file:     pngHeader,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+				querier.EXPECT().
+					CreateAttachment(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateAttachmentParams) (db.Attachment, error) {
+						require.Equal(t, randomPost.ID, arg.PostID)
+						require.True(t, strings.HasPrefix(arg.BlobKey, fmt.Sprintf("posts/%d/", randomPost.ID)))
+						require.True(t, strings.HasSuffix(arg.BlobKey, ".png"))
+						require.Equal(t, "cover.png", arg.FileName)
+						require.Equal(t, "image/png", arg.ContentType)
+						require.Equal(t, int64(len(pngHeader)), arg.Size)
+						return db.Attachment{
+							ID:          3,
+							PostID:      arg.PostID,
+							BlobKey:     arg.BlobKey,
+							FileName:    arg.FileName,
+							ContentType: arg.ContentType,
+							Size:        arg.Size,
+							CreatedAt:   createdAt,
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Len(t, blobs.blobs, 1)
+				for key, data := range blobs.blobs {
+					require.Equal(t, pngHeader, data)
+					requireBodyMatchAttachment(t, recorder.Body, AttachmentResponse{
+						ID:          3,
+						URL:         "/media/" + key,
+						FileName:    "cover.png",
+						ContentType: "image/png",
+						Size:        int64(len(pngHeader)),
+						CreatedAt:   "2024-05-01 12:00:00",
+					})
+				}
+			},
+		},
+		{
+			name:     "negative_CreateAttachment_TooLarge",
+			fileName: "cover.png",
+			file:     append(pngHeader, make([]byte, testAttachmentMaxSize)...),
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+				querier.EXPECT().
+					CreateAttachment(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errAttachmentTooLarge.Error()})
+				require.Empty(t, blobs.blobs)
+			},
+		},
+		{
+			name:     "negative_CreateAttachment_UnsupportedType",
+			fileName: "cover.png",
+			file:     []byte("#!/bin/sh\necho pretending to be an image\n"),
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+				querier.EXPECT().
+					CreateAttachment(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore) {
+				require.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errAttachmentType.Error()})
+				require.Empty(t, blobs.blobs)
+			},
+		},
+		{
+			name: "negative_CreateAttachment_MissingFile",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+				querier.EXPECT().
+					CreateAttachment(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "negative_CreateAttachment_PostNotFound",
+			fileName: "cover.png",
+			file:     pngHeader,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(db.Post{}, sql.ErrNoRows)
+				querier.EXPECT().
+					CreateAttachment(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "negative_CreateAttachment_PostDeletedMeanwhile",
+			fileName: "cover.png",
+			file:     pngHeader,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+				querier.EXPECT().
+					CreateAttachment(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Attachment{}, &pq.Error{Code: "23503"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Empty(t, blobs.blobs)
+			},
+		},
+		{
+			name:     "negative_CreateAttachment_StorageError",
+			fileName: "cover.png",
+			file:     pngHeader,
+			blobErr:  io.ErrClosedPipe,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+				querier.EXPECT().
+					CreateAttachment(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			blobs := server.blobs.(*testBlobStore)
+			blobs.err = testCase.blobErr
+			recorder := httptest.NewRecorder()
+
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			if len(testCase.fileName) > 0 {
+				part, err := writer.CreateFormFile(attachmentFormField, testCase.fileName)
+				require.NoError(t, err)
+				_, err = part.Write(testCase.file)
+				require.NoError(t, err)
+			}
+			require.NoError(t, writer.Close())
+
+			requestUrl := fmt.Sprintf("/posts/%d/attachments", randomPost.ID)
+			request, err := http.NewRequest(http.MethodPost, requestUrl, &body)
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", writer.FormDataContentType())
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder, blobs)
+		})
+	}
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	postID := int32(42)
+	attachment := db.Attachment{ID: 3, PostID: postID, BlobKey: "posts/42/cover.png"}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore)
+	}{
+		{
+			name: "positive_DeleteAttachment",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					DeleteAttachment(gomock.Any(), gomock.Eq(db.DeleteAttachmentParams{ID: attachment.ID, PostID: postID})).
+					Times(1).
+					Return(attachment, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, blobs.blobs)
+			},
+		},
+		{
+			name: "negative_DeleteAttachment_NotFound",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					DeleteAttachment(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Attachment{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Len(t, blobs.blobs, 1)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			blobs := server.blobs.(*testBlobStore)
+			blobs.blobs[attachment.BlobKey] = pngHeader
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/posts/%d/attachments/%d", postID, attachment.ID)
+			request, err := http.NewRequest(http.MethodDelete, requestUrl, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder, blobs)
+		})
+	}
+}
+
+func TestPurgePost_DeletesAttachmentBlobs(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	postID := int32(42)
+	attachments := []db.Attachment{
+		{ID: 1, PostID: postID, BlobKey: "posts/42/a.png"},
+		{ID: 2, PostID: postID, BlobKey: "posts/42/b.pdf"},
+	}
+
+	store := mockdb.NewMockStore(controller)
+	gomock.InOrder(
+		store.EXPECT().
+			GetAttachmentsByPostIds(gomock.Any(), gomock.Eq([]int32{postID})).
+			Times(1).
+			Return(attachments, nil),
+		store.EXPECT().
+			PurgePost(gomock.Any(), gomock.Eq(postID)).
+			Times(1).
+			Return(int64(1), nil),
+	)
+	server := newTestServer(store)
+	blobs := server.blobs.(*testBlobStore)
+	for _, attachment := range attachments {
+		blobs.blobs[attachment.BlobKey] = pngHeader
+	}
+	blobs.blobs["posts/43/c.png"] = pngHeader
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/posts/%d?hard=true", postID), nil)
+	require.NoError(t, err)
+	request.Header.Set(adminTokenHeader, testAdminToken)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, map[string][]byte{"posts/43/c.png": pngHeader}, blobs.blobs)
+}
+
+func requireBodyMatchAttachment(t *testing.T, body *bytes.Buffer, expected AttachmentResponse) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var actual AttachmentResponse
+	err = json.Unmarshal(data, &actual)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
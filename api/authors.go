@@ -0,0 +1,335 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+)
+
+var (
+	errAuthorNotFound   = errors.New("author does not exist")
+	errAuthorEmailTaken = errors.New("an author with this email already exists")
+	errAuthorHasPosts   = errors.New("author still has posts")
+)
+
+type authorRequestBody struct {
+	Name  string `json:"name" binding:"required,max=200"`
+	Email string `json:"email" binding:"required,email,max=320"`
+	Bio   string `json:"bio" binding:"max=2000"`
+}
+
+type listAuthorsRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+type AuthorResponse struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Email     string `json:"email,omitempty"`
+	Bio       string `json:"bio"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+type ListAuthorsResponse struct {
+	Items []AuthorResponse `json:"items"`
+}
+
+// PostAuthorResponse is the compact author embedded into posts
+type PostAuthorResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// @Summary Create an author
+// @Tags Author
+// @Description Create an author that posts can be attributed to
+// @ID create-author
+// @Accept json
+// @Produce json
+// @Param input body authorRequestBody true "author entity related data"
+// @Success 200 {object} AuthorResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /authors [post]
+func (s *Server) createAuthor(context *gin.Context) {
+	var request authorRequestBody
+
+	if err := context.ShouldBindJSON(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	author, err := s.store.CreateAuthor(context, db.CreateAuthorParams{
+		Name:  request.Name,
+		Email: request.Email,
+		Bio:   request.Bio,
+	})
+	if err != nil {
+		respondAuthorError(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, mapToAuthorResponse(author))
+}
+
+// @Summary Get authors
+// @Tags Author
+// @Description Get a page of authors ordered by id. Their emails are shown to admins only
+// @ID get-authors
+// @Produce json
+// @Param limit query int false "maximum number of authors (1-100, defaults to 20)"
+// @Param offset query int false "number of authors to skip"
+// @Param X-Admin-Token header string false "admin token"
+// @Success 200 {object} ListAuthorsResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /authors [get]
+func (s *Server) getAuthors(context *gin.Context) {
+	var request listAuthorsRequest
+
+	if err := context.ShouldBindQuery(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if request.Limit == 0 {
+		request.Limit = defaultPageSize
+	}
+
+	authors, err := s.store.ListAuthors(context, db.ListAuthorsParams{
+		PageSize:   int32(request.Limit),
+		PageOffset: int32(request.Offset),
+	})
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := ListAuthorsResponse{Items: make([]AuthorResponse, 0, len(authors))}
+	for _, author := range authors {
+		response.Items = append(response.Items, s.authorResponse(context, author))
+	}
+	context.JSON(http.StatusOK, response)
+}
+
+// @Summary Get author by id
+// @Tags Author
+// @Description Get a specific author by the specified id. The email is shown to admins only
+// @ID get-author-by-id
+// @Produce json
+// @Param id path string true "the specific author id"
+// @Param X-Admin-Token header string false "admin token"
+// @Success 200 {object} AuthorResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /authors/{id} [get]
+func (s *Server) getAuthor(context *gin.Context) {
+	var request getPostRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	author, err := s.store.GetAuthorById(context, int32(request.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			context.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	context.JSON(http.StatusOK, s.authorResponse(context, author))
+}
+
+// @Summary Update author by id
+// @Tags Author
+// @Description Replace the name, email and bio of a specific author
+// @ID update-author-by-id
+// @Accept json
+// @Produce json
+// @Param id path string true "the specific author id"
+// @Param input body authorRequestBody true "author entity related data"
+// @Success 200 {object} AuthorResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /authors/{id} [put]
+func (s *Server) updateAuthor(context *gin.Context) {
+	var request getPostRequest
+	var requestBody authorRequestBody
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := context.ShouldBindJSON(&requestBody); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	author, err := s.store.UpdateAuthor(context, db.UpdateAuthorParams{
+		ID:    int32(request.ID),
+		Name:  requestBody.Name,
+		Email: requestBody.Email,
+		Bio:   requestBody.Bio,
+	})
+	if err != nil {
+		respondAuthorError(context, err)
+		return
+	}
+
+	context.JSON(http.StatusOK, mapToAuthorResponse(author))
+}
+
+// @Summary Delete author by id
+// @Tags Author
+// @Description Delete a specific author. Authors are kept while any post, trashed ones included, is attributed to them
+// @ID delete-author-by-id
+// @Produce json
+// @Param id path string true "the specific author id"
+// @Success 200
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /authors/{id} [delete]
+func (s *Server) deleteAuthor(context *gin.Context) {
+	var request getPostRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	deleted, err := s.store.DeleteAuthor(context, int32(request.ID))
+	if err != nil {
+		respondAuthorError(context, err)
+		return
+	}
+	if deleted == 0 {
+		context.JSON(http.StatusNotFound, errorResponse(sql.ErrNoRows))
+		return
+	}
+	context.Status(http.StatusOK)
+}
+
+// @Summary Get author posts
+// @Tags Author
+// @Description Get a page of the posts of a specific author, filtered and sorted like GET /posts
+// @ID get-author-posts
+// @Produce json
+// @Param id path string true "the specific author id"
+// @Param limit query int false "maximum number of posts in the page (1-100, defaults to 20)"
+// @Param cursor query string false "opaque cursor returned as nextCursor by the previous page"
+// @Param sort query string false "sort order, prefix with - for descending" Enums(id, -id, created_at, -created_at, updated_at, -updated_at, title, -title, likes, -likes)
+// @Param created_after query string false "only posts created after the RFC 3339 timestamp"
+// @Param created_before query string false "only posts created before the RFC 3339 timestamp"
+// @Param updated_since query string false "only posts updated at or after the RFC 3339 timestamp"
+// @Param title_contains query string false "case-insensitive substring of the title"
+// @Param status query string false "lifecycle status of the posts, defaults to published. Other statuses need the admin token" Enums(draft, published, archived)
+// @Param tag query []string false "only posts with these tags, repeat the parameter for several" collectionFormat(multi)
+// @Param tag_match query string false "whether posts need any or all of the tags, defaults to any" Enums(any, all)
+// @Param X-Admin-Token header string false "admin token"
+// @Success 200 {object} ListPostsResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 403 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /authors/{id}/posts [get]
+func (s *Server) getAuthorPosts(context *gin.Context) {
+	var request getPostRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	author, err := s.store.GetAuthorById(context, int32(request.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			context.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	s.listPosts(context, postsScope{authorID: sql.NullInt32{Int32: author.ID, Valid: true}})
+}
+
+// respondAuthorError answers with the status matching an error returned from an author query.
+func respondAuthorError(context *gin.Context, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		context.JSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+	var pqError *pq.Error
+	if errors.As(err, &pqError) {
+		switch pqError.Code.Name() {
+		case "unique_violation":
+			context.JSON(http.StatusConflict, errorResponse(errAuthorEmailTaken))
+			return
+		case "foreign_key_violation":
+			context.JSON(http.StatusConflict, errorResponse(errAuthorHasPosts))
+			return
+		}
+	}
+	context.JSON(http.StatusInternalServerError, errorResponse(err))
+}
+
+// loadAuthors fetches the authors with the given ids in a single query, skipping the query when there are none.
+func (s *Server) loadAuthors(context *gin.Context, authorIDs ...sql.NullInt32) (map[int32]db.Author, error) {
+	ids := make([]int32, 0, len(authorIDs))
+	seen := make(map[int32]bool, len(authorIDs))
+	for _, id := range authorIDs {
+		if id.Valid && !seen[id.Int32] {
+			seen[id.Int32] = true
+			ids = append(ids, id.Int32)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	authors, err := s.store.GetAuthorsByIds(context, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	authorsById := make(map[int32]db.Author, len(authors))
+	for _, author := range authors {
+		authorsById[author.ID] = author
+	}
+	return authorsById, nil
+}
+
+// authorResponse maps the author for the reader of a public route, the email is shown to admins only.
+func (s *Server) authorResponse(context *gin.Context, author db.Author) AuthorResponse {
+	response := mapToAuthorResponse(author)
+	if !s.isAdmin(context) {
+		response.Email = ""
+	}
+	return response
+}
+
+func mapToAuthorResponse(author db.Author) AuthorResponse {
+	return AuthorResponse{
+		ID:        int(author.ID),
+		Name:      author.Name,
+		Email:     author.Email,
+		Bio:       author.Bio,
+		CreatedAt: author.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt: author.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
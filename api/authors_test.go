@@ -0,0 +1,576 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"promova-test-task/util"
+	"testing"
+)
+
+func TestCreateAuthor(t *testing.T) {
+	randomAuthor := generateRandomAuthor()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "positive_CreateAuthor",
+			body: gin.H{
+				"name":  randomAuthor.Name,
+				"email": randomAuthor.Email,
+				"bio":   randomAuthor.Bio,
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.CreateAuthorParams{
+					Name:  randomAuthor.Name,
+					Email: randomAuthor.Email,
+					Bio:   randomAuthor.Bio,
+				}
+				querier.EXPECT().
+					CreateAuthor(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(randomAuthor, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAuthor(t, recorder.Body, mapToAuthorResponse(randomAuthor))
+			},
+		},
+		{
+			name: "negative_CreateAuthor_EmailTaken",
+			body: gin.H{
+				"name":  randomAuthor.Name,
+				"email": randomAuthor.Email,
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					CreateAuthor(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Author{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errAuthorEmailTaken.Error()})
+			},
+		},
+		{
+			name: "negative_CreateAuthor_InvalidEmail",
+			body: gin.H{
+				"name":  randomAuthor.Name,
+				"email": "not an email",
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					CreateAuthor(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "negative_CreateAuthor_InternalError",
+			body: gin.H{
+				"name":  randomAuthor.Name,
+				"email": randomAuthor.Email,
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					CreateAuthor(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Author{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/authors", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetAuthors(t *testing.T) {
+	randomAuthor := generateRandomAuthor()
+	publicAuthor := mapToAuthorResponse(randomAuthor)
+	publicAuthor.Email = ""
+
+	testCases := []struct {
+		name          string
+		query         string
+		adminToken    string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "positive_GetAuthors",
+			query: "?limit=5&offset=10",
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListAuthorsParams{PageSize: 5, PageOffset: 10}
+				querier.EXPECT().
+					ListAuthors(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Author{randomAuthor}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var actual ListAuthorsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actual))
+				require.Equal(t, ListAuthorsResponse{Items: []AuthorResponse{publicAuthor}}, actual)
+				require.NotContains(t, recorder.Body.String(), randomAuthor.Email)
+			},
+		},
+		{
+			name:       "positive_GetAuthors_EmailsForAdmin",
+			adminToken: testAdminToken,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListAuthors(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.Author{randomAuthor}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var actual ListAuthorsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actual))
+				require.Equal(t, ListAuthorsResponse{Items: []AuthorResponse{mapToAuthorResponse(randomAuthor)}}, actual)
+			},
+		},
+		{
+			name:  "negative_GetAuthors_InvalidLimit",
+			query: "?limit=1000",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListAuthors(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/authors"+testCase.query, nil)
+			require.NoError(t, err)
+			if len(testCase.adminToken) > 0 {
+				request.Header.Set(adminTokenHeader, testCase.adminToken)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetAuthorById(t *testing.T) {
+	randomAuthor := generateRandomAuthor()
+	publicAuthor := mapToAuthorResponse(randomAuthor)
+	publicAuthor.Email = ""
+
+	testCases := []struct {
+		name          string
+		adminToken    string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "positive_GetAuthorById",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetAuthorById(gomock.Any(), gomock.Eq(randomAuthor.ID)).
+					Times(1).
+					Return(randomAuthor, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), randomAuthor.Email)
+				requireBodyMatchAuthor(t, recorder.Body, publicAuthor)
+			},
+		},
+		{
+			name:       "positive_GetAuthorById_EmailForAdmin",
+			adminToken: testAdminToken,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetAuthorById(gomock.Any(), gomock.Eq(randomAuthor.ID)).
+					Times(1).
+					Return(randomAuthor, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAuthor(t, recorder.Body, mapToAuthorResponse(randomAuthor))
+			},
+		},
+		{
+			name:       "positive_GetAuthorById_WrongAdminToken",
+			adminToken: "wrong",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetAuthorById(gomock.Any(), gomock.Eq(randomAuthor.ID)).
+					Times(1).
+					Return(randomAuthor, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAuthor(t, recorder.Body, publicAuthor)
+			},
+		},
+		{
+			name: "negative_GetAuthorById_NotFound",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetAuthorById(gomock.Any(), gomock.Eq(randomAuthor.ID)).
+					Times(1).
+					Return(db.Author{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/authors/%d", randomAuthor.ID)
+			request, err := http.NewRequest(http.MethodGet, requestUrl, nil)
+			require.NoError(t, err)
+			if len(testCase.adminToken) > 0 {
+				request.Header.Set(adminTokenHeader, testCase.adminToken)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateAuthor(t *testing.T) {
+	randomAuthor := generateRandomAuthor()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "positive_UpdateAuthor",
+			body: gin.H{
+				"name":  "New name",
+				"email": randomAuthor.Email,
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.UpdateAuthorParams{
+					ID:    randomAuthor.ID,
+					Name:  "New name",
+					Email: randomAuthor.Email,
+				}
+				updatedAuthor := randomAuthor
+				updatedAuthor.Name = arg.Name
+				updatedAuthor.Bio = ""
+
+				querier.EXPECT().
+					UpdateAuthor(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(updatedAuthor, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				expected := mapToAuthorResponse(randomAuthor)
+				expected.Name = "New name"
+				expected.Bio = ""
+				requireBodyMatchAuthor(t, recorder.Body, expected)
+			},
+		},
+		{
+			name: "negative_UpdateAuthor_NotFound",
+			body: gin.H{
+				"name":  randomAuthor.Name,
+				"email": randomAuthor.Email,
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					UpdateAuthor(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Author{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "negative_UpdateAuthor_MissingName",
+			body: gin.H{
+				"email": randomAuthor.Email,
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					UpdateAuthor(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			requestUrl := fmt.Sprintf("/authors/%d", randomAuthor.ID)
+			request, err := http.NewRequest(http.MethodPut, requestUrl, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteAuthor(t *testing.T) {
+	randomAuthor := generateRandomAuthor()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "positive_DeleteAuthor",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					DeleteAuthor(gomock.Any(), gomock.Eq(randomAuthor.ID)).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "negative_DeleteAuthor_NotFound",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					DeleteAuthor(gomock.Any(), gomock.Eq(randomAuthor.ID)).
+					Times(1).
+					Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "negative_DeleteAuthor_HasPosts",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					DeleteAuthor(gomock.Any(), gomock.Eq(randomAuthor.ID)).
+					Times(1).
+					Return(int64(0), &pq.Error{Code: "23503"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errAuthorHasPosts.Error()})
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/authors/%d", randomAuthor.ID)
+			request, err := http.NewRequest(http.MethodDelete, requestUrl, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetAuthorPosts(t *testing.T) {
+	randomAuthor := generateRandomAuthor()
+	randomPost := generateRandomPost()
+	randomPost.AuthorID = sql.NullInt32{Int32: randomAuthor.ID, Valid: true}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "positive_GetAuthorPosts",
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListPostsByIDParams{
+					Status:   db.PostStatusPublished,
+					AuthorID: randomPost.AuthorID,
+					PageSize: defaultPageSize + 1,
+				}
+				querier.EXPECT().
+					GetAuthorById(gomock.Any(), gomock.Eq(randomAuthor.ID)).
+					Times(1).
+					Return(randomAuthor, nil)
+				querier.EXPECT().
+					ListPostsByID(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Post{randomPost}, nil)
+				querier.EXPECT().
+					GetAuthorsByIds(gomock.Any(), gomock.Eq([]int32{randomAuthor.ID})).
+					Times(1).
+					Return([]db.Author{randomAuthor}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				expected := mapToPostResponse(randomPost)
+				expected.Author = &PostAuthorResponse{ID: int(randomAuthor.ID), Name: randomAuthor.Name}
+				requireBodyMatchPosts(t, recorder.Body, ListPostsResponse{Items: []PostResponse{expected}})
+			},
+		},
+		{
+			name: "negative_GetAuthorPosts_AuthorNotFound",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetAuthorById(gomock.Any(), gomock.Eq(randomAuthor.ID)).
+					Times(1).
+					Return(db.Author{}, sql.ErrNoRows)
+				querier.EXPECT().
+					ListPostsByID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "negative_GetAuthorPosts_AuthorsLoadFailed",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetAuthorById(gomock.Any(), gomock.Eq(randomAuthor.ID)).
+					Times(1).
+					Return(randomAuthor, nil)
+				querier.EXPECT().
+					ListPostsByID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.Post{randomPost}, nil)
+				querier.EXPECT().
+					GetAuthorsByIds(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/authors/%d/posts", randomAuthor.ID)
+			request, err := http.NewRequest(http.MethodGet, requestUrl, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func requireBodyMatchAuthor(t *testing.T, body *bytes.Buffer, expected AuthorResponse) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var actual AuthorResponse
+	err = json.Unmarshal(data, &actual)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func generateRandomAuthor() db.Author {
+	randomAuthor := util.GenerateRandomAuthor()
+	return db.Author{
+		ID:        randomAuthor.ID,
+		Name:      randomAuthor.Name,
+		Email:     randomAuthor.Email,
+		Bio:       randomAuthor.Bio,
+		CreatedAt: randomAuthor.CreatedAt,
+		UpdatedAt: randomAuthor.UpdatedAt,
+	}
+}
@@ -0,0 +1,256 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+)
+
+const (
+	batchCreate = "create"
+	batchUpdate = "update"
+)
+
+var errBatchPostMissing = errors.New("post is required to create or update")
+
+// batchPostsRequest is up to 1000 operations, run all or nothing when atomic
+type batchPostsRequest struct {
+	Atomic     bool                    `json:"atomic"`
+	Operations []batchOperationRequest `json:"operations" binding:"required,min=1,max=1000,dive"`
+}
+
+// batchOperationRequest is a create, update or delete of a post. Post is the createPostRequest of a create
+// or the updatePostRequestBody of an update, and version the version the post must still have to be updated or deleted
+type batchOperationRequest struct {
+	Op      string          `json:"op" binding:"required,oneof=create update delete"`
+	ID      int             `json:"id" binding:"required_unless=Op create,omitempty,min=1"`
+	Version *int            `json:"version" binding:"omitempty,min=1"`
+	Post    json.RawMessage `json:"post" swaggertype:"object"`
+}
+
+type BatchPostsResponse struct {
+	Results []BatchResultResponse `json:"results"`
+}
+
+// BatchResultResponse is the outcome of an operation, with the status and error the single operation would answer with
+type BatchResultResponse struct {
+	Status int           `json:"status"`
+	Post   *PostResponse `json:"post,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// @Summary Create, update and delete posts in a batch
+// @Tags Post
+// @Description Run up to 1000 operations, creates with the body of POST /posts, updates with the body of PUT /posts/{id}
+// @Description and deletes, reporting the status and error of each one. Creates are inserted together before the updates
+// @Description and deletes run in order. An atomic batch is all or nothing: the first failing operation rolls back
+// @Description the others, which report 424, and answers with its status. Otherwise every operation that can succeed does,
+// @Description and the batch answers with 207 when some failed. Malformed operations reject the whole batch
+// @ID batch-posts
+// @Accept json
+// @Produce json
+// @Param input body batchPostsRequest true "the operations"
+// @Success 200 {object} BatchPostsResponse
+// @Success 207 {object} BatchPostsResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts:batch [post]
+func (s *Server) batchPosts(context *gin.Context) {
+	var request batchPostsRequest
+
+	if err := context.ShouldBindJSON(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	operations := make([]db.PostOperation, len(request.Operations))
+	failures := make([]error, len(request.Operations))
+	for i, operation := range request.Operations {
+		operations[i], failures[i] = s.toPostOperation(operation)
+	}
+	if err := s.checkReferences(context, operations, failures); err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	valid := make([]db.PostOperation, 0, len(operations))
+	indexes := make([]int, 0, len(operations))
+	for i, operation := range operations {
+		if failures[i] == nil {
+			valid = append(valid, operation)
+			indexes = append(indexes, i)
+		}
+	}
+
+	if request.Atomic && len(valid) < len(operations) {
+		valid = nil
+		for i := range failures {
+			if failures[i] == nil {
+				failures[i] = db.ErrBatchAborted
+			}
+		}
+	}
+	posts := make([]*db.Post, len(operations))
+	if len(valid) > 0 {
+		results, err := s.store.BatchPostsTx(context, valid, request.Atomic)
+		if err != nil {
+			context.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		for n, result := range results {
+			i := indexes[n]
+			failures[i] = result.Err
+			if result.Err == nil && operations[i].Delete == nil {
+				post := result.Post
+				posts[i] = &post
+			}
+		}
+	}
+
+	s.respondWithBatch(context, request.Atomic, posts, failures)
+}
+
+// toPostOperation checks an operation of a batch and turns it into the change of a post.
+func (s *Server) toPostOperation(request batchOperationRequest) (db.PostOperation, error) {
+	switch request.Op {
+	case batchCreate:
+		var createRequest createPostRequest
+		if err := decodeBatchPost(request.Post, &createRequest); err != nil {
+			return db.PostOperation{}, statusError{status: http.StatusBadRequest, err: err}
+		}
+		arg, err := s.toCreatePostParams(createRequest)
+		if err != nil {
+			return db.PostOperation{}, err
+		}
+		return db.PostOperation{Create: &arg}, nil
+	case batchUpdate:
+		var requestBody updatePostRequestBody
+		if err := decodeBatchPost(request.Post, &requestBody); err != nil {
+			return db.PostOperation{}, statusError{status: http.StatusBadRequest, err: err}
+		}
+		return db.PostOperation{ID: int32(request.ID), Update: func(post db.Post) (db.UpdatePostTxParams, error) {
+			version, ok := batchVersion(post, request.Version)
+			if !ok {
+				return db.UpdatePostTxParams{}, statusError{status: http.StatusPreconditionFailed, err: errPreconditionFailed}
+			}
+			return s.updatePostParams(post, requestBody.replacement(), version)
+		}}, nil
+	default: // delete
+		return db.PostOperation{ID: int32(request.ID), Delete: func(post db.Post) error {
+			if _, ok := batchVersion(post, request.Version); !ok {
+				return statusError{status: http.StatusPreconditionFailed, err: errPreconditionFailed}
+			}
+			return nil
+		}}, nil
+	}
+}
+
+// decodeBatchPost decodes the post of an operation and checks it against the rules of its single operation.
+func decodeBatchPost(data json.RawMessage, obj interface{}) error {
+	if len(data) == 0 || string(data) == "null" {
+		return errBatchPostMissing
+	}
+	if err := json.Unmarshal(data, obj); err != nil {
+		return err
+	}
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// batchVersion checks the version an operation expects against the post as it was just read, the way expectedVersion
+// checks If-Match. Without a version the write is unconditional.
+func batchVersion(post db.Post, version *int) (sql.NullInt32, bool) {
+	if version == nil {
+		return sql.NullInt32{}, true
+	}
+	if int32(*version) != post.Version {
+		return sql.NullInt32{}, false
+	}
+	return sql.NullInt32{Int32: post.Version, Valid: true}, true
+}
+
+// checkReferences fails the creates referring to an author or a category that does not exist,
+// which would otherwise have the creates of the batch inserted one at a time to tell the failing ones.
+func (s *Server) checkReferences(context *gin.Context, operations []db.PostOperation, failures []error) error {
+	var authorIDs, categoryIDs []sql.NullInt32
+	for i, operation := range operations {
+		if operation.Create != nil && failures[i] == nil {
+			authorIDs = append(authorIDs, operation.Create.AuthorID)
+			categoryIDs = append(categoryIDs, operation.Create.CategoryID)
+		}
+	}
+
+	authors, err := s.loadAuthors(context, authorIDs...)
+	if err != nil {
+		return err
+	}
+	categories, err := s.loadBreadcrumbs(context, categoryIDs...)
+	if err != nil {
+		return err
+	}
+
+	for i, operation := range operations {
+		if operation.Create == nil || failures[i] != nil {
+			continue
+		}
+		if id := operation.Create.AuthorID; id.Valid {
+			if _, ok := authors[id.Int32]; !ok {
+				failures[i] = statusError{status: http.StatusBadRequest, err: errAuthorNotFound}
+				continue
+			}
+		}
+		if id := operation.Create.CategoryID; id.Valid {
+			if _, ok := categories[id.Int32]; !ok {
+				failures[i] = statusError{status: http.StatusBadRequest, err: errCategoryNotFound}
+			}
+		}
+	}
+	return nil
+}
+
+// respondWithBatch answers with the outcome of every operation, the posts with their relations embedded.
+// An atomic batch that failed answers with the status of the first failed operation.
+func (s *Server) respondWithBatch(context *gin.Context, atomic bool, posts []*db.Post, failures []error) {
+	var written []db.Post
+	for _, post := range posts {
+		if post != nil {
+			written = append(written, *post)
+		}
+	}
+	postsResponse, err := s.postsResponse(context, written)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	status := http.StatusOK
+	results := make([]BatchResultResponse, len(failures))
+	for i, failure := range failures {
+		if failure == nil {
+			results[i].Status = http.StatusOK
+			if posts[i] != nil {
+				results[i].Post = &postsResponse[0]
+				postsResponse = postsResponse[1:]
+			}
+			continue
+		}
+		if errors.Is(failure, db.ErrBatchAborted) {
+			results[i] = BatchResultResponse{Status: http.StatusFailedDependency, Error: failure.Error()}
+			continue
+		}
+
+		failureStatus, err := txErrorStatus(failure)
+		results[i] = BatchResultResponse{Status: failureStatus, Error: err.Error()}
+		if status == http.StatusOK {
+			status = http.StatusMultiStatus
+			if atomic {
+				status = failureStatus
+			}
+		}
+	}
+
+	context.JSON(status, BatchPostsResponse{Results: results})
+}
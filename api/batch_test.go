@@ -0,0 +1,311 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"testing"
+)
+
+func TestBatchPosts(t *testing.T) {
+	currentPost := generateRandomPost()
+	createdPost := generateRandomPost()
+	createBody := gin.H{"title": createdPost.Title, "content": createdPost.Content, "tags": []string{"Go"}}
+	updateBody := gin.H{"title": "Updated title", "content": currentPost.Content}
+
+	tooMany := make([]gin.H, 1001)
+	for i := range tooMany {
+		tooMany[i] = gin.H{"op": "delete", "id": 1}
+	}
+
+	testCases := []struct {
+		name          string
+		path          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "positive_BatchPosts_Atomic",
+			body: gin.H{
+				"atomic": true,
+				"operations": []gin.H{
+					{"op": "create", "post": createBody},
+					{"op": "update", "id": currentPost.ID, "version": currentPost.Version, "post": updateBody},
+				},
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					BatchPostsTx(gomock.Any(), gomock.Len(2), gomock.Eq(true)).
+					Times(1).
+					DoAndReturn(batchPostsTx([]db.Post{currentPost}, createdPost))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				response := requireBodyMatchBatch(t, recorder.Body, http.StatusOK, http.StatusOK)
+				require.Equal(t, createdPost.Title, response.Results[0].Post.Title)
+				require.Equal(t, "Updated title", response.Results[1].Post.Title)
+			},
+		},
+		{
+			name: "positive_BatchPosts_BestEffort",
+			body: gin.H{
+				"operations": []gin.H{
+					{"op": "create", "post": createBody},
+					{"op": "create", "post": gin.H{"title": createdPost.Title}},
+					{"op": "update", "id": currentPost.ID, "version": currentPost.Version + 1, "post": updateBody},
+					{"op": "delete", "id": currentPost.ID + 1},
+					{"op": "delete", "id": currentPost.ID},
+				},
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					BatchPostsTx(gomock.Any(), gomock.Len(4), gomock.Eq(false)).
+					Times(1).
+					DoAndReturn(batchPostsTx([]db.Post{currentPost}, createdPost))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusMultiStatus, recorder.Code)
+
+				response := requireBodyMatchBatch(t, recorder.Body,
+					http.StatusOK, http.StatusBadRequest, http.StatusPreconditionFailed, http.StatusNotFound, http.StatusOK)
+				require.NotNil(t, response.Results[0].Post)
+				require.Equal(t, errPreconditionFailed.Error(), response.Results[2].Error)
+				require.Nil(t, response.Results[4].Post)
+			},
+		},
+		{
+			name: "negative_BatchPosts_AtomicInvalidOperation",
+			body: gin.H{
+				"atomic": true,
+				"operations": []gin.H{
+					{"op": "update", "id": currentPost.ID, "post": updateBody},
+					{"op": "create", "post": gin.H{"title": createdPost.Title, "content": createdPost.Content, "contentFormat": "rst"}},
+				},
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					BatchPostsTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				response := requireBodyMatchBatch(t, recorder.Body, http.StatusFailedDependency, http.StatusBadRequest)
+				require.Equal(t, db.ErrBatchAborted.Error(), response.Results[0].Error)
+			},
+		},
+		{
+			name: "negative_BatchPosts_AtomicRolledBack",
+			body: gin.H{
+				"atomic": true,
+				"operations": []gin.H{
+					{"op": "create", "post": createBody},
+					{"op": "delete", "id": currentPost.ID + 1},
+				},
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					BatchPostsTx(gomock.Any(), gomock.Len(2), gomock.Eq(true)).
+					Times(1).
+					DoAndReturn(batchPostsTx([]db.Post{currentPost}, createdPost))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+
+				response := requireBodyMatchBatch(t, recorder.Body, http.StatusFailedDependency, http.StatusNotFound)
+				require.Nil(t, response.Results[0].Post)
+			},
+		},
+		{
+			name: "negative_BatchPosts_AtomicCreateFails",
+			body: gin.H{
+				"atomic": true,
+				"operations": []gin.H{
+					{"op": "create", "post": createBody},
+					{"op": "create", "post": createBody},
+				},
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				// the category of the second create was deleted after the references were checked
+				querier.EXPECT().
+					BatchPostsTx(gomock.Any(), gomock.Len(2), gomock.Eq(true)).
+					Times(1).
+					Return([]db.PostOperationResult{
+						{Err: db.ErrBatchAborted},
+						{Err: &pq.Error{Code: "23503", Constraint: "posts_category_id_fkey"}},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				response := requireBodyMatchBatch(t, recorder.Body, http.StatusFailedDependency, http.StatusBadRequest)
+				require.Equal(t, errCategoryNotFound.Error(), response.Results[1].Error)
+			},
+		},
+		{
+			name: "negative_BatchPosts_UnknownAuthor",
+			body: gin.H{
+				"operations": []gin.H{
+					{"op": "create", "post": gin.H{"title": createdPost.Title, "content": createdPost.Content, "authorId": 7}},
+				},
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetAuthorsByIds(gomock.Any(), gomock.Eq([]int32{7})).
+					Times(1).
+					Return([]db.Author{}, nil)
+				querier.EXPECT().
+					BatchPostsTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusMultiStatus, recorder.Code)
+
+				response := requireBodyMatchBatch(t, recorder.Body, http.StatusBadRequest)
+				require.Equal(t, errAuthorNotFound.Error(), response.Results[0].Error)
+			},
+		},
+		{
+			name: "negative_BatchPosts_MissingID",
+			body: gin.H{
+				"operations": []gin.H{
+					{"op": "update", "post": updateBody},
+				},
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					BatchPostsTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "negative_BatchPosts_TooManyOperations",
+			body: gin.H{"operations": tooMany},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					BatchPostsTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "negative_BatchPosts_UnknownMethod",
+			path: "/posts:merge",
+			body: gin.H{"operations": []gin.H{{"op": "delete", "id": currentPost.ID}}},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					BatchPostsTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errUnknownMethod.Error()})
+			},
+		},
+		{
+			name: "negative_BatchPosts_InternalError",
+			body: gin.H{"atomic": true, "operations": []gin.H{{"op": "delete", "id": currentPost.ID}}},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					BatchPostsTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			path := testCase.path
+			if len(path) == 0 {
+				path = "/posts:batch"
+			}
+			request, err := http.NewRequest(http.MethodPost, path, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+// batchPostsTx stubs the batch by creating the created posts in turn and running the updates and deletes
+// of the handler against the existing posts, rolling back an atomic batch like the store does.
+func batchPostsTx(existing []db.Post, created ...db.Post) func(context.Context, []db.PostOperation, bool) ([]db.PostOperationResult, error) {
+	return func(_ context.Context, operations []db.PostOperation, atomic bool) ([]db.PostOperationResult, error) {
+		posts := make(map[int32]db.Post, len(existing))
+		for _, post := range existing {
+			posts[post.ID] = post
+		}
+
+		results := make([]db.PostOperationResult, len(operations))
+		failed := false
+		for i, operation := range operations {
+			post, ok := posts[operation.ID]
+			switch {
+			case operation.Create != nil:
+				results[i].Post, created = created[0], created[1:]
+			case !ok:
+				results[i].Err = sql.ErrNoRows
+			case operation.Update != nil:
+				arg, err := operation.Update(post)
+				post.Title, post.Content = arg.Title, arg.Content
+				results[i] = db.PostOperationResult{Post: post, Err: err}
+			default:
+				results[i].Err = operation.Delete(post)
+			}
+			failed = failed || results[i].Err != nil
+		}
+
+		if atomic && failed {
+			for i := range results {
+				if results[i].Err == nil {
+					results[i] = db.PostOperationResult{Err: db.ErrBatchAborted}
+				}
+			}
+		}
+		return results, nil
+	}
+}
+
+func requireBodyMatchBatch(t *testing.T, body *bytes.Buffer, statuses ...int) BatchPostsResponse {
+	var response BatchPostsResponse
+	require.NoError(t, json.Unmarshal(body.Bytes(), &response))
+
+	actual := make([]int, 0, len(response.Results))
+	for _, result := range response.Results {
+		actual = append(actual, result.Status)
+	}
+	require.Equal(t, statuses, actual)
+	return response
+}
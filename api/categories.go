@@ -0,0 +1,221 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+)
+
+var (
+	errCategoryNotFound       = errors.New("category does not exist")
+	errParentCategoryNotFound = errors.New("parent category does not exist")
+	errCategoryExists         = errors.New("the parent category already has a category with this name")
+)
+
+type createCategoryRequest struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	ParentID *int   `json:"parentId" binding:"omitempty,min=1"`
+}
+
+type CategoryResponse struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	ParentID  *int   `json:"parentId,omitempty"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type CategoryNodeResponse struct {
+	ID       int                    `json:"id"`
+	Name     string                 `json:"name"`
+	Children []CategoryNodeResponse `json:"children"`
+}
+
+type CategoryTreeResponse struct {
+	Items []CategoryNodeResponse `json:"items"`
+}
+
+// BreadcrumbResponse is one category on the path from the root to the category of a post
+type BreadcrumbResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// @Summary Create a category
+// @Tags Category
+// @Description Create a top level category, or a subcategory when parentId is set
+// @ID create-category
+// @Accept json
+// @Produce json
+// @Param input body createCategoryRequest true "category entity related data"
+// @Success 200 {object} CategoryResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /categories [post]
+func (s *Server) createCategory(context *gin.Context) {
+	var request createCategoryRequest
+
+	if err := context.ShouldBindJSON(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.CreateCategoryParams{Name: request.Name}
+	if request.ParentID != nil {
+		arg.ParentID = sql.NullInt32{Int32: int32(*request.ParentID), Valid: true}
+	}
+
+	category, err := s.store.CreateCategory(context, arg)
+	if err != nil {
+		var pqError *pq.Error
+		if errors.As(err, &pqError) {
+			switch pqError.Code.Name() {
+			case "unique_violation":
+				context.JSON(http.StatusConflict, errorResponse(errCategoryExists))
+				return
+			case "foreign_key_violation":
+				context.JSON(http.StatusBadRequest, errorResponse(errParentCategoryNotFound))
+				return
+			}
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	context.JSON(http.StatusOK, mapToCategoryResponse(category))
+}
+
+// @Summary Get category tree
+// @Tags Category
+// @Description Get all categories nested under their parents, siblings ordered by name
+// @ID get-category-tree
+// @Produce json
+// @Success 200 {object} CategoryTreeResponse
+// @Failure 500 {object} ErrResponse
+// @Router /categories/tree [get]
+func (s *Server) getCategoryTree(context *gin.Context) {
+	categories, err := s.store.ListCategories(context)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	context.JSON(http.StatusOK, CategoryTreeResponse{Items: buildCategoryTree(categories)})
+}
+
+// @Summary Get category posts
+// @Tags Category
+// @Description Get a page of the posts of a category and all of its subcategories, filtered and sorted like GET /posts
+// @ID get-category-posts
+// @Produce json
+// @Param id path string true "the specific category id"
+// @Param limit query int false "maximum number of posts in the page (1-100, defaults to 20)"
+// @Param cursor query string false "opaque cursor returned as nextCursor by the previous page"
+// @Param sort query string false "sort order, prefix with - for descending" Enums(id, -id, created_at, -created_at, updated_at, -updated_at, title, -title, likes, -likes)
+// @Param created_after query string false "only posts created after the RFC 3339 timestamp"
+// @Param created_before query string false "only posts created before the RFC 3339 timestamp"
+// @Param updated_since query string false "only posts updated at or after the RFC 3339 timestamp"
+// @Param title_contains query string false "case-insensitive substring of the title"
+// @Param status query string false "lifecycle status of the posts, defaults to published. Other statuses need the admin token" Enums(draft, published, archived)
+// @Param tag query []string false "only posts with these tags, repeat the parameter for several" collectionFormat(multi)
+// @Param tag_match query string false "whether posts need any or all of the tags, defaults to any" Enums(any, all)
+// @Param X-Admin-Token header string false "admin token"
+// @Success 200 {object} ListPostsResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 403 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /categories/{id}/posts [get]
+func (s *Server) getCategoryPosts(context *gin.Context) {
+	var request getPostRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	category, err := s.store.GetCategoryById(context, int32(request.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			context.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	s.listPosts(context, postsScope{categoryID: sql.NullInt32{Int32: category.ID, Valid: true}})
+}
+
+// loadBreadcrumbs fetches the paths from the root to the given categories in a single query,
+// skipping the query when there are none.
+func (s *Server) loadBreadcrumbs(context *gin.Context, categoryIDs ...sql.NullInt32) (map[int32][]BreadcrumbResponse, error) {
+	ids := make([]int32, 0, len(categoryIDs))
+	seen := make(map[int32]bool, len(categoryIDs))
+	for _, id := range categoryIDs {
+		if id.Valid && !seen[id.Int32] {
+			seen[id.Int32] = true
+			ids = append(ids, id.Int32)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	rows, err := s.store.GetCategoryPaths(context, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	breadcrumbs := make(map[int32][]BreadcrumbResponse, len(ids))
+	for _, row := range rows {
+		breadcrumbs[row.CategoryID] = append(breadcrumbs[row.CategoryID], BreadcrumbResponse{
+			ID:   int(row.ID),
+			Name: row.Name,
+		})
+	}
+	return breadcrumbs, nil
+}
+
+// buildCategoryTree nests the categories under their parents, keeping the order of the list among siblings.
+func buildCategoryTree(categories []db.Category) []CategoryNodeResponse {
+	children := make(map[int32][]db.Category, len(categories))
+	var roots []db.Category
+	for _, category := range categories {
+		if category.ParentID.Valid {
+			children[category.ParentID.Int32] = append(children[category.ParentID.Int32], category)
+		} else {
+			roots = append(roots, category)
+		}
+	}
+
+	var build func(categories []db.Category) []CategoryNodeResponse
+	build = func(categories []db.Category) []CategoryNodeResponse {
+		nodes := make([]CategoryNodeResponse, 0, len(categories))
+		for _, category := range categories {
+			nodes = append(nodes, CategoryNodeResponse{
+				ID:       int(category.ID),
+				Name:     category.Name,
+				Children: build(children[category.ID]),
+			})
+		}
+		return nodes
+	}
+	return build(roots)
+}
+
+func mapToCategoryResponse(category db.Category) CategoryResponse {
+	categoryResponse := CategoryResponse{
+		ID:        int(category.ID),
+		Name:      category.Name,
+		CreatedAt: category.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if category.ParentID.Valid {
+		parentID := int(category.ParentID.Int32)
+		categoryResponse.ParentID = &parentID
+	}
+	return categoryResponse
+}
@@ -0,0 +1,265 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"testing"
+	"time"
+)
+
+func TestCreateCategory(t *testing.T) {
+	category := db.Category{
+		ID:        3,
+		ParentID:  sql.NullInt32{Int32: 1, Valid: true},
+		Name:      "Releases",
+		CreatedAt: time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "positive_CreateCategory",
+			body: gin.H{"name": "Releases", "parentId": 1},
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.CreateCategoryParams{ParentID: category.ParentID, Name: category.Name}
+				querier.EXPECT().
+					CreateCategory(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(category, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var actual CategoryResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actual))
+				require.Equal(t, mapToCategoryResponse(category), actual)
+			},
+		},
+		{
+			name: "negative_CreateCategory_Exists",
+			body: gin.H{"name": "Releases", "parentId": 1},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					CreateCategory(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Category{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errCategoryExists.Error()})
+			},
+		},
+		{
+			name: "negative_CreateCategory_ParentNotFound",
+			body: gin.H{"name": "Releases", "parentId": 1},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					CreateCategory(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Category{}, &pq.Error{Code: "23503"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errParentCategoryNotFound.Error()})
+			},
+		},
+		{
+			name: "negative_CreateCategory_MissingName",
+			body: gin.H{"parentId": 1},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					CreateCategory(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/categories", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetCategoryTree(t *testing.T) {
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "positive_GetCategoryTree",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListCategories(gomock.Any()).
+					Times(1).
+					Return([]db.Category{
+						{ID: 1, Name: "News"},
+						{ID: 2, Name: "Product", ParentID: sql.NullInt32{Int32: 1, Valid: true}},
+						{ID: 3, Name: "Releases", ParentID: sql.NullInt32{Int32: 2, Valid: true}},
+						{ID: 4, Name: "Tutorials"},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var actual CategoryTreeResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actual))
+				require.Equal(t, CategoryTreeResponse{Items: []CategoryNodeResponse{
+					{ID: 1, Name: "News", Children: []CategoryNodeResponse{
+						{ID: 2, Name: "Product", Children: []CategoryNodeResponse{
+							{ID: 3, Name: "Releases", Children: []CategoryNodeResponse{}},
+						}},
+					}},
+					{ID: 4, Name: "Tutorials", Children: []CategoryNodeResponse{}},
+				}}, actual)
+			},
+		},
+		{
+			name: "negative_GetCategoryTree_InternalError",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListCategories(gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/categories/tree", nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetCategoryPosts(t *testing.T) {
+	category := db.Category{ID: 2, Name: "Product", ParentID: sql.NullInt32{Int32: 1, Valid: true}}
+	randomPost := generateRandomPost()
+	randomPost.CategoryID = sql.NullInt32{Int32: 3, Valid: true}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "positive_GetCategoryPosts",
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListPostsByIDParams{
+					Status:     db.PostStatusPublished,
+					CategoryID: sql.NullInt32{Int32: category.ID, Valid: true},
+					PageSize:   defaultPageSize + 1,
+				}
+				querier.EXPECT().
+					GetCategoryById(gomock.Any(), gomock.Eq(category.ID)).
+					Times(1).
+					Return(category, nil)
+				querier.EXPECT().
+					ListPostsByID(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Post{randomPost}, nil)
+				querier.EXPECT().
+					GetCategoryPaths(gomock.Any(), gomock.Eq([]int32{3})).
+					Times(1).
+					Return([]db.GetCategoryPathsRow{
+						{CategoryID: 3, ID: 1, Name: "News"},
+						{CategoryID: 3, ID: 2, Name: "Product"},
+						{CategoryID: 3, ID: 3, Name: "Releases"},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				expected := mapToPostResponse(randomPost)
+				expected.Breadcrumbs = []BreadcrumbResponse{
+					{ID: 1, Name: "News"},
+					{ID: 2, Name: "Product"},
+					{ID: 3, Name: "Releases"},
+				}
+				requireBodyMatchPosts(t, recorder.Body, ListPostsResponse{Items: []PostResponse{expected}})
+			},
+		},
+		{
+			name: "negative_GetCategoryPosts_CategoryNotFound",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetCategoryById(gomock.Any(), gomock.Eq(category.ID)).
+					Times(1).
+					Return(db.Category{}, sql.ErrNoRows)
+				querier.EXPECT().
+					ListPostsByID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/categories/%d/posts", category.ID)
+			request, err := http.NewRequest(http.MethodGet, requestUrl, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,385 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+)
+
+const (
+	commentsViewTree = "tree"
+	commentsViewFlat = "flat"
+)
+
+var errParentCommentNotFound = errors.New("parent comment does not exist on this post")
+
+type createCommentRequest struct {
+	AuthorName string `json:"authorName" binding:"required,max=100"`
+	Body       string `json:"body" binding:"required,max=5000"`
+	ParentID   *int   `json:"parentId" binding:"omitempty,min=1"`
+}
+
+type listCommentsRequest struct {
+	View   string `form:"view" binding:"omitempty,oneof=tree flat"`
+	Status string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+type CommentResponse struct {
+	ID         int               `json:"id"`
+	PostID     int               `json:"postId"`
+	ParentID   *int              `json:"parentId,omitempty"`
+	AuthorName string            `json:"authorName"`
+	Body       string            `json:"body"`
+	Status     string            `json:"status"`
+	CreatedAt  string            `json:"createdAt"`
+	Depth      int               `json:"depth"`
+	Replies    []CommentResponse `json:"replies,omitempty"`
+}
+
+type ListCommentsResponse struct {
+	Items []CommentResponse `json:"items"`
+}
+
+// @Summary Comment on a post
+// @Tags Comment
+// @Description Add a comment to a published post, or a reply to one of its comments when parentId is set.
+// @Description New comments wait for moderation before they are shown
+// @ID create-comment
+// @Accept json
+// @Produce json
+// @Param id path string true "the specific post id"
+// @Param input body createCommentRequest true "comment entity related data"
+// @Success 200 {object} CommentResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/{id}/comments [post]
+func (s *Server) createComment(context *gin.Context) {
+	var request getPostRequest
+	var requestBody createCommentRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := context.ShouldBindJSON(&requestBody); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	post, ok := s.publicPost(context, int32(request.ID))
+	if !ok {
+		return
+	}
+
+	arg := db.CreateCommentParams{
+		PostID:     post.ID,
+		AuthorName: requestBody.AuthorName,
+		Body:       requestBody.Body,
+	}
+	if requestBody.ParentID != nil {
+		arg.ParentID = sql.NullInt32{Int32: int32(*requestBody.ParentID), Valid: true}
+	}
+
+	comment, err := s.store.CreateComment(context, arg)
+	if err != nil {
+		var pqError *pq.Error
+		if errors.As(err, &pqError) && pqError.Code.Name() == "foreign_key_violation" {
+			context.JSON(http.StatusBadRequest, errorResponse(errParentCommentNotFound))
+			return
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	context.JSON(http.StatusOK, mapToCommentResponse(comment))
+}
+
+// @Summary Get post comments
+// @Tags Comment
+// @Description Get a page of the comment threads of a published post, oldest thread first. The page is counted in top level
+// @Description comments, each coming with all of its replies, either nested under it or flattened in reading order.
+// @Description Only approved comments are shown unless an admin asks for another status. Pending and rejected comments
+// @Description are listed flat whatever the view, replies included, and the page is counted in comments
+// @ID get-comments
+// @Produce json
+// @Param id path string true "the specific post id"
+// @Param view query string false "nest replies under their comments or list them flat, defaults to tree" Enums(tree, flat)
+// @Param status query string false "moderation status of the comments, admins only" Enums(pending, approved, rejected)
+// @Param limit query int false "maximum number of threads (1-100, defaults to 20)"
+// @Param offset query int false "number of threads to skip"
+// @Param X-Admin-Token header string false "admin token"
+// @Success 200 {object} ListCommentsResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 403 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/{id}/comments [get]
+func (s *Server) getComments(context *gin.Context) {
+	var request getPostRequest
+	var query listCommentsRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := context.ShouldBindQuery(&query); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultPageSize
+	}
+	if len(query.View) == 0 {
+		query.View = commentsViewTree
+	}
+
+	arg := db.ListCommentThreadsParams{
+		PageSize:   int32(query.Limit),
+		PageOffset: int32(query.Offset),
+	}
+	// readers only see approved comments, admins see all of them unless they filter
+	if !s.isAdmin(context) {
+		if len(query.Status) > 0 && query.Status != string(db.CommentStatusApproved) {
+			context.JSON(http.StatusForbidden, errorResponse(errAdminOnly))
+			return
+		}
+		query.Status = string(db.CommentStatusApproved)
+	}
+	if len(query.Status) > 0 {
+		arg.Status = db.NullCommentStatus{CommentStatus: db.CommentStatus(query.Status), Valid: true}
+	}
+
+	post, ok := s.publicPost(context, int32(request.ID))
+	if !ok {
+		return
+	}
+	if arg.Status.Valid && arg.Status.CommentStatus != db.CommentStatusApproved {
+		s.listCommentsByStatus(context, db.ListCommentsByStatusParams{
+			PostID:     post.ID,
+			Status:     arg.Status.CommentStatus,
+			PageSize:   arg.PageSize,
+			PageOffset: arg.PageOffset,
+		})
+		return
+	}
+	arg.PostID = post.ID
+
+	rows, err := s.store.ListCommentThreads(context, arg)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := ListCommentsResponse{}
+	if query.View == commentsViewFlat {
+		response.Items = make([]CommentResponse, 0, len(rows))
+		for _, row := range rows {
+			response.Items = append(response.Items, mapToCommentThreadResponse(row))
+		}
+	} else {
+		response.Items = buildCommentTree(rows)
+	}
+	context.JSON(http.StatusOK, response)
+}
+
+// listCommentsByStatus answers the moderation queue: pending and rejected replies mostly sit under approved comments,
+// so instead of the threads starting with such a comment every comment with the status is listed flat, oldest first.
+func (s *Server) listCommentsByStatus(context *gin.Context, arg db.ListCommentsByStatusParams) {
+	comments, err := s.store.ListCommentsByStatus(context, arg)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := ListCommentsResponse{Items: make([]CommentResponse, 0, len(comments))}
+	for _, comment := range comments {
+		response.Items = append(response.Items, mapToCommentResponse(comment))
+	}
+	context.JSON(http.StatusOK, response)
+}
+
+// @Summary Approve comment
+// @Tags Comment
+// @Description Show a comment to readers
+// @ID approve-comment
+// @Produce json
+// @Param id path string true "the specific comment id"
+// @Param X-Admin-Token header string true "admin token"
+// @Success 200 {object} CommentResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 403 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /comments/{id}/approve [post]
+func (s *Server) approveComment(context *gin.Context) {
+	s.moderateComment(context, db.CommentStatusApproved)
+}
+
+// @Summary Reject comment
+// @Tags Comment
+// @Description Hide a comment and its replies from readers
+// @ID reject-comment
+// @Produce json
+// @Param id path string true "the specific comment id"
+// @Param X-Admin-Token header string true "admin token"
+// @Success 200 {object} CommentResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 403 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /comments/{id}/reject [post]
+func (s *Server) rejectComment(context *gin.Context) {
+	s.moderateComment(context, db.CommentStatusRejected)
+}
+
+func (s *Server) moderateComment(context *gin.Context, status db.CommentStatus) {
+	var request getPostRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !s.isAdmin(context) {
+		context.JSON(http.StatusForbidden, errorResponse(errAdminOnly))
+		return
+	}
+
+	comment, err := s.store.UpdateCommentStatus(context, db.UpdateCommentStatusParams{
+		ID:     int32(request.ID),
+		Status: status,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			context.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	context.JSON(http.StatusOK, mapToCommentResponse(comment))
+}
+
+// @Summary Delete comment
+// @Tags Comment
+// @Description Permanently delete a comment together with all replies to it
+// @ID delete-comment
+// @Produce json
+// @Param id path string true "the specific comment id"
+// @Param X-Admin-Token header string true "admin token"
+// @Success 200
+// @Failure 400 {object} ErrResponse
+// @Failure 403 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /comments/{id} [delete]
+func (s *Server) deleteComment(context *gin.Context) {
+	var request getPostRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !s.isAdmin(context) {
+		context.JSON(http.StatusForbidden, errorResponse(errAdminOnly))
+		return
+	}
+
+	deleted, err := s.store.DeleteComment(context, int32(request.ID))
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if deleted == 0 {
+		context.JSON(http.StatusNotFound, errorResponse(sql.ErrNoRows))
+		return
+	}
+	context.Status(http.StatusOK)
+}
+
+// loadCommentCounts fetches the number of visible comments of the given posts in a single query, the approved ones
+// whose parents are all approved too.
+func (s *Server) loadCommentCounts(context *gin.Context, postIDs []int32) (map[int32]int64, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := s.store.GetCommentCountsByPostIds(context, postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int32]int64, len(rows))
+	for _, row := range rows {
+		counts[row.PostID] = row.CommentCount
+	}
+	return counts, nil
+}
+
+// buildCommentTree nests the replies under the comments they answer. The rows come in reading order,
+// so the order among siblings is kept.
+func buildCommentTree(rows []db.ListCommentThreadsRow) []CommentResponse {
+	replies := make(map[int32][]db.ListCommentThreadsRow, len(rows))
+	var roots []db.ListCommentThreadsRow
+	for _, row := range rows {
+		if row.ParentID.Valid && row.Depth > 0 {
+			replies[row.ParentID.Int32] = append(replies[row.ParentID.Int32], row)
+		} else {
+			roots = append(roots, row)
+		}
+	}
+
+	var build func(rows []db.ListCommentThreadsRow) []CommentResponse
+	build = func(rows []db.ListCommentThreadsRow) []CommentResponse {
+		comments := make([]CommentResponse, 0, len(rows))
+		for _, row := range rows {
+			comment := mapToCommentThreadResponse(row)
+			if children, ok := replies[row.ID]; ok {
+				comment.Replies = build(children)
+			}
+			comments = append(comments, comment)
+		}
+		return comments
+	}
+	return build(roots)
+}
+
+func mapToCommentThreadResponse(row db.ListCommentThreadsRow) CommentResponse {
+	commentResponse := mapToCommentResponse(db.Comment{
+		ID:         row.ID,
+		PostID:     row.PostID,
+		ParentID:   row.ParentID,
+		AuthorName: row.AuthorName,
+		Body:       row.Body,
+		Status:     row.Status,
+		CreatedAt:  row.CreatedAt,
+	})
+	commentResponse.Depth = int(row.Depth)
+	return commentResponse
+}
+
+func mapToCommentResponse(comment db.Comment) CommentResponse {
+	commentResponse := CommentResponse{
+		ID:         int(comment.ID),
+		PostID:     int(comment.PostID),
+		AuthorName: comment.AuthorName,
+		Body:       comment.Body,
+		Status:     string(comment.Status),
+		CreatedAt:  comment.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if comment.ParentID.Valid {
+		parentID := int(comment.ParentID.Int32)
+		commentResponse.ParentID = &parentID
+	}
+	return commentResponse
+}
@@ -0,0 +1,618 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"testing"
+	"time"
+)
+
+func TestCreateComment(t *testing.T) {
+	publishedPost := generateRandomPost()
+	publishedPost.Status = db.PostStatusPublished
+	comment := generateRandomComment(publishedPost.ID)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "positive_CreateComment",
+			body: gin.H{"authorName": comment.AuthorName, "body": comment.Body},
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.CreateCommentParams{PostID: publishedPost.ID, AuthorName: comment.AuthorName, Body: comment.Body}
+
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(publishedPost.ID)).
+					Times(1).
+					Return(publishedPost, nil)
+				querier.EXPECT().
+					CreateComment(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(comment, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchComment(t, recorder.Body, mapToCommentResponse(comment))
+			},
+		},
+		{
+			name: "positive_CreateComment_Reply",
+			body: gin.H{"authorName": comment.AuthorName, "body": comment.Body, "parentId": 7},
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.CreateCommentParams{
+					PostID:     publishedPost.ID,
+					ParentID:   sql.NullInt32{Int32: 7, Valid: true},
+					AuthorName: comment.AuthorName,
+					Body:       comment.Body,
+				}
+				reply := comment
+				reply.ParentID = arg.ParentID
+
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(publishedPost.ID)).
+					Times(1).
+					Return(publishedPost, nil)
+				querier.EXPECT().
+					CreateComment(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(reply, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var actual CommentResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actual))
+				require.NotNil(t, actual.ParentID)
+				require.Equal(t, 7, *actual.ParentID)
+			},
+		},
+		{
+			name: "negative_CreateComment_ParentNotFound",
+			body: gin.H{"authorName": comment.AuthorName, "body": comment.Body, "parentId": 7},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(publishedPost.ID)).
+					Times(1).
+					Return(publishedPost, nil)
+				querier.EXPECT().
+					CreateComment(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Comment{}, &pq.Error{Code: "23503"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errParentCommentNotFound.Error()})
+			},
+		},
+		{
+			name: "negative_CreateComment_DraftPost",
+			body: gin.H{"authorName": comment.AuthorName, "body": comment.Body},
+			buildStubs: func(querier *mockdb.MockStore) {
+				draftPost := publishedPost
+				draftPost.Status = db.PostStatusDraft
+
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(publishedPost.ID)).
+					Times(1).
+					Return(draftPost, nil)
+				querier.EXPECT().
+					CreateComment(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "negative_CreateComment_PostNotFound",
+			body: gin.H{"authorName": comment.AuthorName, "body": comment.Body},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(publishedPost.ID)).
+					Times(1).
+					Return(db.Post{}, sql.ErrNoRows)
+				querier.EXPECT().
+					CreateComment(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "negative_CreateComment_MissingBody",
+			body: gin.H{"authorName": comment.AuthorName},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					CreateComment(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			requestUrl := fmt.Sprintf("/posts/%d/comments", publishedPost.ID)
+			request, err := http.NewRequest(http.MethodPost, requestUrl, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetComments(t *testing.T) {
+	publishedPost := generateRandomPost()
+	publishedPost.Status = db.PostStatusPublished
+
+	// two threads in reading order, the first one with a reply and a reply to the reply
+	rows := []db.ListCommentThreadsRow{
+		generateRandomCommentThreadRow(publishedPost.ID, 1, 0, 0),
+		generateRandomCommentThreadRow(publishedPost.ID, 2, 1, 1),
+		generateRandomCommentThreadRow(publishedPost.ID, 4, 2, 2),
+		generateRandomCommentThreadRow(publishedPost.ID, 3, 1, 1),
+		generateRandomCommentThreadRow(publishedPost.ID, 5, 0, 0),
+	}
+	approved := db.NullCommentStatus{CommentStatus: db.CommentStatusApproved, Valid: true}
+	// a pending reply to an approved comment, which moderators have to find without going through the threads
+	pendingReply := generateRandomComment(publishedPost.ID)
+	pendingReply.ParentID = sql.NullInt32{Int32: 1, Valid: true}
+
+	testCases := []struct {
+		name          string
+		query         string
+		adminToken    string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "positive_GetComments_Tree",
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListCommentThreadsParams{PostID: publishedPost.ID, Status: approved, PageSize: defaultPageSize}
+
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(publishedPost.ID)).
+					Times(1).
+					Return(publishedPost, nil)
+				querier.EXPECT().
+					ListCommentThreads(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(rows, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				first := mapToCommentThreadResponse(rows[0])
+				reply := mapToCommentThreadResponse(rows[1])
+				reply.Replies = []CommentResponse{mapToCommentThreadResponse(rows[2])}
+				first.Replies = []CommentResponse{reply, mapToCommentThreadResponse(rows[3])}
+				requireBodyMatchComments(t, recorder.Body, ListCommentsResponse{
+					Items: []CommentResponse{first, mapToCommentThreadResponse(rows[4])},
+				})
+			},
+		},
+		{
+			name:  "positive_GetComments_Flat",
+			query: "?view=flat&limit=2&offset=4",
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListCommentThreadsParams{PostID: publishedPost.ID, Status: approved, PageSize: 2, PageOffset: 4}
+
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(publishedPost.ID)).
+					Times(1).
+					Return(publishedPost, nil)
+				querier.EXPECT().
+					ListCommentThreads(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(rows, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				expected := ListCommentsResponse{Items: []CommentResponse{}}
+				for _, row := range rows {
+					expected.Items = append(expected.Items, mapToCommentThreadResponse(row))
+				}
+				requireBodyMatchComments(t, recorder.Body, expected)
+			},
+		},
+		{
+			name:       "positive_GetComments_AdminSeesAllStatuses",
+			adminToken: testAdminToken,
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListCommentThreadsParams{PostID: publishedPost.ID, PageSize: defaultPageSize}
+
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(publishedPost.ID)).
+					Times(1).
+					Return(publishedPost, nil)
+				querier.EXPECT().
+					ListCommentThreads(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.ListCommentThreadsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchComments(t, recorder.Body, ListCommentsResponse{Items: []CommentResponse{}})
+			},
+		},
+		{
+			name:       "positive_GetComments_AdminFiltersPending",
+			query:      "?status=pending&limit=10&offset=10",
+			adminToken: testAdminToken,
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListCommentsByStatusParams{
+					PostID:     publishedPost.ID,
+					Status:     db.CommentStatusPending,
+					PageSize:   10,
+					PageOffset: 10,
+				}
+
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(publishedPost.ID)).
+					Times(1).
+					Return(publishedPost, nil)
+				querier.EXPECT().
+					ListCommentsByStatus(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Comment{pendingReply}, nil)
+				querier.EXPECT().
+					ListCommentThreads(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchComments(t, recorder.Body, ListCommentsResponse{
+					Items: []CommentResponse{mapToCommentResponse(pendingReply)},
+				})
+			},
+		},
+		{
+			name:       "negative_GetComments_AdminFiltersRejectedInternalError",
+			query:      "?status=rejected",
+			adminToken: testAdminToken,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(publishedPost.ID)).
+					Times(1).
+					Return(publishedPost, nil)
+				querier.EXPECT().
+					ListCommentsByStatus(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:  "negative_GetComments_PendingForReaders",
+			query: "?status=pending",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListCommentThreads(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errAdminOnly.Error()})
+			},
+		},
+		{
+			name:  "negative_GetComments_InvalidView",
+			query: "?view=graph",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListCommentThreads(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "negative_GetComments_ScheduledPost",
+			buildStubs: func(querier *mockdb.MockStore) {
+				scheduledPost := publishedPost
+				scheduledPost.PublishAt = sql.NullTime{Time: testNow.Add(time.Hour), Valid: true}
+
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(publishedPost.ID)).
+					Times(1).
+					Return(scheduledPost, nil)
+				querier.EXPECT().
+					ListCommentThreads(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "negative_GetComments_InternalError",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(publishedPost.ID)).
+					Times(1).
+					Return(publishedPost, nil)
+				querier.EXPECT().
+					ListCommentThreads(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/posts/%d/comments%s", publishedPost.ID, testCase.query)
+			request, err := http.NewRequest(http.MethodGet, requestUrl, nil)
+			require.NoError(t, err)
+			if len(testCase.adminToken) > 0 {
+				request.Header.Set(adminTokenHeader, testCase.adminToken)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestModerateComment(t *testing.T) {
+	comment := generateRandomComment(1)
+
+	testCases := []struct {
+		name          string
+		action        string
+		adminToken    string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "positive_ModerateComment_Approve",
+			action:     "approve",
+			adminToken: testAdminToken,
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.UpdateCommentStatusParams{ID: comment.ID, Status: db.CommentStatusApproved}
+				approvedComment := comment
+				approvedComment.Status = db.CommentStatusApproved
+
+				querier.EXPECT().
+					UpdateCommentStatus(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(approvedComment, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				expected := mapToCommentResponse(comment)
+				expected.Status = string(db.CommentStatusApproved)
+				requireBodyMatchComment(t, recorder.Body, expected)
+			},
+		},
+		{
+			name:       "positive_ModerateComment_Reject",
+			action:     "reject",
+			adminToken: testAdminToken,
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.UpdateCommentStatusParams{ID: comment.ID, Status: db.CommentStatusRejected}
+
+				querier.EXPECT().
+					UpdateCommentStatus(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(comment, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "negative_ModerateComment_NotAdmin",
+			action: "approve",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					UpdateCommentStatus(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:       "negative_ModerateComment_CommentNotFound",
+			action:     "approve",
+			adminToken: testAdminToken,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					UpdateCommentStatus(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Comment{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/comments/%d/%s", comment.ID, testCase.action)
+			request, err := http.NewRequest(http.MethodPost, requestUrl, nil)
+			require.NoError(t, err)
+			if len(testCase.adminToken) > 0 {
+				request.Header.Set(adminTokenHeader, testCase.adminToken)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteComment(t *testing.T) {
+	comment := generateRandomComment(1)
+
+	testCases := []struct {
+		name          string
+		adminToken    string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "positive_DeleteComment",
+			adminToken: testAdminToken,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					DeleteComment(gomock.Any(), gomock.Eq(comment.ID)).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "negative_DeleteComment_NotAdmin",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					DeleteComment(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:       "negative_DeleteComment_CommentNotFound",
+			adminToken: testAdminToken,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					DeleteComment(gomock.Any(), gomock.Eq(comment.ID)).
+					Times(1).
+					Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/comments/%d", comment.ID)
+			request, err := http.NewRequest(http.MethodDelete, requestUrl, nil)
+			require.NoError(t, err)
+			if len(testCase.adminToken) > 0 {
+				request.Header.Set(adminTokenHeader, testCase.adminToken)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func requireBodyMatchComment(t *testing.T, body *bytes.Buffer, expected CommentResponse) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var actual CommentResponse
+	err = json.Unmarshal(data, &actual)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func requireBodyMatchComments(t *testing.T, body *bytes.Buffer, expected ListCommentsResponse) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var actual ListCommentsResponse
+	err = json.Unmarshal(data, &actual)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func generateRandomComment(postID int32) db.Comment {
+	randomPost := generateRandomPost()
+	return db.Comment{
+		ID:         randomPost.ID,
+		PostID:     postID,
+		AuthorName: randomPost.Title,
+		Body:       randomPost.Content,
+		Status:     db.CommentStatusPending,
+		CreatedAt:  randomPost.CreatedAt,
+	}
+}
+
+func generateRandomCommentThreadRow(postID int32, id int32, parentID int32, depth int32) db.ListCommentThreadsRow {
+	comment := generateRandomComment(postID)
+	row := db.ListCommentThreadsRow{
+		ID:         id,
+		PostID:     postID,
+		AuthorName: comment.AuthorName,
+		Body:       comment.Body,
+		Status:     db.CommentStatusApproved,
+		CreatedAt:  comment.CreatedAt,
+		Depth:      depth,
+	}
+	if parentID > 0 {
+		row.ParentID = sql.NullInt32{Int32: parentID, Valid: true}
+	}
+	return row
+}
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"io"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+	"promova-test-task/imaging"
+)
+
+// maxCoverPixels bounds the size of decoded covers, a small file can still decode into gigabytes of pixels
+const maxCoverPixels = 25 * 1000 * 1000
+
+// coverExtensions lists the accepted content types with the extension their originals are stored under.
+var coverExtensions = map[string]string{
+	imaging.ContentTypeJPEG: ".jpg",
+	imaging.ContentTypePNG:  ".png",
+	imaging.ContentTypeWebP: ".webp",
+}
+
+var (
+	errCoverTooLarge      = errors.New("the cover exceeds the size limit")
+	errCoverType          = errors.New("the cover must be a JPEG, PNG or WebP image")
+	errCoverTooManyPixels = fmt.Errorf("the cover must not have more than %d pixels", maxCoverPixels)
+)
+
+// CoverImageResponse is the cover of a post. The URLs are only set once the variants are generated,
+// until then the status is pending or processing.
+type CoverImageResponse struct {
+	Status    string `json:"status"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Original  string `json:"original,omitempty"`
+	Thumbnail string `json:"thumbnail,omitempty"`
+	Medium    string `json:"medium,omitempty"`
+	Large     string `json:"large,omitempty"`
+}
+
+// @Summary Set post cover
+// @Tags Post
+// @Description Upload a JPEG, PNG or WebP image as the cover of a post, replacing the previous one.
+// @Description The thumbnail, medium and large variants are generated in the background and the metadata
+// @Description of the image is stripped, the URLs show up in the post once the cover is ready
+// @ID put-post-cover
+// @Accept mpfd
+// @Produce json
+// @Param id path string true "the specific post id"
+// @Param file formData file true "the cover image"
+// @Success 202 {object} CoverImageResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 413 {object} ErrResponse
+// @Failure 415 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/{id}/cover [put]
+func (s *Server) putCover(context *gin.Context) {
+	var request getPostRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	post, err := s.store.GetPostById(context, int32(request.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			context.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	context.Request.Body = http.MaxBytesReader(context.Writer, context.Request.Body, s.config.CoverMaxSize+maxMultipartOverhead)
+	fileHeader, err := context.FormFile(attachmentFormField)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			context.JSON(http.StatusRequestEntityTooLarge, errorResponse(errCoverTooLarge))
+			return
+		}
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if fileHeader.Size > s.config.CoverMaxSize {
+		context.JSON(http.StatusRequestEntityTooLarge, errorResponse(errCoverTooLarge))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	defer file.Close()
+
+	contentType, err := sniffContentType(file)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	extension, ok := coverExtensions[contentType]
+	if !ok {
+		context.JSON(http.StatusUnsupportedMediaType, errorResponse(errCoverType))
+		return
+	}
+
+	config, err := imaging.DecodeConfig(file)
+	if err != nil {
+		context.JSON(http.StatusUnsupportedMediaType, errorResponse(errCoverType))
+		return
+	}
+	if config.Width*config.Height > maxCoverPixels {
+		context.JSON(http.StatusRequestEntityTooLarge, errorResponse(errCoverTooManyPixels))
+		return
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	key, err := coverKey(post.ID, extension)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if err = s.blobs.Put(context, key, file, fileHeader.Size, contentType); err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	cover, previous, err := s.store.ReplacePostCoverTx(context, db.UpsertPostCoverParams{
+		PostID:      post.ID,
+		BlobKey:     key,
+		ContentType: contentType,
+		Width:       int32(config.Width),
+		Height:      int32(config.Height),
+	})
+	if err != nil {
+		s.deleteBlobs(context, key)
+
+		var pqError *pq.Error
+		if errors.Is(err, sql.ErrNoRows) || errors.As(err, &pqError) && pqError.Code.Name() == "foreign_key_violation" {
+			context.JSON(http.StatusNotFound, errorResponse(sql.ErrNoRows))
+			return
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if previous != nil {
+		s.deleteBlobs(context, coverBlobKeys(*previous)...)
+	}
+
+	context.JSON(http.StatusAccepted, s.mapToCoverImageResponse(cover))
+}
+
+// loadCovers fetches the covers of the given posts in a single query.
+func (s *Server) loadCovers(context *gin.Context, postIDs []int32) (map[int32]CoverImageResponse, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+
+	covers, err := s.store.GetPostCoversByPostIds(context, postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	coversByPostId := make(map[int32]CoverImageResponse, len(covers))
+	for _, cover := range covers {
+		coversByPostId[cover.PostID] = s.mapToCoverImageResponse(cover)
+	}
+	return coversByPostId, nil
+}
+
+// coverKey names the original of a new cover. The variants are stored in the same directory,
+// whose random part keeps keys unguessable and unique.
+func coverKey(postID int32, extension string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("covers/%d/%s/original%s", postID, hex.EncodeToString(random), extension), nil
+}
+
+// coverBlobKeys lists the original of the cover and the variants generated so far.
+func coverBlobKeys(cover db.PostCover) []string {
+	keys := []string{cover.BlobKey}
+	for _, variant := range []sql.NullString{cover.ThumbnailKey, cover.MediumKey, cover.LargeKey} {
+		if variant.Valid {
+			keys = append(keys, variant.String)
+		}
+	}
+	return keys
+}
+
+func (s *Server) mapToCoverImageResponse(cover db.PostCover) CoverImageResponse {
+	response := CoverImageResponse{
+		Status: string(cover.Status),
+		Width:  int(cover.Width),
+		Height: int(cover.Height),
+	}
+	// the original still carries its metadata until the variants are generated
+	if cover.Status != db.CoverStatusReady {
+		return response
+	}
+
+	response.Original = s.blobs.URL(cover.BlobKey)
+	response.Thumbnail = s.blobs.URL(cover.ThumbnailKey.String)
+	response.Medium = s.blobs.URL(cover.MediumKey.String)
+	response.Large = s.blobs.URL(cover.LargeKey.String)
+	return response
+}
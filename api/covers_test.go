@@ -0,0 +1,313 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"strings"
+	"testing"
+)
+
+// encodePNG encodes a blank image of the given size.
+func encodePNG(t *testing.T, width, height int) []byte {
+	var buffer bytes.Buffer
+	require.NoError(t, png.Encode(&buffer, image.NewGray(image.Rect(0, 0, width, height))))
+	return buffer.Bytes()
+}
+
+// pngSignatureWithSize is the start of a PNG file claiming the given size, enough to read its dimensions.
+func pngSignatureWithSize(width, height uint32) []byte {
+	chunk := []byte("IHDR")
+	chunk = binary.BigEndian.AppendUint32(chunk, width)
+	chunk = binary.BigEndian.AppendUint32(chunk, height)
+	chunk = append(chunk, 8, 0, 0, 0, 0)
+
+	data := []byte("\x89PNG\r\n\x1a\n")
+	data = binary.BigEndian.AppendUint32(data, uint32(len(chunk)-4))
+	data = append(data, chunk...)
+	return binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(chunk))
+}
+
+func TestPutCover(t *testing.T) {
+	randomPost := generateRandomPost()
+	cover := encodePNG(t, 64, 48)
+	previous := db.PostCover{
+		PostID:       randomPost.ID,
+		BlobKey:      "covers/1/old/original.jpg",
+		Status:       db.CoverStatusReady,
+		ThumbnailKey: sql.NullString{String: "covers/1/old/thumbnail.jpg", Valid: true},
+		MediumKey:    sql.NullString{String: "covers/1/old/medium.jpg", Valid: true},
+		LargeKey:     sql.NullString{String: "covers/1/old/large.jpg", Valid: true},
+	}
+
+	testCases := []struct {
+		name          string
+		file          []byte
+		blobs         []string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore)
+	}{
+		{
+			name: "positive_PutCover",
+			file: cover,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+				querier.EXPECT().
+					ReplacePostCoverTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.UpsertPostCoverParams) (db.PostCover, *db.PostCover, error) {
+						require.Equal(t, randomPost.ID, arg.PostID)
+						require.True(t, strings.HasPrefix(arg.BlobKey, fmt.Sprintf("covers/%d/", randomPost.ID)))
+						require.True(t, strings.HasSuffix(arg.BlobKey, "/original.png"))
+						require.Equal(t, "image/png", arg.ContentType)
+						require.Equal(t, int32(64), arg.Width)
+						require.Equal(t, int32(48), arg.Height)
+						return db.PostCover{
+							PostID:      arg.PostID,
+							BlobKey:     arg.BlobKey,
+							ContentType: arg.ContentType,
+							Width:       arg.Width,
+							Height:      arg.Height,
+							Status:      db.CoverStatusPending,
+						}, nil, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				requireBodyMatchCoverImage(t, recorder.Body, CoverImageResponse{Status: "pending", Width: 64, Height: 48})
+				require.Len(t, blobs.blobs, 1)
+				for _, data := range blobs.blobs {
+					require.Equal(t, cover, data)
+				}
+			},
+		},
+		{
+			name:  "positive_PutCover_ReplacesPrevious",
+			file:  cover,
+			blobs: coverBlobKeys(previous),
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+				querier.EXPECT().
+					ReplacePostCoverTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.UpsertPostCoverParams) (db.PostCover, *db.PostCover, error) {
+						return db.PostCover{PostID: arg.PostID, BlobKey: arg.BlobKey, Status: db.CoverStatusPending}, &previous, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.Len(t, blobs.blobs, 1)
+				for _, key := range coverBlobKeys(previous) {
+					require.NotContains(t, blobs.blobs, key)
+				}
+			},
+		},
+		{
+			name: "negative_PutCover_TooLarge",
+			file: append(encodePNG(t, 1, 1), make([]byte, testCoverMaxSize)...),
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+				querier.EXPECT().
+					ReplacePostCoverTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errCoverTooLarge.Error()})
+				require.Empty(t, blobs.blobs)
+			},
+		},
+		{
+			name: "negative_PutCover_TooManyPixels",
+			file: pngSignatureWithSize(10000, 10000),
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+				querier.EXPECT().
+					ReplacePostCoverTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errCoverTooManyPixels.Error()})
+				require.Empty(t, blobs.blobs)
+			},
+		},
+		{
+			name: "negative_PutCover_UnsupportedType",
+			file: []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00;"),
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+				querier.EXPECT().
+					ReplacePostCoverTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore) {
+				require.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errCoverType.Error()})
+				require.Empty(t, blobs.blobs)
+			},
+		},
+		{
+			name: "negative_PutCover_Corrupt",
+			file: pngHeader,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+				querier.EXPECT().
+					ReplacePostCoverTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore) {
+				require.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+				require.Empty(t, blobs.blobs)
+			},
+		},
+		{
+			name: "negative_PutCover_PostNotFound",
+			file: cover,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(db.Post{}, sql.ErrNoRows)
+				querier.EXPECT().
+					ReplacePostCoverTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "negative_PutCover_PostDeletedMeanwhile",
+			file: cover,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+				querier.EXPECT().
+					ReplacePostCoverTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PostCover{}, nil, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, blobs *testBlobStore) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Empty(t, blobs.blobs)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			blobs := server.blobs.(*testBlobStore)
+			for _, key := range testCase.blobs {
+				blobs.blobs[key] = pngHeader
+			}
+			recorder := httptest.NewRecorder()
+
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			part, err := writer.CreateFormFile(attachmentFormField, "cover.png")
+			require.NoError(t, err)
+			_, err = part.Write(testCase.file)
+			require.NoError(t, err)
+			require.NoError(t, writer.Close())
+
+			requestUrl := fmt.Sprintf("/posts/%d/cover", randomPost.ID)
+			request, err := http.NewRequest(http.MethodPut, requestUrl, &body)
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", writer.FormDataContentType())
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder, blobs)
+		})
+	}
+}
+
+func TestPurgePost_DeletesCoverBlobs(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	postID := int32(42)
+	cover := db.PostCover{
+		PostID:       postID,
+		BlobKey:      "covers/42/abc/original.png",
+		Status:       db.CoverStatusReady,
+		ThumbnailKey: sql.NullString{String: "covers/42/abc/thumbnail.png", Valid: true},
+		MediumKey:    sql.NullString{String: "covers/42/abc/medium.png", Valid: true},
+		LargeKey:     sql.NullString{String: "covers/42/abc/large.png", Valid: true},
+	}
+
+	store := mockdb.NewMockStore(controller)
+	gomock.InOrder(
+		store.EXPECT().
+			GetPostCoversByPostIds(gomock.Any(), gomock.Eq([]int32{postID})).
+			Times(1).
+			Return([]db.PostCover{cover}, nil),
+		store.EXPECT().
+			PurgePost(gomock.Any(), gomock.Eq(postID)).
+			Times(1).
+			Return(int64(1), nil),
+	)
+	server := newTestServer(store)
+	blobs := server.blobs.(*testBlobStore)
+	for _, key := range coverBlobKeys(cover) {
+		blobs.blobs[key] = pngHeader
+	}
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/posts/%d?hard=true", postID), nil)
+	require.NoError(t, err)
+	request.Header.Set(adminTokenHeader, testAdminToken)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Empty(t, blobs.blobs)
+}
+
+func requireBodyMatchCoverImage(t *testing.T, body *bytes.Buffer, expected CoverImageResponse) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var actual CoverImageResponse
+	err = json.Unmarshal(data, &actual)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
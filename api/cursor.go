@@ -0,0 +1,89 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	db "promova-test-task/db/sqlc"
+	"time"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// postsCursor points at the last post of a page. It is handed to clients as an opaque token.
+// Besides the id it keeps the value of the column the page was sorted by, so the next page
+// can seek by (column, id).
+type postsCursor struct {
+	Sort  string     `json:"sort"`
+	ID    int32      `json:"id"`
+	Time  *time.Time `json:"time,omitempty"`
+	Title *string    `json:"title,omitempty"`
+	Likes *int32     `json:"likes,omitempty"`
+}
+
+// newPostsCursor points at the post, which has the given number of likes.
+func newPostsCursor(sort string, post db.Post, likes int32) postsCursor {
+	cursor := postsCursor{Sort: sort, ID: post.ID}
+
+	switch sort {
+	case "created_at", "-created_at":
+		cursor.Time = &post.CreatedAt
+	case "updated_at", "-updated_at":
+		cursor.Time = &post.UpdatedAt
+	case "title", "-title":
+		cursor.Title = &post.Title
+	case "likes", "-likes":
+		cursor.Likes = &likes
+	}
+	return cursor
+}
+
+// apply sets the seek arguments of the listing query.
+func (c postsCursor) apply(arg *listPostsParams) {
+	arg.CursorID = sql.NullInt32{Int32: c.ID, Valid: true}
+	if c.Time != nil {
+		arg.CursorTime = sql.NullTime{Time: *c.Time, Valid: true}
+	}
+	if c.Title != nil {
+		arg.CursorTitle = sql.NullString{String: *c.Title, Valid: true}
+	}
+	if c.Likes != nil {
+		arg.CursorLikes = sql.NullInt32{Int32: *c.Likes, Valid: true}
+	}
+}
+
+func encodeCursor(cursor postsCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses the token and makes sure it was issued for the same sort order.
+func decodeCursor(token string, sort string) (postsCursor, error) {
+	var cursor postsCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, errInvalidCursor
+	}
+	if err = json.Unmarshal(data, &cursor); err != nil {
+		return cursor, errInvalidCursor
+	}
+	if cursor.ID <= 0 || cursor.Sort != sort || !cursor.hasSortValue() {
+		return cursor, errInvalidCursor
+	}
+	return cursor, nil
+}
+
+// hasSortValue reports whether the cursor carries the column value its sort order seeks by.
+func (c postsCursor) hasSortValue() bool {
+	switch c.Sort {
+	case "created_at", "-created_at", "updated_at", "-updated_at":
+		return c.Time != nil
+	case "title", "-title":
+		return c.Title != nil
+	case "likes", "-likes":
+		return c.Likes != nil
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+	"strings"
+	"time"
+)
+
+const (
+	etagHeader            = "ETag"
+	ifMatchHeader         = "If-Match"
+	ifNoneMatchHeader     = "If-None-Match"
+	lastModifiedHeader    = "Last-Modified"
+	ifModifiedSinceHeader = "If-Modified-Since"
+)
+
+var errPreconditionFailed = errors.New("post was modified since it was fetched, get it again and retry")
+
+func postETag(post db.Post) string {
+	return fmt.Sprintf(`"%d"`, post.Version)
+}
+
+// expectedVersion checks the If-Match header against the post as it was just read.
+// It returns the version the write has to find in the database, or false when the precondition already failed.
+// Without If-Match the write is unconditional.
+func expectedVersion(context *gin.Context, post db.Post) (sql.NullInt32, bool) {
+	header := context.GetHeader(ifMatchHeader)
+	if len(header) == 0 {
+		return sql.NullInt32{}, true
+	}
+
+	etag := postETag(post)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == etag {
+			return sql.NullInt32{Int32: post.Version, Valid: true}, true
+		}
+	}
+	return sql.NullInt32{}, false
+}
+
+// notModified reports whether the client already has the representation with the given validators.
+// If-None-Match takes precedence over If-Modified-Since, as a changed set of posts does not always move the latter.
+func notModified(context *gin.Context, etag string, lastModified time.Time) bool {
+	if header := context.GetHeader(ifNoneMatchHeader); len(header) > 0 {
+		for _, tag := range strings.Split(header, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	header := context.GetHeader(ifModifiedSinceHeader)
+	if len(header) == 0 || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(header)
+	if err != nil {
+		return false
+	}
+	// HTTP dates have a resolution of seconds
+	return !lastModified.Truncate(time.Second).After(since)
+}
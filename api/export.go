@@ -0,0 +1,285 @@
+package api
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	exportFormatNDJSON = "ndjson"
+	exportFormatCSV    = "csv"
+
+	ndjsonContentType = "application/x-ndjson"
+	csvContentType    = "text/csv; charset=utf-8"
+
+	// exportFlushSize is how many posts are written between flushes of the response
+	exportFlushSize    = 500
+	exportErrorTrailer = "X-Export-Error"
+)
+
+// exportColumns is the header of a CSV export, in the order of PostExportResponse
+var exportColumns = []string{
+	"id", "slug", "title", "content", "contentFormat", "status", "authorId", "categoryId", "tags",
+	"createdAt", "updatedAt", "publishedAt", "version",
+}
+
+type exportPostsRequest struct {
+	Format string `form:"format" binding:"required,oneof=ndjson csv"`
+	Gzip   bool   `form:"gzip"`
+	Status string `form:"status" binding:"omitempty,oneof=draft published archived"`
+	postFilters
+}
+
+// PostExportResponse is an exported post, a line of NDJSON or a row of CSV with the same columns
+type PostExportResponse struct {
+	ID            int      `json:"id"`
+	Slug          string   `json:"slug"`
+	Title         string   `json:"title"`
+	Content       string   `json:"content"`
+	ContentFormat string   `json:"contentFormat"`
+	Status        string   `json:"status"`
+	AuthorID      *int     `json:"authorId"`
+	CategoryID    *int     `json:"categoryId"`
+	Tags          []string `json:"tags"`
+	CreatedAt     string   `json:"createdAt"`
+	UpdatedAt     string   `json:"updatedAt"`
+	PublishedAt   string   `json:"publishedAt,omitempty"`
+	Version       int      `json:"version"`
+}
+
+// exportEncoder writes exported posts in one of the export formats
+type exportEncoder interface {
+	encode(post PostExportResponse) error
+	flush() error
+}
+
+type ndjsonEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e ndjsonEncoder) encode(post PostExportResponse) error {
+	return e.encoder.Encode(post)
+}
+
+func (e ndjsonEncoder) flush() error {
+	return nil
+}
+
+type csvEncoder struct {
+	writer *csv.Writer
+}
+
+func (e csvEncoder) encode(post PostExportResponse) error {
+	return e.writer.Write(post.csvRecord())
+}
+
+func (e csvEncoder) flush() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// newExportEncoder starts an export in the format, a CSV export with its header.
+func newExportEncoder(format string, writer io.Writer) (exportEncoder, error) {
+	if format == exportFormatCSV {
+		encoder := csvEncoder{writer: csv.NewWriter(writer)}
+		return encoder, encoder.writer.Write(exportColumns)
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	return ndjsonEncoder{encoder: encoder}, nil
+}
+
+// @Summary Export posts
+// @Tags Post
+// @Description Export all posts matching the filters of the listing as NDJSON or CSV, ordered by id.
+// @Description The posts are streamed from the database as they are read, so exports of any size take the same memory.
+// @Description An export failing midway ends early with its error in the X-Export-Error trailer
+// @ID export-posts
+// @Produce application/x-ndjson,text/csv
+// @Param format query string true "the format of the export" Enums(ndjson, csv)
+// @Param gzip query bool false "compress the export with gzip, sent as its content encoding"
+// @Param created_after query string false "only posts created after the RFC 3339 timestamp"
+// @Param created_before query string false "only posts created before the RFC 3339 timestamp"
+// @Param updated_since query string false "only posts updated at or after the RFC 3339 timestamp"
+// @Param title_contains query string false "case-insensitive substring of the title"
+// @Param status query string false "lifecycle status of the posts, defaults to published. Other statuses need the admin token" Enums(draft, published, archived)
+// @Param tag query []string false "only posts with these tags, repeat the parameter for several" collectionFormat(multi)
+// @Param tag_match query string false "whether posts need any or all of the tags, defaults to any" Enums(any, all)
+// @Param X-Admin-Token header string false "admin token"
+// @Success 200 {array} PostExportResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 403 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/export [get]
+func (s *Server) exportPosts(context *gin.Context) {
+	var request exportPostsRequest
+
+	if err := context.ShouldBindQuery(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if len(request.Status) == 0 {
+		request.Status = string(db.PostStatusPublished)
+	}
+	// drafts and archived posts are not public
+	if request.Status != string(db.PostStatusPublished) && !s.isAdmin(context) {
+		context.JSON(http.StatusForbidden, errorResponse(errAdminOnly))
+		return
+	}
+
+	listRequest := listPostsRequest{Status: request.Status, postFilters: request.postFilters}
+	listArg, err := listRequest.toListPostsParams()
+	if err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	arg := db.ListExportPostsParams{
+		CreatedAfter:  listArg.CreatedAfter,
+		CreatedBefore: listArg.CreatedBefore,
+		UpdatedSince:  listArg.UpdatedSince,
+		TitleContains: listArg.TitleContains,
+		Status:        listArg.Status,
+		Tags:          listArg.Tags,
+		TagMatch:      listArg.TagMatch,
+	}
+
+	// the response is chunked, flushed every exportFlushSize posts while the cursor is read
+	var compressor *gzip.Writer
+	var encoder exportEncoder
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+		started = true
+		contentType := ndjsonContentType
+		if request.Format == exportFormatCSV {
+			contentType = csvContentType
+		}
+		context.Header("Content-Type", contentType)
+		context.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="posts.%s"`, request.Format))
+		context.Header("Trailer", exportErrorTrailer)
+
+		var body io.Writer = context.Writer
+		if request.Gzip {
+			context.Header("Content-Encoding", "gzip")
+			compressor = gzip.NewWriter(context.Writer)
+			body = compressor
+		}
+		context.Status(http.StatusOK)
+
+		var err error
+		encoder, err = newExportEncoder(request.Format, body)
+		return err
+	}
+	flush := func() error {
+		if err := encoder.flush(); err != nil {
+			return err
+		}
+		if compressor != nil {
+			if err := compressor.Flush(); err != nil {
+				return err
+			}
+		}
+		context.Writer.Flush()
+		return nil
+	}
+
+	exported := 0
+	err = s.store.StreamExportPosts(context, arg, func(post db.ListExportPostsRow) error {
+		if err := start(); err != nil {
+			return err
+		}
+		if err := encoder.encode(mapToPostExportResponse(post)); err != nil {
+			return err
+		}
+		exported++
+		if exported%exportFlushSize == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		if err = start(); err == nil {
+			err = encoder.flush()
+		}
+	}
+	// a gzip stream left unclosed tells its reader it is truncated
+	if err == nil && compressor != nil {
+		err = compressor.Close()
+	}
+	if err != nil {
+		if !started {
+			context.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		// the status is already sent, all that is left is to tell the export is incomplete
+		log.Printf("failed to stream export after %d posts: %v", exported, err)
+		context.Writer.Header().Set(exportErrorTrailer, err.Error())
+	}
+}
+
+func (r PostExportResponse) csvRecord() []string {
+	optionalID := func(id *int) string {
+		if id == nil {
+			return ""
+		}
+		return strconv.Itoa(*id)
+	}
+	return []string{
+		strconv.Itoa(r.ID),
+		r.Slug,
+		r.Title,
+		r.Content,
+		r.ContentFormat,
+		r.Status,
+		optionalID(r.AuthorID),
+		optionalID(r.CategoryID),
+		strings.Join(r.Tags, ","),
+		r.CreatedAt,
+		r.UpdatedAt,
+		r.PublishedAt,
+		strconv.Itoa(r.Version),
+	}
+}
+
+func mapToPostExportResponse(post db.ListExportPostsRow) PostExportResponse {
+	response := PostExportResponse{
+		ID:            int(post.ID),
+		Slug:          post.Slug,
+		Title:         post.Title,
+		Content:       post.Content,
+		ContentFormat: string(post.ContentFormat),
+		Status:        string(post.Status),
+		Tags:          post.Tags,
+		CreatedAt:     post.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     post.UpdatedAt.UTC().Format(time.RFC3339),
+		Version:       int(post.Version),
+	}
+	if response.Tags == nil {
+		response.Tags = []string{}
+	}
+	if post.AuthorID.Valid {
+		authorID := int(post.AuthorID.Int32)
+		response.AuthorID = &authorID
+	}
+	if post.CategoryID.Valid {
+		categoryID := int(post.CategoryID.Int32)
+		response.CategoryID = &categoryID
+	}
+	if post.PublishedAt.Valid {
+		response.PublishedAt = post.PublishedAt.Time.UTC().Format(time.RFC3339)
+	}
+	return response
+}
@@ -0,0 +1,264 @@
+package api
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"testing"
+	"time"
+)
+
+func TestExportPosts(t *testing.T) {
+	rows := []db.ListExportPostsRow{
+		{
+			ID: 1, Slug: "first-post", Title: "First, post", Content: "<p>Hello</p>\nworld", ContentFormat: db.ContentFormatHtml,
+			Status: db.PostStatusPublished, AuthorID: sql.NullInt32{Int32: 3, Valid: true}, CreatedAt: testNow.Add(-time.Hour),
+			UpdatedAt: testNow, PublishedAt: sql.NullTime{Time: testNow.Add(-time.Hour), Valid: true}, Version: 2,
+			Tags: []string{"go", "sql"},
+		},
+		{
+			ID: 2, Slug: "second-post", Title: "Second post", Content: "Plain", ContentFormat: db.ContentFormatPlain,
+			Status: db.PostStatusPublished, CreatedAt: testNow, UpdatedAt: testNow, Version: 1,
+		},
+	}
+	defaultArg := db.ListExportPostsParams{Status: db.PostStatusPublished}
+
+	testCases := []struct {
+		name          string
+		query         string
+		adminToken    string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "positive_ExportPosts_NDJSON",
+			query: "?format=ndjson",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					StreamExportPosts(gomock.Any(), gomock.Eq(defaultArg), gomock.Any()).
+					Times(1).
+					DoAndReturn(streamExportPosts(rows, nil))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, ndjsonContentType, recorder.Header().Get("Content-Type"))
+				require.Equal(t, `attachment; filename="posts.ndjson"`, recorder.Header().Get("Content-Disposition"))
+
+				posts := requireBodyMatchNDJSON(t, recorder.Body)
+				require.Equal(t, []PostExportResponse{
+					mapToPostExportResponse(rows[0]),
+					mapToPostExportResponse(rows[1]),
+				}, posts)
+				require.Equal(t, 3, *posts[0].AuthorID)
+				require.Equal(t, "2024-05-01T11:00:00Z", posts[0].PublishedAt)
+				require.Empty(t, posts[1].Tags)
+				require.Empty(t, recorder.Result().Trailer.Get(exportErrorTrailer))
+			},
+		},
+		{
+			name:  "positive_ExportPosts_CSV",
+			query: "?format=csv",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					StreamExportPosts(gomock.Any(), gomock.Eq(defaultArg), gomock.Any()).
+					Times(1).
+					DoAndReturn(streamExportPosts(rows, nil))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, csvContentType, recorder.Header().Get("Content-Type"))
+
+				records, err := csv.NewReader(recorder.Body).ReadAll()
+				require.NoError(t, err)
+				require.Equal(t, [][]string{
+					exportColumns,
+					{"1", "first-post", "First, post", "<p>Hello</p>\nworld", "html", "published", "3", "", "go,sql",
+						"2024-05-01T11:00:00Z", "2024-05-01T12:00:00Z", "2024-05-01T11:00:00Z", "2"},
+					{"2", "second-post", "Second post", "Plain", "plain", "published", "", "", "",
+						"2024-05-01T12:00:00Z", "2024-05-01T12:00:00Z", "", "1"},
+				}, records)
+			},
+		},
+		{
+			name:  "positive_ExportPosts_Gzip",
+			query: "?format=ndjson&gzip=true",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					StreamExportPosts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(streamExportPosts(rows, nil))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+
+				reader, err := gzip.NewReader(recorder.Body)
+				require.NoError(t, err)
+				posts := requireBodyMatchNDJSON(t, reader)
+				require.Len(t, posts, 2)
+				require.Equal(t, "first-post", posts[0].Slug)
+			},
+		},
+		{
+			name:  "positive_ExportPosts_Empty",
+			query: "?format=csv",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					StreamExportPosts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(streamExportPosts(nil, nil))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				records, err := csv.NewReader(recorder.Body).ReadAll()
+				require.NoError(t, err)
+				require.Equal(t, [][]string{exportColumns}, records)
+			},
+		},
+		{
+			name:       "positive_ExportPosts_Filters",
+			query:      "?format=ndjson&status=draft&title_contains=50%25&created_after=2024-01-01T00:00:00Z&tag=Go&tag=SQL&tag_match=all",
+			adminToken: testAdminToken,
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListExportPostsParams{
+					CreatedAfter:  sql.NullTime{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+					TitleContains: sql.NullString{String: `50\%`, Valid: true},
+					Status:        db.PostStatusDraft,
+					Tags:          []string{"go", "sql"},
+					TagMatch:      "all",
+				}
+				querier.EXPECT().
+					StreamExportPosts(gomock.Any(), gomock.Eq(arg), gomock.Any()).
+					Times(1).
+					DoAndReturn(streamExportPosts(nil, nil))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, recorder.Body.String())
+			},
+		},
+		{
+			name:  "negative_ExportPosts_DraftsWithoutAdminToken",
+			query: "?format=csv&status=draft",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					StreamExportPosts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errAdminOnly.Error()})
+			},
+		},
+		{
+			name:  "negative_ExportPosts_InvalidFormat",
+			query: "?format=xml",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					StreamExportPosts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "negative_ExportPosts_InvalidCreatedRange",
+			query: "?format=csv&created_after=2024-02-01T00:00:00Z&created_before=2024-01-01T00:00:00Z",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					StreamExportPosts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errInvalidCreatedRange.Error()})
+			},
+		},
+		{
+			name:  "negative_ExportPosts_FailedMidway",
+			query: "?format=ndjson",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					StreamExportPosts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(streamExportPosts(rows[:1], sql.ErrConnDone))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Len(t, requireBodyMatchNDJSON(t, recorder.Body), 1)
+				require.Equal(t, sql.ErrConnDone.Error(), recorder.Result().Trailer.Get(exportErrorTrailer))
+			},
+		},
+		{
+			name:  "negative_ExportPosts_InternalError",
+			query: "?format=ndjson",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					StreamExportPosts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(streamExportPosts(nil, sql.ErrConnDone))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: sql.ErrConnDone.Error()})
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/posts/export"+testCase.query, nil)
+			require.NoError(t, err)
+			if len(testCase.adminToken) > 0 {
+				request.Header.Set(adminTokenHeader, testCase.adminToken)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+// streamExportPosts stubs the cursor by calling back with the rows, then failing with err if any.
+func streamExportPosts(rows []db.ListExportPostsRow, err error) func(context.Context, db.ListExportPostsParams, func(db.ListExportPostsRow) error) error {
+	return func(_ context.Context, _ db.ListExportPostsParams, fn func(db.ListExportPostsRow) error) error {
+		for _, row := range rows {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+}
+
+func requireBodyMatchNDJSON(t *testing.T, body io.Reader) []PostExportResponse {
+	var posts []PostExportResponse
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		var post PostExportResponse
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &post))
+		posts = append(posts, post)
+	}
+	require.NoError(t, scanner.Err())
+	return posts
+}
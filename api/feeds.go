@@ -0,0 +1,443 @@
+package api
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	feedTitle       = "Latest posts"
+	feedDescription = "The latest published posts"
+	feedSort        = "-created_at"
+
+	rssContentType      = "application/rss+xml; charset=utf-8"
+	atomContentType     = "application/atom+xml; charset=utf-8"
+	jsonFeedContentType = "application/feed+json; charset=utf-8"
+	jsonFeedVersion     = "https://jsonfeed.org/version/1.1"
+)
+
+type feedRequest struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+	postFilters
+}
+
+// feed is what the RSS, Atom and JSON feeds are rendered from
+type feed struct {
+	homeURL string
+	feedURL string
+	updated time.Time
+	items   []feedItem
+}
+
+type feedItem struct {
+	id        string
+	url       string
+	title     string
+	content   string
+	author    string
+	tags      []string
+	published time.Time
+	updated   time.Time
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Self          atomLink  `xml:"atom:link"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Creator     string   `xml:"dc:creator,omitempty"`
+	Categories  []string `xml:"category"`
+	Description string   `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Link       atomLink       `xml:"link"`
+	Author     *atomPerson    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Content    atomContent    `xml:"content"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// JSONFeedResponse is a JSON Feed 1.1 document
+type JSONFeedResponse struct {
+	Version     string                 `json:"version"`
+	Title       string                 `json:"title"`
+	HomePageURL string                 `json:"home_page_url"`
+	FeedURL     string                 `json:"feed_url"`
+	Description string                 `json:"description"`
+	Items       []JSONFeedItemResponse `json:"items"`
+}
+
+type JSONFeedItemResponse struct {
+	ID            string                   `json:"id"`
+	URL           string                   `json:"url"`
+	Title         string                   `json:"title"`
+	ContentHTML   string                   `json:"content_html"`
+	DatePublished string                   `json:"date_published"`
+	DateModified  string                   `json:"date_modified"`
+	Authors       []JSONFeedAuthorResponse `json:"authors,omitempty"`
+	Tags          []string                 `json:"tags,omitempty"`
+}
+
+type JSONFeedAuthorResponse struct {
+	Name string `json:"name"`
+}
+
+// @Summary Get RSS feed
+// @Tags Feed
+// @Description Get the latest published posts as an RSS 2.0 feed, filtered like the posts listing.
+// @Description Send the returned ETag in If-None-Match or Last-Modified in If-Modified-Since to poll cheaply
+// @ID get-rss-feed
+// @Produce application/rss+xml
+// @Param limit query int false "maximum number of posts in the feed (1-100, defaults to 20)"
+// @Param created_after query string false "only posts created after the RFC 3339 timestamp"
+// @Param created_before query string false "only posts created before the RFC 3339 timestamp"
+// @Param updated_since query string false "only posts updated at or after the RFC 3339 timestamp"
+// @Param title_contains query string false "case-insensitive substring of the title"
+// @Param tag query []string false "only posts with these tags, repeat the parameter for several" collectionFormat(multi)
+// @Param tag_match query string false "whether posts need any or all of the tags, defaults to any" Enums(any, all)
+// @Param If-None-Match header string false "the ETag of the feed the client has"
+// @Param If-Modified-Since header string false "the Last-Modified of the feed the client has"
+// @Success 200 {string} string
+// @Header 200 {string} ETag "the version of the feed"
+// @Header 200 {string} Last-Modified "when a post of the feed last changed"
+// @Success 304
+// @Failure 400 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /feed.rss [get]
+func (s *Server) getRSSFeed(context *gin.Context) {
+	feed, ok := s.loadFeed(context)
+	if !ok {
+		return
+	}
+
+	items := make([]rssItem, 0, len(feed.items))
+	for _, item := range feed.items {
+		items = append(items, rssItem{
+			Title:       item.title,
+			Link:        item.url,
+			GUID:        rssGUID{IsPermaLink: true, Value: item.id},
+			PubDate:     item.published.Format(time.RFC1123Z),
+			Creator:     item.author,
+			Categories:  item.tags,
+			Description: item.content,
+		})
+	}
+
+	respondWithXML(context, rssContentType, rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         feedTitle,
+			Self:          atomLink{Href: feed.feedURL, Rel: "self", Type: strings.Split(rssContentType, ";")[0]},
+			Link:          feed.homeURL,
+			Description:   feedDescription,
+			LastBuildDate: feed.updated.Format(time.RFC1123Z),
+			Items:         items,
+		},
+	})
+}
+
+// @Summary Get Atom feed
+// @Tags Feed
+// @Description Get the latest published posts as an Atom feed, filtered like the posts listing.
+// @Description Send the returned ETag in If-None-Match or Last-Modified in If-Modified-Since to poll cheaply
+// @ID get-atom-feed
+// @Produce application/atom+xml
+// @Param limit query int false "maximum number of posts in the feed (1-100, defaults to 20)"
+// @Param created_after query string false "only posts created after the RFC 3339 timestamp"
+// @Param created_before query string false "only posts created before the RFC 3339 timestamp"
+// @Param updated_since query string false "only posts updated at or after the RFC 3339 timestamp"
+// @Param title_contains query string false "case-insensitive substring of the title"
+// @Param tag query []string false "only posts with these tags, repeat the parameter for several" collectionFormat(multi)
+// @Param tag_match query string false "whether posts need any or all of the tags, defaults to any" Enums(any, all)
+// @Param If-None-Match header string false "the ETag of the feed the client has"
+// @Param If-Modified-Since header string false "the Last-Modified of the feed the client has"
+// @Success 200 {string} string
+// @Header 200 {string} ETag "the version of the feed"
+// @Header 200 {string} Last-Modified "when a post of the feed last changed"
+// @Success 304
+// @Failure 400 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /feed.atom [get]
+func (s *Server) getAtomFeed(context *gin.Context) {
+	feed, ok := s.loadFeed(context)
+	if !ok {
+		return
+	}
+
+	entries := make([]atomEntry, 0, len(feed.items))
+	for _, item := range feed.items {
+		entry := atomEntry{
+			ID:        item.id,
+			Title:     item.title,
+			Published: item.published.Format(time.RFC3339),
+			Updated:   item.updated.Format(time.RFC3339),
+			Link:      atomLink{Href: item.url, Rel: "alternate"},
+			Content:   atomContent{Type: "html", Body: item.content},
+		}
+		if len(item.author) > 0 {
+			entry.Author = &atomPerson{Name: item.author}
+		}
+		for _, tag := range item.tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		entries = append(entries, entry)
+	}
+
+	respondWithXML(context, atomContentType, atomFeed{
+		ID:      feed.feedURL,
+		Title:   feedTitle,
+		Updated: feed.updated.Format(time.RFC3339),
+		// entries without an author of their own are attributed to the feed
+		Author: atomPerson{Name: feedTitle},
+		Links: []atomLink{
+			{Href: feed.feedURL, Rel: "self", Type: strings.Split(atomContentType, ";")[0]},
+			{Href: feed.homeURL, Rel: "alternate"},
+		},
+		Entries: entries,
+	})
+}
+
+// @Summary Get JSON feed
+// @Tags Feed
+// @Description Get the latest published posts as a JSON Feed 1.1, filtered like the posts listing.
+// @Description Send the returned ETag in If-None-Match or Last-Modified in If-Modified-Since to poll cheaply
+// @ID get-json-feed
+// @Produce application/feed+json
+// @Param limit query int false "maximum number of posts in the feed (1-100, defaults to 20)"
+// @Param created_after query string false "only posts created after the RFC 3339 timestamp"
+// @Param created_before query string false "only posts created before the RFC 3339 timestamp"
+// @Param updated_since query string false "only posts updated at or after the RFC 3339 timestamp"
+// @Param title_contains query string false "case-insensitive substring of the title"
+// @Param tag query []string false "only posts with these tags, repeat the parameter for several" collectionFormat(multi)
+// @Param tag_match query string false "whether posts need any or all of the tags, defaults to any" Enums(any, all)
+// @Param If-None-Match header string false "the ETag of the feed the client has"
+// @Param If-Modified-Since header string false "the Last-Modified of the feed the client has"
+// @Success 200 {object} JSONFeedResponse
+// @Header 200 {string} ETag "the version of the feed"
+// @Header 200 {string} Last-Modified "when a post of the feed last changed"
+// @Success 304
+// @Failure 400 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /feed.json [get]
+func (s *Server) getJSONFeed(context *gin.Context) {
+	feed, ok := s.loadFeed(context)
+	if !ok {
+		return
+	}
+
+	items := make([]JSONFeedItemResponse, 0, len(feed.items))
+	for _, item := range feed.items {
+		responseItem := JSONFeedItemResponse{
+			ID:            item.id,
+			URL:           item.url,
+			Title:         item.title,
+			ContentHTML:   item.content,
+			DatePublished: item.published.Format(time.RFC3339),
+			DateModified:  item.updated.Format(time.RFC3339),
+			Tags:          item.tags,
+		}
+		if len(item.author) > 0 {
+			responseItem.Authors = []JSONFeedAuthorResponse{{Name: item.author}}
+		}
+		items = append(items, responseItem)
+	}
+
+	data, err := json.Marshal(JSONFeedResponse{
+		Version:     jsonFeedVersion,
+		Title:       feedTitle,
+		HomePageURL: feed.homeURL,
+		FeedURL:     feed.feedURL,
+		Description: feedDescription,
+		Items:       items,
+	})
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	context.Data(http.StatusOK, jsonFeedContentType, data)
+}
+
+// loadFeed reads the latest published posts matching the query into a feed. It returns false once it has responded itself,
+// either with an error or with Not Modified when the client already has the current feed.
+func (s *Server) loadFeed(context *gin.Context) (feed, bool) {
+	var request feedRequest
+
+	if err := context.ShouldBindQuery(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return feed{}, false
+	}
+	if request.Limit == 0 {
+		request.Limit = defaultPageSize
+	}
+
+	listRequest := listPostsRequest{Sort: feedSort, Status: string(db.PostStatusPublished), postFilters: request.postFilters}
+	arg, err := listRequest.toListPostsParams()
+	if err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return feed{}, false
+	}
+	arg.PageSize = int32(request.Limit)
+
+	posts, err := s.queryPosts(context, arg)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return feed{}, false
+	}
+
+	authorIDs := make([]sql.NullInt32, 0, len(posts))
+	postIDs := make([]int32, 0, len(posts))
+	for _, post := range posts {
+		authorIDs = append(authorIDs, post.AuthorID)
+		postIDs = append(postIDs, post.ID)
+	}
+	authors, err := s.loadAuthors(context, authorIDs...)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return feed{}, false
+	}
+
+	etag, lastModified := feedValidators(posts, authors)
+	context.Header(etagHeader, etag)
+	if !lastModified.IsZero() {
+		context.Header(lastModifiedHeader, lastModified.UTC().Format(http.TimeFormat))
+	}
+	if notModified(context, etag, lastModified) {
+		context.Status(http.StatusNotModified)
+		return feed{}, false
+	}
+
+	tags, err := s.loadTags(context, postIDs)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return feed{}, false
+	}
+
+	result := feed{
+		homeURL: s.absoluteURL("/posts"),
+		feedURL: s.absoluteURL(context.Request.URL.RequestURI()),
+		updated: lastModified,
+		items:   make([]feedItem, 0, len(posts)),
+	}
+	if result.updated.IsZero() {
+		result.updated = s.clock.Now()
+	}
+	for _, post := range posts {
+		item := feedItem{
+			id:        s.absoluteURL("/posts/" + strconv.Itoa(int(post.ID))),
+			url:       s.absoluteURL(postSlugPath(post.Slug)),
+			title:     post.Title,
+			content:   post.ContentHtml,
+			tags:      tags[post.ID],
+			published: post.CreatedAt,
+			updated:   post.UpdatedAt,
+		}
+		if post.PublishedAt.Valid {
+			item.published = post.PublishedAt.Time
+		}
+		if author, ok := authors[post.AuthorID.Int32]; ok && post.AuthorID.Valid {
+			item.author = author.Name
+		}
+		result.items = append(result.items, item)
+	}
+	return result, true
+}
+
+// feedValidators derive the ETag and Last-Modified of a feed from its posts and their authors, so that a feed
+// that did not change is answered without loading anything else. Every write to a post moves its version
+// or its updated_at, and every write to an author its updated_at.
+func feedValidators(posts []db.Post, authors map[int32]db.Author) (string, time.Time) {
+	var lastModified time.Time
+	hash := sha256.New()
+	for _, post := range posts {
+		fmt.Fprintf(hash, "%d:%d:%d;", post.ID, post.Version, post.UpdatedAt.UnixNano())
+		if post.UpdatedAt.After(lastModified) {
+			lastModified = post.UpdatedAt
+		}
+
+		author, ok := authors[post.AuthorID.Int32]
+		if !post.AuthorID.Valid || !ok {
+			continue
+		}
+		fmt.Fprintf(hash, "%d:%d;", author.ID, author.UpdatedAt.UnixNano())
+		if author.UpdatedAt.After(lastModified) {
+			lastModified = author.UpdatedAt
+		}
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`, lastModified
+}
+
+func respondWithXML(context *gin.Context, contentType string, document interface{}) {
+	data, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	context.Data(http.StatusOK, contentType, append([]byte(xml.Header), data...))
+}
@@ -0,0 +1,316 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"testing"
+	"time"
+)
+
+func TestGetFeeds(t *testing.T) {
+	randomAuthor := generateRandomAuthor()
+	randomAuthor.UpdatedAt = testNow.Add(-2 * time.Hour)
+	randomPost := generateRandomPost()
+	randomPost.Status = db.PostStatusPublished
+	randomPost.AuthorID = sql.NullInt32{Int32: randomAuthor.ID, Valid: true}
+	randomPost.PublishedAt = sql.NullTime{Time: testNow.Add(-time.Hour), Valid: true}
+	randomPost.UpdatedAt = testNow.Add(-time.Minute)
+	olderPost := generateRandomPost()
+	olderPost.Status = db.PostStatusPublished
+	olderPost.UpdatedAt = testNow.Add(-time.Hour)
+
+	posts := []db.Post{randomPost, olderPost}
+	etag, _ := feedValidators(posts, map[int32]db.Author{randomAuthor.ID: randomAuthor})
+	lastModified := randomPost.UpdatedAt.UTC().Format(http.TimeFormat)
+	listArg := db.ListPostsByCreatedAtDescParams{Status: db.PostStatusPublished, PageSize: defaultPageSize}
+	postURL := fmt.Sprintf("%s/posts/by-slug/%s", testBaseURL, randomPost.Slug)
+	postID := fmt.Sprintf("%s/posts/%d", testBaseURL, randomPost.ID)
+
+	buildFeedStubs := func(querier *mockdb.MockStore) {
+		querier.EXPECT().
+			ListPostsByCreatedAtDesc(gomock.Any(), gomock.Eq(listArg)).
+			Times(1).
+			Return(posts, nil)
+		querier.EXPECT().
+			GetAuthorsByIds(gomock.Any(), gomock.Eq([]int32{randomAuthor.ID})).
+			Times(1).
+			Return([]db.Author{randomAuthor}, nil)
+		querier.EXPECT().
+			GetTagsByPostIds(gomock.Any(), gomock.Eq([]int32{randomPost.ID, olderPost.ID})).
+			Times(1).
+			Return([]db.GetTagsByPostIdsRow{{PostID: randomPost.ID, ID: 1, Name: "go"}}, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		path          string
+		header        http.Header
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "positive_GetRSSFeed",
+			path:       "/feed.rss",
+			buildStubs: buildFeedStubs,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, rssContentType, recorder.Header().Get("Content-Type"))
+				require.Equal(t, etag, recorder.Header().Get(etagHeader))
+				require.Equal(t, lastModified, recorder.Header().Get(lastModifiedHeader))
+
+				var feed struct {
+					Channel struct {
+						Link  string `xml:"link"`
+						Items []struct {
+							Title       string   `xml:"title"`
+							Link        string   `xml:"link"`
+							GUID        string   `xml:"guid"`
+							PubDate     string   `xml:"pubDate"`
+							Creator     string   `xml:"creator"`
+							Categories  []string `xml:"category"`
+							Description string   `xml:"description"`
+						} `xml:"item"`
+					} `xml:"channel"`
+				}
+				require.NoError(t, xml.Unmarshal(recorder.Body.Bytes(), &feed))
+				require.Equal(t, testBaseURL+"/posts", feed.Channel.Link)
+				require.Len(t, feed.Channel.Items, 2)
+
+				item := feed.Channel.Items[0]
+				require.Equal(t, randomPost.Title, item.Title)
+				require.Equal(t, postURL, item.Link)
+				require.Equal(t, postID, item.GUID)
+				require.Equal(t, randomPost.PublishedAt.Time.Format(time.RFC1123Z), item.PubDate)
+				require.Equal(t, randomAuthor.Name, item.Creator)
+				require.Equal(t, []string{"go"}, item.Categories)
+				require.Equal(t, randomPost.ContentHtml, item.Description)
+				require.Equal(t, olderPost.CreatedAt.Format(time.RFC1123Z), feed.Channel.Items[1].PubDate)
+			},
+		},
+		{
+			name:       "positive_GetAtomFeed",
+			path:       "/feed.atom",
+			buildStubs: buildFeedStubs,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, atomContentType, recorder.Header().Get("Content-Type"))
+
+				var feed atomFeed
+				require.NoError(t, xml.Unmarshal(recorder.Body.Bytes(), &feed))
+				require.Equal(t, testBaseURL+"/feed.atom", feed.ID)
+				require.Equal(t, randomPost.UpdatedAt.Format(time.RFC3339), feed.Updated)
+				require.Len(t, feed.Entries, 2)
+
+				entry := feed.Entries[0]
+				require.Equal(t, postID, entry.ID)
+				require.Equal(t, postURL, entry.Link.Href)
+				require.Equal(t, &atomPerson{Name: randomAuthor.Name}, entry.Author)
+				require.Equal(t, []atomCategory{{Term: "go"}}, entry.Categories)
+				require.Equal(t, atomContent{Type: "html", Body: randomPost.ContentHtml}, entry.Content)
+				require.Nil(t, feed.Entries[1].Author)
+			},
+		},
+		{
+			name:       "positive_GetJSONFeed",
+			path:       "/feed.json",
+			buildStubs: buildFeedStubs,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, jsonFeedContentType, recorder.Header().Get("Content-Type"))
+
+				var feed JSONFeedResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &feed))
+				require.Equal(t, jsonFeedVersion, feed.Version)
+				require.Equal(t, testBaseURL+"/feed.json", feed.FeedURL)
+				require.Equal(t, []JSONFeedItemResponse{
+					{
+						ID:            postID,
+						URL:           postURL,
+						Title:         randomPost.Title,
+						ContentHTML:   randomPost.ContentHtml,
+						DatePublished: randomPost.PublishedAt.Time.Format(time.RFC3339),
+						DateModified:  randomPost.UpdatedAt.Format(time.RFC3339),
+						Authors:       []JSONFeedAuthorResponse{{Name: randomAuthor.Name}},
+						Tags:          []string{"go"},
+					},
+					{
+						ID:            fmt.Sprintf("%s/posts/%d", testBaseURL, olderPost.ID),
+						URL:           fmt.Sprintf("%s/posts/by-slug/%s", testBaseURL, olderPost.Slug),
+						Title:         olderPost.Title,
+						ContentHTML:   olderPost.ContentHtml,
+						DatePublished: olderPost.CreatedAt.Format(time.RFC3339),
+						DateModified:  olderPost.UpdatedAt.Format(time.RFC3339),
+					},
+				}, feed.Items)
+			},
+		},
+		{
+			name: "positive_GetJSONFeed_Filtered",
+			path: "/feed.json?limit=5&tag=Go&title_contains=release",
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListPostsByCreatedAtDescParams{
+					Status:        db.PostStatusPublished,
+					TitleContains: sql.NullString{String: "release", Valid: true},
+					Tags:          []string{"go"},
+					TagMatch:      defaultTagMatch,
+					PageSize:      5,
+				}
+				querier.EXPECT().
+					ListPostsByCreatedAtDesc(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Post{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, recorder.Header().Get(lastModifiedHeader))
+
+				var feed JSONFeedResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &feed))
+				require.Equal(t, testBaseURL+"/feed.json?limit=5&tag=Go&title_contains=release", feed.FeedURL)
+				require.Empty(t, feed.Items)
+			},
+		},
+		{
+			name:   "positive_GetRSSFeed_NotModifiedETag",
+			path:   "/feed.rss",
+			header: http.Header{ifNoneMatchHeader: {`"other", W/` + etag}},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListPostsByCreatedAtDesc(gomock.Any(), gomock.Eq(listArg)).
+					Times(1).
+					Return(posts, nil)
+				querier.EXPECT().
+					GetAuthorsByIds(gomock.Any(), gomock.Eq([]int32{randomAuthor.ID})).
+					Times(1).
+					Return([]db.Author{randomAuthor}, nil)
+				querier.EXPECT().
+					GetTagsByPostIds(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotModified, recorder.Code)
+				require.Equal(t, etag, recorder.Header().Get(etagHeader))
+				require.Empty(t, recorder.Body.Bytes())
+			},
+		},
+		{
+			name:   "positive_GetAtomFeed_NotModifiedSince",
+			path:   "/feed.atom",
+			header: http.Header{ifModifiedSinceHeader: {lastModified}},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListPostsByCreatedAtDesc(gomock.Any(), gomock.Eq(listArg)).
+					Times(1).
+					Return(posts, nil)
+				querier.EXPECT().
+					GetAuthorsByIds(gomock.Any(), gomock.Eq([]int32{randomAuthor.ID})).
+					Times(1).
+					Return([]db.Author{randomAuthor}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotModified, recorder.Code)
+			},
+		},
+		{
+			name:   "positive_GetRSSFeed_AuthorRenamed",
+			path:   "/feed.rss",
+			header: http.Header{ifNoneMatchHeader: {etag}},
+			buildStubs: func(querier *mockdb.MockStore) {
+				renamedAuthor := randomAuthor
+				renamedAuthor.Name = "Renamed author"
+				renamedAuthor.UpdatedAt = testNow
+
+				querier.EXPECT().
+					ListPostsByCreatedAtDesc(gomock.Any(), gomock.Eq(listArg)).
+					Times(1).
+					Return(posts, nil)
+				querier.EXPECT().
+					GetAuthorsByIds(gomock.Any(), gomock.Eq([]int32{randomAuthor.ID})).
+					Times(1).
+					Return([]db.Author{renamedAuthor}, nil)
+				querier.EXPECT().
+					GetTagsByPostIds(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.GetTagsByPostIdsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotEqual(t, etag, recorder.Header().Get(etagHeader))
+				require.Equal(t, testNow.UTC().Format(http.TimeFormat), recorder.Header().Get(lastModifiedHeader))
+				require.Contains(t, recorder.Body.String(), "Renamed author")
+			},
+		},
+		{
+			name:       "positive_GetAtomFeed_ModifiedSince",
+			path:       "/feed.atom",
+			header:     http.Header{ifModifiedSinceHeader: {randomPost.UpdatedAt.Add(-time.Minute).UTC().Format(http.TimeFormat)}},
+			buildStubs: buildFeedStubs,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "positive_GetRSSFeed_ETagChanged",
+			path:       "/feed.rss",
+			header:     http.Header{ifNoneMatchHeader: {`"stale"`}, ifModifiedSinceHeader: {lastModified}},
+			buildStubs: buildFeedStubs,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "negative_GetRSSFeed_InvalidLimit",
+			path: "/feed.rss?limit=1000",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListPostsByCreatedAtDesc(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "negative_GetJSONFeed_InternalError",
+			path: "/feed.json",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListPostsByCreatedAtDesc(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, testCase.path, nil)
+			require.NoError(t, err)
+			for key, values := range testCase.header {
+				request.Header[key] = values
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
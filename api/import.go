@@ -0,0 +1,359 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"io"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	importAccepted  = "accepted"
+	importRejected  = "rejected"
+	importDuplicate = "duplicate"
+
+	// importBatchSize is how many valid records are copied into the database at a time
+	importBatchSize = 500
+	// importMaxLineSize is the longest line of an NDJSON upload, which holds a whole post
+	importMaxLineSize = 4 << 20
+)
+
+var errImportColumnsMissing = errors.New("csv header must name the title and content columns")
+
+type importPostsRequest struct {
+	Format string `form:"format" binding:"required,oneof=ndjson csv"`
+	DryRun bool   `form:"dryRun"`
+}
+
+// ImportPostsResponse is the report of an import, with a row for every record of the upload in order
+type ImportPostsResponse struct {
+	DryRun     bool                `json:"dryRun"`
+	Accepted   int                 `json:"accepted"`
+	Rejected   int                 `json:"rejected"`
+	Duplicates int                 `json:"duplicates"`
+	Rows       []ImportRowResponse `json:"rows"`
+}
+
+// ImportRowResponse is the outcome of the record at a line of the upload: accepted with the id of the new post,
+// rejected with the reason, or a duplicate of an existing post or an earlier record with the same title and content
+type ImportRowResponse struct {
+	Line   int    `json:"line"`
+	Status string `json:"status"`
+	ID     int    `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// importRecord is a record of an upload read into a createPostRequest, or the error it could not be read with
+type importRecord struct {
+	line    int
+	request createPostRequest
+	err     error
+}
+
+// importedPost is a valid record waiting in a batch, with the index of its row in the report
+type importedPost struct {
+	row int
+	arg db.CreatePostTxParams
+}
+
+// postImportReader reads the records of an upload one at a time, returning io.EOF after the last one.
+// Any other error means the upload cannot be read any further.
+type postImportReader interface {
+	read() (importRecord, error)
+}
+
+type ndjsonImportReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (r *ndjsonImportReader) read() (importRecord, error) {
+	for r.scanner.Scan() {
+		r.line++
+		data := bytes.TrimSpace(r.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		record := importRecord{line: r.line}
+		record.err = json.Unmarshal(data, &record.request)
+		return record, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return importRecord{}, err
+	}
+	return importRecord{}, io.EOF
+}
+
+// csvImportReader reads records by the columns of the header named like the fields of createPostRequest,
+// ignoring the other ones so that an export can be imported back. Tags are separated by commas.
+type csvImportReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func (r *csvImportReader) read() (importRecord, error) {
+	values, err := r.reader.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return importRecord{line: parseErr.StartLine, err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return importRecord{}, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	record := importRecord{line: line}
+	record.request, record.err = r.toCreatePostRequest(values)
+	return record, nil
+}
+
+func (r *csvImportReader) toCreatePostRequest(values []string) (createPostRequest, error) {
+	value := func(column string) string {
+		if i, ok := r.columns[column]; ok {
+			return values[i]
+		}
+		return ""
+	}
+	optionalID := func(column string) (*int, error) {
+		if len(value(column)) == 0 {
+			return nil, nil
+		}
+		id, err := strconv.Atoi(value(column))
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a number: %q", column, value(column))
+		}
+		return &id, nil
+	}
+
+	request := createPostRequest{
+		Title:         value("title"),
+		Content:       value("content"),
+		ContentFormat: value("contentFormat"),
+	}
+	if publishAt := value("publishAt"); len(publishAt) > 0 {
+		parsed, err := time.Parse(time.RFC3339, publishAt)
+		if err != nil {
+			return request, fmt.Errorf("publishAt is not an RFC 3339 timestamp: %q", publishAt)
+		}
+		request.PublishAt = &parsed
+	}
+	var err error
+	if request.AuthorID, err = optionalID("authorId"); err != nil {
+		return request, err
+	}
+	if request.CategoryID, err = optionalID("categoryId"); err != nil {
+		return request, err
+	}
+	if tags := value("tags"); len(tags) > 0 {
+		request.Tags = strings.Split(tags, ",")
+	}
+	return request, nil
+}
+
+// newPostImportReader starts reading an upload in the format, a CSV upload from its header.
+func newPostImportReader(format string, body io.Reader) (postImportReader, error) {
+	if format == exportFormatNDJSON {
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(nil, importMaxLineSize)
+		return &ndjsonImportReader{scanner: scanner}, nil
+	}
+
+	reader := csv.NewReader(body)
+	header, err := reader.Read()
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.TrimSpace(column)] = i
+	}
+	_, hasTitle := columns["title"]
+	_, hasContent := columns["content"]
+	if !hasTitle || !hasContent {
+		return nil, errImportColumnsMissing
+	}
+	reader.FieldsPerRecord = len(header)
+	return &csvImportReader{reader: reader, columns: columns}, nil
+}
+
+// @Summary Import posts
+// @Tags Post
+// @Description Create posts from an NDJSON or CSV upload, optionally gzipped with its Content-Encoding. Every record
+// @Description is checked like the body of POST /posts, a CSV upload naming its fields in the header, and the valid ones
+// @Description are copied into the database in batches. Records with the title and content of an existing post
+// @Description or an earlier record are skipped as duplicates, so an import that failed midway can be run again.
+// @Description The report tells the outcome of every record by its line. A dry run checks the records without writing
+// @ID import-posts
+// @Accept application/x-ndjson,text/csv
+// @Produce json
+// @Param format query string true "the format of the upload" Enums(ndjson, csv)
+// @Param dryRun query bool false "check the records without creating posts"
+// @Param input body string true "the records, an NDJSON line or a CSV row each"
+// @Success 200 {object} ImportPostsResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/import [post]
+func (s *Server) importPosts(context *gin.Context) {
+	var request importPostsRequest
+
+	if err := context.ShouldBindQuery(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var body io.Reader = context.Request.Body
+	if context.GetHeader("Content-Encoding") == "gzip" {
+		decompressor, err := gzip.NewReader(body)
+		if err != nil {
+			context.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		defer decompressor.Close()
+		body = decompressor
+	}
+	reader, err := newPostImportReader(request.Format, body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	response := ImportPostsResponse{DryRun: request.DryRun, Rows: []ImportRowResponse{}}
+	seen := make(map[[sha256.Size]byte]bool)
+	var batch []importedPost
+	for {
+		record, err := reader.read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			context.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		row := ImportRowResponse{Line: record.line}
+		arg, err := s.toImportPostParams(record)
+		if err != nil {
+			row.Status, row.Error = importRejected, err.Error()
+			response.Rows = append(response.Rows, row)
+			continue
+		}
+		key := sha256.Sum256([]byte(arg.Title + "\x00" + arg.Content))
+		if seen[key] {
+			row.Status = importDuplicate
+			response.Rows = append(response.Rows, row)
+			continue
+		}
+		seen[key] = true
+
+		batch = append(batch, importedPost{row: len(response.Rows), arg: arg})
+		response.Rows = append(response.Rows, row)
+		if len(batch) == importBatchSize {
+			if err := s.importBatch(context, request.DryRun, batch, response.Rows); err != nil {
+				context.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := s.importBatch(context, request.DryRun, batch, response.Rows); err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	for _, row := range response.Rows {
+		switch row.Status {
+		case importAccepted:
+			response.Accepted++
+		case importRejected:
+			response.Rejected++
+		default:
+			response.Duplicates++
+		}
+	}
+	context.JSON(http.StatusOK, response)
+}
+
+// toImportPostParams checks a record with the rules of createPostRequest and turns it into a new post like createPost.
+func (s *Server) toImportPostParams(record importRecord) (db.CreatePostTxParams, error) {
+	if record.err != nil {
+		return db.CreatePostTxParams{}, record.err
+	}
+	if err := binding.Validator.ValidateStruct(&record.request); err != nil {
+		return db.CreatePostTxParams{}, err
+	}
+	return s.toCreatePostParams(record.request)
+}
+
+// importBatch imports the posts of a batch, or only looks for the duplicates among them in a dry run,
+// and fills in their rows of the report. Posts referring to a missing author or category are rejected.
+func (s *Server) importBatch(context *gin.Context, dryRun bool, batch []importedPost, rows []ImportRowResponse) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	operations := make([]db.PostOperation, len(batch))
+	failures := make([]error, len(batch))
+	for i := range batch {
+		operations[i] = db.PostOperation{Create: &batch[i].arg}
+	}
+	if err := s.checkReferences(context, operations, failures); err != nil {
+		return err
+	}
+
+	var posts []db.CreatePostTxParams
+	var indexes []int
+	for i, failure := range failures {
+		if failure != nil {
+			rows[batch[i].row].Status, rows[batch[i].row].Error = importRejected, failure.Error()
+			continue
+		}
+		posts = append(posts, batch[i].arg)
+		indexes = append(indexes, batch[i].row)
+	}
+	if len(posts) == 0 {
+		return nil
+	}
+
+	results := make([]db.ImportPostResult, len(posts))
+	if dryRun {
+		arg := db.ListDuplicatePostsParams{}
+		for _, post := range posts {
+			arg.Titles = append(arg.Titles, post.Title)
+			arg.Contents = append(arg.Contents, post.Content)
+		}
+		duplicates, err := s.store.ListDuplicatePosts(context, arg)
+		if err != nil {
+			return err
+		}
+		for _, position := range duplicates {
+			results[position-1].Duplicate = true
+		}
+	} else {
+		var err error
+		if results, err = s.store.ImportPostsTx(context, posts); err != nil {
+			return err
+		}
+	}
+
+	for n, result := range results {
+		row := &rows[indexes[n]]
+		if result.Duplicate {
+			row.Status = importDuplicate
+			continue
+		}
+		row.Status, row.ID = importAccepted, int(result.ID)
+	}
+	return nil
+}
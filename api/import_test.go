@@ -0,0 +1,280 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"strings"
+	"testing"
+)
+
+func TestImportPosts(t *testing.T) {
+	ndjson := strings.Join([]string{
+		`{"title": "First post", "content": "Hello", "tags": ["Go"]}`,
+		`{"title": "No content"}`,
+		``,
+		`{"title": "First post", "content": "Hello"}`,
+		`{"title": "Old post", "content": "Imported before", "contentFormat": "markdown"}`,
+		`{"title": "Broken"`,
+	}, "\n")
+	csvBody := strings.Join([]string{
+		`id,title,content,contentFormat,tags`,
+		`1,First post,"Hello,`,
+		`world",plain,"go,sql"`,
+		`2,Second post,Hello,rst,`,
+		`3,Short row`,
+		`4,Third post,Hi,markdown,`,
+	}, "\n")
+
+	var many strings.Builder
+	for i := 0; i <= importBatchSize; i++ {
+		fmt.Fprintf(&many, `{"title": "Post %d", "content": "Hello"}`+"\n", i)
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		body          string
+		gzip          bool
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "positive_ImportPosts_NDJSON",
+			query: "?format=ndjson",
+			body:  ndjson,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ImportPostsTx(gomock.Any(), gomock.Len(2)).
+					Times(1).
+					DoAndReturn(func(_ context.Context, posts []db.CreatePostTxParams) ([]db.ImportPostResult, error) {
+						require.Equal(t, "First post", posts[0].Title)
+						require.Equal(t, []string{"go"}, posts[0].Tags)
+						require.Equal(t, db.ContentFormatMarkdown, posts[1].ContentFormat)
+						require.Equal(t, "<p>Imported before</p>\n", posts[1].ContentHtml)
+						return []db.ImportPostResult{{ID: 10}, {Duplicate: true}}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				response := requireBodyMatchImport(t, recorder.Body, 1, 2, 2)
+				require.Equal(t, []ImportRowResponse{
+					{Line: 1, Status: importAccepted, ID: 10},
+					{Line: 2, Status: importRejected, Error: response.Rows[1].Error},
+					{Line: 4, Status: importDuplicate},
+					{Line: 5, Status: importDuplicate},
+					{Line: 6, Status: importRejected, Error: "unexpected end of JSON input"},
+				}, response.Rows)
+				require.Contains(t, response.Rows[1].Error, "Content")
+			},
+		},
+		{
+			name:  "positive_ImportPosts_CSV",
+			query: "?format=csv",
+			body:  csvBody,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ImportPostsTx(gomock.Any(), gomock.Len(2)).
+					Times(1).
+					DoAndReturn(func(_ context.Context, posts []db.CreatePostTxParams) ([]db.ImportPostResult, error) {
+						require.Equal(t, "Hello,\nworld", posts[0].Content)
+						require.Equal(t, []string{"go", "sql"}, posts[0].Tags)
+						require.Equal(t, "Third post", posts[1].Title)
+						return []db.ImportPostResult{{ID: 10}, {ID: 11}}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				response := requireBodyMatchImport(t, recorder.Body, 2, 2, 0)
+				require.Equal(t, []int{2, 4, 5, 6}, importLines(response))
+				require.Equal(t, importRejected, response.Rows[1].Status)
+				require.Contains(t, response.Rows[2].Error, "wrong number of fields")
+				require.Equal(t, 11, response.Rows[3].ID)
+			},
+		},
+		{
+			name:  "positive_ImportPosts_DryRun",
+			query: "?format=ndjson&dryRun=true",
+			body:  ndjson,
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListDuplicatePostsParams{
+					Titles:   []string{"First post", "Old post"},
+					Contents: []string{"Hello", "Imported before"},
+				}
+				querier.EXPECT().
+					ListDuplicatePosts(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]int32{2}, nil)
+				querier.EXPECT().
+					ImportPostsTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				response := requireBodyMatchImport(t, recorder.Body, 1, 2, 2)
+				require.True(t, response.DryRun)
+				require.Equal(t, ImportRowResponse{Line: 1, Status: importAccepted}, response.Rows[0])
+			},
+		},
+		{
+			name:  "positive_ImportPosts_Gzip",
+			query: "?format=ndjson",
+			body:  `{"title": "First post", "content": "Hello"}`,
+			gzip:  true,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ImportPostsTx(gomock.Any(), gomock.Len(1)).
+					Times(1).
+					Return([]db.ImportPostResult{{ID: 10}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchImport(t, recorder.Body, 1, 0, 0)
+			},
+		},
+		{
+			name:  "positive_ImportPosts_Batches",
+			query: "?format=ndjson",
+			body:  many.String(),
+			buildStubs: func(querier *mockdb.MockStore) {
+				gomock.InOrder(
+					querier.EXPECT().
+						ImportPostsTx(gomock.Any(), gomock.Len(importBatchSize)).
+						Times(1).
+						Return(make([]db.ImportPostResult, importBatchSize), nil),
+					querier.EXPECT().
+						ImportPostsTx(gomock.Any(), gomock.Len(1)).
+						Times(1).
+						Return([]db.ImportPostResult{{ID: 10}}, nil),
+				)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchImport(t, recorder.Body, importBatchSize+1, 0, 0)
+			},
+		},
+		{
+			name:  "negative_ImportPosts_UnknownAuthor",
+			query: "?format=ndjson",
+			body:  `{"title": "First post", "content": "Hello", "authorId": 7}`,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetAuthorsByIds(gomock.Any(), gomock.Eq([]int32{7})).
+					Times(1).
+					Return([]db.Author{}, nil)
+				querier.EXPECT().
+					ImportPostsTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				response := requireBodyMatchImport(t, recorder.Body, 0, 1, 0)
+				require.Equal(t, errAuthorNotFound.Error(), response.Rows[0].Error)
+			},
+		},
+		{
+			name:  "negative_ImportPosts_MissingColumns",
+			query: "?format=csv",
+			body:  "title,body\nFirst post,Hello",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ImportPostsTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errImportColumnsMissing.Error()})
+			},
+		},
+		{
+			name:  "negative_ImportPosts_InvalidFormat",
+			query: "?format=xml",
+			body:  ndjson,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ImportPostsTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "negative_ImportPosts_InternalError",
+			query: "?format=ndjson",
+			body:  ndjson,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ImportPostsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			var body bytes.Buffer
+			if testCase.gzip {
+				compressor := gzip.NewWriter(&body)
+				_, err := compressor.Write([]byte(testCase.body))
+				require.NoError(t, err)
+				require.NoError(t, compressor.Close())
+			} else {
+				body.WriteString(testCase.body)
+			}
+
+			request, err := http.NewRequest(http.MethodPost, "/posts/import"+testCase.query, &body)
+			require.NoError(t, err)
+			if testCase.gzip {
+				request.Header.Set("Content-Encoding", "gzip")
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func requireBodyMatchImport(t *testing.T, body *bytes.Buffer, accepted, rejected, duplicates int) ImportPostsResponse {
+	var response ImportPostsResponse
+	require.NoError(t, json.Unmarshal(body.Bytes(), &response))
+
+	require.Equal(t, accepted, response.Accepted)
+	require.Equal(t, rejected, response.Rejected)
+	require.Equal(t, duplicates, response.Duplicates)
+	require.Len(t, response.Rows, accepted+rejected+duplicates)
+	return response
+}
+
+func importLines(response ImportPostsResponse) []int {
+	lines := make([]int, 0, len(response.Rows))
+	for _, row := range response.Rows {
+		lines = append(lines, row.Line)
+	}
+	return lines
+}
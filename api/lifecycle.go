@@ -0,0 +1,118 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+)
+
+var errConcurrentStatusChange = errors.New("post status was changed concurrently, retry the request")
+
+// postTransitions lists for every target status the statuses a post may move to it from.
+var postTransitions = map[db.PostStatus][]db.PostStatus{
+	db.PostStatusPublished: {db.PostStatusDraft},
+	db.PostStatusDraft:     {db.PostStatusPublished, db.PostStatusArchived},
+	db.PostStatusArchived:  {db.PostStatusDraft, db.PostStatusPublished},
+}
+
+func canTransition(from db.PostStatus, to db.PostStatus) bool {
+	for _, status := range postTransitions[to] {
+		if status == from {
+			return true
+		}
+	}
+	return false
+}
+
+// @Summary Publish post
+// @Tags Post
+// @Description Make a draft post public
+// @ID publish-post
+// @Produce json
+// @Param id path string true "the specific post id"
+// @Success 200 {object} PostResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/{id}/publish [post]
+func (s *Server) publishPost(context *gin.Context) {
+	s.transitionPost(context, db.PostStatusPublished)
+}
+
+// @Summary Unpublish post
+// @Tags Post
+// @Description Move a published or archived post back to drafts
+// @ID unpublish-post
+// @Produce json
+// @Param id path string true "the specific post id"
+// @Success 200 {object} PostResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/{id}/unpublish [post]
+func (s *Server) unpublishPost(context *gin.Context) {
+	s.transitionPost(context, db.PostStatusDraft)
+}
+
+// @Summary Archive post
+// @Tags Post
+// @Description Archive a draft or published post
+// @ID archive-post
+// @Produce json
+// @Param id path string true "the specific post id"
+// @Success 200 {object} PostResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/{id}/archive [post]
+func (s *Server) archivePost(context *gin.Context) {
+	s.transitionPost(context, db.PostStatusArchived)
+}
+
+func (s *Server) transitionPost(context *gin.Context, to db.PostStatus) {
+	var request getPostRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	post, err := s.store.GetPostById(context, int32(request.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			context.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !canTransition(post.Status, to) {
+		err = fmt.Errorf("cannot move %s post to %s", post.Status, to)
+		context.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	// the update only matches while the post keeps the status checked above
+	post, err = s.store.UpdatePostStatus(context, db.UpdatePostStatusParams{
+		ToStatus:   to,
+		ID:         post.ID,
+		FromStatus: post.Status,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			context.JSON(http.StatusConflict, errorResponse(errConcurrentStatusChange))
+			return
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	s.respondWithPost(context, post)
+}
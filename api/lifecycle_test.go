@@ -0,0 +1,172 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"testing"
+	"time"
+)
+
+func TestTransitionPost(t *testing.T) {
+	randomPost := generateRandomPost()
+
+	publishedPost := randomPost
+	publishedPost.Status = db.PostStatusPublished
+	publishedPost.PublishedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	archivedPost := randomPost
+	archivedPost.Status = db.PostStatusArchived
+
+	testCases := []struct {
+		name          string
+		action        string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "positive_PublishPost",
+			action: "publish",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+
+				arg := db.UpdatePostStatusParams{
+					ToStatus:   db.PostStatusPublished,
+					ID:         randomPost.ID,
+					FromStatus: db.PostStatusDraft,
+				}
+				querier.EXPECT().
+					UpdatePostStatus(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(publishedPost, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPost(t, recorder.Body, mapToPostResponse(publishedPost))
+			},
+		},
+		{
+			name:   "positive_UnpublishArchivedPost",
+			action: "unpublish",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(archivedPost, nil)
+
+				arg := db.UpdatePostStatusParams{
+					ToStatus:   db.PostStatusDraft,
+					ID:         randomPost.ID,
+					FromStatus: db.PostStatusArchived,
+				}
+				querier.EXPECT().
+					UpdatePostStatus(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(randomPost, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPost(t, recorder.Body, mapToPostResponse(randomPost))
+			},
+		},
+		{
+			name:   "negative_PublishArchivedPost_IllegalTransition",
+			action: "publish",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(archivedPost, nil)
+
+				querier.EXPECT().
+					UpdatePostStatus(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: "cannot move archived post to published"})
+			},
+		},
+		{
+			name:   "negative_ArchivePost_ConcurrentChange",
+			action: "archive",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(publishedPost, nil)
+
+				querier.EXPECT().
+					UpdatePostStatus(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Post{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errConcurrentStatusChange.Error()})
+			},
+		},
+		{
+			name:   "negative_PublishPost_PostNotFound",
+			action: "publish",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(db.Post{}, sql.ErrNoRows)
+
+				querier.EXPECT().
+					UpdatePostStatus(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "negative_PublishPost_InternalError",
+			action: "publish",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+
+				querier.EXPECT().
+					UpdatePostStatus(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Post{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/posts/%d/%s", randomPost.ID, testCase.action)
+			request, err := http.NewRequest(http.MethodPost, requestUrl, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
@@ -1,11 +1,125 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"io"
 	"os"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"promova-test-task/util"
 	"testing"
+	"time"
 )
 
+const (
+	testAdminToken        = "test-admin-token"
+	testAttachmentMaxSize = 1 << 10
+	testCoverMaxSize      = 4 << 10
+	testBaseURL           = "https://news.example.com"
+)
+
+var testNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+// testViewRecorder keeps the recorded views in memory, in the order they were recorded
+type testViewRecorder struct {
+	postIDs []int32
+}
+
+func (r *testViewRecorder) RecordView(postID int32) {
+	r.postIDs = append(r.postIDs, postID)
+}
+
+// testBlobStore keeps the blobs in memory
+type testBlobStore struct {
+	blobs map[string][]byte
+	err   error
+}
+
+func newTestBlobStore() *testBlobStore {
+	return &testBlobStore{blobs: make(map[string][]byte)}
+}
+
+func (s *testBlobStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	if s.err != nil {
+		return s.err
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	s.blobs[key] = data
+	return nil
+}
+
+func (s *testBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := s.blobs[key]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *testBlobStore) Delete(ctx context.Context, key string) error {
+	delete(s.blobs, key)
+	return s.err
+}
+
+func (s *testBlobStore) URL(key string) string {
+	return "/media/" + key
+}
+
+func newTestServer(store db.Store) *Server {
+	config := util.Config{
+		BaseURL:           testBaseURL,
+		AdminToken:        testAdminToken,
+		AttachmentMaxSize: testAttachmentMaxSize,
+		CoverMaxSize:      testCoverMaxSize,
+	}
+
+	// posts have no tags, comments, reactions, views and attachments unless the test expects the lookup itself
+	if mockStore, ok := store.(*mockdb.MockStore); ok {
+		mockStore.EXPECT().
+			GetTagsByPostIds(gomock.Any(), gomock.Any()).
+			AnyTimes().
+			Return([]db.GetTagsByPostIdsRow{}, nil)
+		mockStore.EXPECT().
+			GetCommentCountsByPostIds(gomock.Any(), gomock.Any()).
+			AnyTimes().
+			Return([]db.GetCommentCountsByPostIdsRow{}, nil)
+		mockStore.EXPECT().
+			GetReactionCountsByPostIds(gomock.Any(), gomock.Any()).
+			AnyTimes().
+			Return([]db.PostReactionCounter{}, nil)
+		mockStore.EXPECT().
+			GetViewCountsByPostIds(gomock.Any(), gomock.Any()).
+			AnyTimes().
+			Return([]db.PostViewCount{}, nil)
+		mockStore.EXPECT().
+			GetAttachmentsByPostIds(gomock.Any(), gomock.Any()).
+			AnyTimes().
+			Return([]db.Attachment{}, nil)
+		mockStore.EXPECT().
+			GetPostCoversByPostIds(gomock.Any(), gomock.Any()).
+			AnyTimes().
+			Return([]db.PostCover{}, nil)
+	}
+
+	server := NewServer(config, store, &testViewRecorder{}, newTestBlobStore())
+	server.clock = fixedClock{now: testNow}
+	return server
+}
+
 func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
 	os.Exit(m.Run())
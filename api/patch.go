@@ -0,0 +1,172 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"io"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+)
+
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+
+	// maxPatchSize is the largest patch body read, enough to replace the content of a long post
+	maxPatchSize = 4 << 20
+)
+
+var (
+	errUnsupportedPatch  = fmt.Errorf("patch must be sent as %s or %s", mergePatchContentType, jsonPatchContentType)
+	errInvalidMergePatch = errors.New("merge patch is not valid JSON")
+	errPatchTooLarge     = errors.New("the patch exceeds the size limit")
+	errPatchTags         = errors.New("tags cannot be patched, replace the post with PUT to change them")
+)
+
+// @Summary Patch post by id
+// @Tags Post
+// @Description Change some fields of a specific post with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902)
+// @Description applied to {"title", "content", "contentFormat", "publishAt", "categoryId"}. The patched post has to pass the same validation as PUT,
+// @Description the tags are kept as they are and a patch adding them is rejected. The patch may be up to 4 MiB
+// @ID patch-post-by-id
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Param id path string true "the specific post id"
+// @Param input body object true "merge patch object or JSON Patch operations array"
+// @Param If-Match header string false "the ETag the post must still have"
+// @Success 200 {object} PostResponse
+// @Header 200 {string} ETag "the new post version"
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 409 {object} ErrResponse
+// @Failure 412 {object} ErrResponse
+// @Failure 413 {object} ErrResponse
+// @Failure 415 {object} ErrResponse
+// @Failure 422 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/{id} [patch]
+func (s *Server) patchPost(context *gin.Context) {
+	var request getPostRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	contentType := context.ContentType()
+	if contentType != mergePatchContentType && contentType != jsonPatchContentType {
+		context.JSON(http.StatusUnsupportedMediaType, errorResponse(errUnsupportedPatch))
+		return
+	}
+
+	patch, err := io.ReadAll(http.MaxBytesReader(context.Writer, context.Request.Body, maxPatchSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			context.JSON(http.StatusRequestEntityTooLarge, errorResponse(errPatchTooLarge))
+			return
+		}
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if err = checkPatch(contentType, patch); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	post, err := s.store.UpdatePostTx(context, int32(request.ID), func(post db.Post) (db.UpdatePostTxParams, error) {
+		requestBody, err := applyPatch(post, contentType, patch)
+		if err != nil {
+			return db.UpdatePostTxParams{}, err
+		}
+		return s.toUpdatePostParams(context, post, requestBody)
+	})
+	if err != nil {
+		respondTxError(context, err)
+		return
+	}
+
+	context.Header(etagHeader, postETag(post))
+	s.respondWithPost(context, post)
+}
+
+// checkPatch rejects malformed patches before the post gets locked.
+func checkPatch(contentType string, patch []byte) error {
+	if contentType == mergePatchContentType {
+		if !json.Valid(patch) {
+			return errInvalidMergePatch
+		}
+		return nil
+	}
+	_, err := jsonpatch.DecodePatch(patch)
+	return err
+}
+
+// applyPatch patches the editable state of the post and validates the result.
+// The returned errors carry the response status.
+func applyPatch(post db.Post, contentType string, patch []byte) (updatePostRequestBody, error) {
+	document, err := json.Marshal(toUpdatePostRequestBody(post))
+	if err != nil {
+		return updatePostRequestBody{}, err
+	}
+
+	var patched []byte
+	if contentType == mergePatchContentType {
+		patched, err = jsonpatch.MergePatch(document, patch)
+		if err != nil {
+			return updatePostRequestBody{}, statusError{status: http.StatusBadRequest, err: err}
+		}
+	} else {
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return updatePostRequestBody{}, statusError{status: http.StatusBadRequest, err: err}
+		}
+		// a well-formed patch that does not fit the current post, e.g. a failed test operation
+		patched, err = operations.Apply(document)
+		if err != nil {
+			return updatePostRequestBody{}, statusError{status: http.StatusConflict, err: err}
+		}
+	}
+
+	requestBody, err := decodePatchedPost(patched)
+	if err != nil {
+		return updatePostRequestBody{}, statusError{status: http.StatusUnprocessableEntity, err: err}
+	}
+	return requestBody, nil
+}
+
+func toUpdatePostRequestBody(post db.Post) updatePostRequestBody {
+	requestBody := updatePostRequestBody{Title: post.Title, Content: post.Content, ContentFormat: string(post.ContentFormat)}
+	if post.PublishAt.Valid {
+		requestBody.PublishAt = &post.PublishAt.Time
+	}
+	if post.CategoryID.Valid {
+		categoryID := int(post.CategoryID.Int32)
+		requestBody.CategoryID = &categoryID
+	}
+	return requestBody
+}
+
+// decodePatchedPost checks the patched document against the same rules PUT applies to its body.
+// The tags are not part of the document, a patch adding them is rejected rather than replacing the tags.
+func decodePatchedPost(patched []byte) (updatePostRequestBody, error) {
+	var requestBody updatePostRequestBody
+
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&requestBody); err != nil {
+		return updatePostRequestBody{}, err
+	}
+	if requestBody.Tags != nil {
+		return updatePostRequestBody{}, errPatchTags
+	}
+	if err := binding.Validator.ValidateStruct(&requestBody); err != nil {
+		return updatePostRequestBody{}, err
+	}
+	return requestBody, nil
+}
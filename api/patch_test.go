@@ -0,0 +1,290 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPatchPost(t *testing.T) {
+	randomPost := generateRandomPost()
+	randomPost.PublishAt = sql.NullTime{Time: testNow.Add(time.Hour), Valid: true}
+
+	testCases := []struct {
+		name          string
+		contentType   string
+		body          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "positive_PatchPost_MergePatch",
+			contentType: mergePatchContentType,
+			body:        `{"title": "New title"}`,
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.UpdatePostByIdParams{
+					ID:            randomPost.ID,
+					Title:         "New title",
+					Content:       randomPost.Content,
+					ContentFormat: db.ContentFormatPlain,
+					ContentHtml:   randomPost.ContentHtml,
+					PublishAt:     randomPost.PublishAt,
+				}
+				patchedPost := randomPost
+				patchedPost.Title = arg.Title
+
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
+					Times(1).
+					DoAndReturn(updatePostTx(randomPost, arg, patchedPost))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				expected := mapToPostResponse(randomPost)
+				expected.Title = "New title"
+				requireBodyMatchPost(t, recorder.Body, expected)
+			},
+		},
+		{
+			name:        "positive_PatchPost_MergePatchClearsSchedule",
+			contentType: mergePatchContentType,
+			body:        `{"publishAt": null}`,
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.UpdatePostByIdParams{
+					ID:            randomPost.ID,
+					Title:         randomPost.Title,
+					Content:       randomPost.Content,
+					ContentFormat: db.ContentFormatPlain,
+					ContentHtml:   randomPost.ContentHtml,
+				}
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
+					Times(1).
+					DoAndReturn(updatePostTx(randomPost, arg, randomPost))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:        "positive_PatchPost_JSONPatch",
+			contentType: jsonPatchContentType,
+			body:        `[{"op": "test", "path": "/title", "value": ` + fmt.Sprintf("%q", randomPost.Title) + `}, {"op": "replace", "path": "/content", "value": "New content"}]`,
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.UpdatePostByIdParams{
+					ID:            randomPost.ID,
+					Title:         randomPost.Title,
+					Content:       "New content",
+					ContentFormat: db.ContentFormatPlain,
+					ContentHtml:   "<p>New content</p>",
+					PublishAt:     randomPost.PublishAt,
+				}
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
+					Times(1).
+					DoAndReturn(updatePostTx(randomPost, arg, randomPost))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:        "negative_PatchPost_UnsupportedMediaType",
+			contentType: "application/json",
+			body:        `{"title": "New title"}`,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errUnsupportedPatch.Error()})
+			},
+		},
+		{
+			name:        "negative_PatchPost_InvalidMergePatch",
+			contentType: mergePatchContentType,
+			body:        `{"title": `,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "negative_PatchPost_RemovesRequiredField",
+			contentType: mergePatchContentType,
+			body:        `{"title": null}`,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
+					Times(1).
+					DoAndReturn(updatePostTx(randomPost, db.UpdatePostByIdParams{}, db.Post{}))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:        "negative_PatchPost_UnknownField",
+			contentType: mergePatchContentType,
+			body:        `{"status": "published"}`,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
+					Times(1).
+					DoAndReturn(updatePostTx(randomPost, db.UpdatePostByIdParams{}, db.Post{}))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:        "negative_PatchPost_MergePatchTags",
+			contentType: mergePatchContentType,
+			body:        `{"tags": ["go"]}`,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
+					Times(1).
+					DoAndReturn(updatePostTx(randomPost, db.UpdatePostByIdParams{}, db.Post{}))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errPatchTags.Error()})
+			},
+		},
+		{
+			name:        "negative_PatchPost_JSONPatchTags",
+			contentType: jsonPatchContentType,
+			body:        `[{"op": "add", "path": "/tags", "value": []}]`,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
+					Times(1).
+					DoAndReturn(updatePostTx(randomPost, db.UpdatePostByIdParams{}, db.Post{}))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errPatchTags.Error()})
+			},
+		},
+		{
+			name:        "negative_PatchPost_WrongType",
+			contentType: jsonPatchContentType,
+			body:        `[{"op": "replace", "path": "/content", "value": 42}]`,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
+					Times(1).
+					DoAndReturn(updatePostTx(randomPost, db.UpdatePostByIdParams{}, db.Post{}))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:        "negative_PatchPost_SchedulesInPast",
+			contentType: mergePatchContentType,
+			body:        `{"publishAt": "2000-01-01T00:00:00Z"}`,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
+					Times(1).
+					DoAndReturn(updatePostTx(randomPost, db.UpdatePostByIdParams{}, db.Post{}))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errPublishAtInPast.Error()})
+			},
+		},
+		{
+			name:        "negative_PatchPost_TestOperationFailed",
+			contentType: jsonPatchContentType,
+			body:        `[{"op": "test", "path": "/title", "value": "Another title"}, {"op": "remove", "path": "/publishAt"}]`,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
+					Times(1).
+					DoAndReturn(updatePostTx(randomPost, db.UpdatePostByIdParams{}, db.Post{}))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:        "negative_PatchPost_InvalidJSONPatch",
+			contentType: jsonPatchContentType,
+			body:        `{"op": "replace"}`,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "negative_PatchPost_TooLarge",
+			contentType: mergePatchContentType,
+			body:        `{"content": "` + strings.Repeat("a", maxPatchSize) + `"}`,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errPatchTooLarge.Error()})
+			},
+		},
+		{
+			name:        "negative_PatchPost_PostNotFound",
+			contentType: mergePatchContentType,
+			body:        `{"title": "New title"}`,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
+					Times(1).
+					Return(db.Post{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/posts/%d", randomPost.ID)
+			request, err := http.NewRequest(http.MethodPatch, requestUrl, strings.NewReader(testCase.body))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", testCase.contentType)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
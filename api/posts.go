@@ -1,39 +1,132 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
 	"net/http"
+	"net/url"
 	db "promova-test-task/db/sqlc"
+	"promova-test-task/markup"
+	"strings"
+	"time"
 )
 
+const (
+	defaultPageSize = 20
+	defaultSort     = "id"
+)
+
+var (
+	errInvalidCreatedRange = errors.New("created_after must be before created_before")
+	errPublishAtInPast     = errors.New("publishAt must be in the future")
+	errAdminOnly           = errors.New("the operation is allowed to admins only")
+)
+
+// statusError is returned from inside a store transaction to abort it with a specific response status
+type statusError struct {
+	status int
+	err    error
+}
+
+func (e statusError) Error() string {
+	return e.err.Error()
+}
+
+func (e statusError) Unwrap() error {
+	return e.err
+}
+
 type createPostRequest struct {
-	Title   string `json:"title" binding:"required"`
-	Content string `json:"content" binding:"required"`
+	Title         string     `json:"title" binding:"required"`
+	Content       string     `json:"content" binding:"required"`
+	ContentFormat string     `json:"contentFormat" binding:"omitempty,oneof=plain markdown html"`
+	PublishAt     *time.Time `json:"publishAt"`
+	AuthorID      *int       `json:"authorId" binding:"omitempty,min=1"`
+	CategoryID    *int       `json:"categoryId" binding:"omitempty,min=1"`
+	Tags          []string   `json:"tags" binding:"omitempty,max=20,dive,max=50"`
 }
 
 type PostResponse struct {
-	ID        int    `json:"id"`
-	Title     string `json:"title"`
-	Content   string `json:"content"`
-	CreatedAt string `json:"createdAt"`
-	UpdatedAt string `json:"updatedAt"`
+	ID            int                  `json:"id"`
+	Title         string               `json:"title"`
+	Slug          string               `json:"slug"`
+	Content       string               `json:"content"`
+	ContentFormat string               `json:"contentFormat"`
+	Status        string               `json:"status"`
+	CreatedAt     string               `json:"createdAt"`
+	UpdatedAt     string               `json:"updatedAt"`
+	PublishedAt   string               `json:"publishedAt,omitempty"`
+	PublishAt     string               `json:"publishAt,omitempty"`
+	Version       int                  `json:"version"`
+	Author        *PostAuthorResponse  `json:"author,omitempty"`
+	Tags          []string             `json:"tags,omitempty"`
+	Breadcrumbs   []BreadcrumbResponse `json:"breadcrumbs,omitempty"`
+	CommentCount  int                  `json:"commentCount"`
+	Reactions     map[string]int       `json:"reactions,omitempty"`
+	ViewCount     int64                `json:"viewCount"`
+	Attachments   []AttachmentResponse `json:"attachments,omitempty"`
+	CoverImage    *CoverImageResponse  `json:"coverImage,omitempty"`
+}
+
+type listPostsRequest struct {
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor"`
+	Sort   string `form:"sort" binding:"omitempty,oneof=id -id created_at -created_at updated_at -updated_at title -title likes -likes"`
+	Status string `form:"status" binding:"omitempty,oneof=draft published archived"`
+	postFilters
+}
+
+// postFilters are the criteria posts are picked by, shared by everything listing posts
+type postFilters struct {
+	CreatedAfter  time.Time `form:"created_after"`
+	CreatedBefore time.Time `form:"created_before"`
+	UpdatedSince  time.Time `form:"updated_since"`
+	TitleContains string    `form:"title_contains" binding:"omitempty,max=200"`
+	Tags          []string  `form:"tag" binding:"omitempty,max=10,dive,max=50"`
+	TagMatch      string    `form:"tag_match" binding:"omitempty,oneof=any all"`
+}
+
+type ListPostsResponse struct {
+	Items      []PostResponse `json:"items"`
+	NextCursor string         `json:"nextCursor,omitempty"`
 }
 
 type getPostRequest struct {
 	ID int `uri:"id" binding:"required"`
 }
 
+type getPostBySlugRequest struct {
+	Slug string `uri:"slug" binding:"required,max=100"`
+}
+
+// renderPostRequest picks the representation of the content, the one it was written in by default
+type renderPostRequest struct {
+	Render string `form:"render" binding:"omitempty,oneof=html"`
+}
+
+type deletePostRequest struct {
+	Hard bool `form:"hard"`
+}
+
+// updatePostRequestBody is the full editable state of a post, replaced as a whole by PUT and patched by PATCH.
+// Tags and the content format are the exception, they are only replaced when present
 type updatePostRequestBody struct {
-	Title   string `json:"title" binding:"omitempty"`
-	Content string `json:"content" binding:"omitempty"`
+	Title         string     `json:"title" binding:"required"`
+	Content       string     `json:"content" binding:"required"`
+	ContentFormat string     `json:"contentFormat,omitempty" binding:"omitempty,oneof=plain markdown html"`
+	PublishAt     *time.Time `json:"publishAt,omitempty"`
+	CategoryID    *int       `json:"categoryId,omitempty" binding:"omitempty,min=1"`
+	Tags          []string   `json:"tags,omitempty" binding:"omitempty,max=20,dive,max=50"`
 }
 
 // @Summary Create a post
 // @Tags Post
-// @Description Create a post
+// @Description Create a post. A post with a future publishAt stays hidden until the scheduler publishes it.
+// @Description Tags that do not exist yet are created. The content is plain text unless contentFormat says it is Markdown or HTML,
+// @Description either way a sanitized HTML rendering of it is stored along with it
 // @ID create-post
 // @Accept json
 // @Produce json
@@ -49,32 +142,122 @@ func (s *Server) createPost(context *gin.Context) {
 		context.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
+	arg, err := s.toCreatePostParams(request)
+	if err != nil {
+		respondTxError(context, err)
+		return
+	}
+
 	// TODO: Validate incoming data
-	post, err := s.store.CreatePost(context, db.CreatePostParams{
-		Title:   request.Title,
-		Content: request.Content,
-	})
+	post, err := s.store.CreatePostTx(context, arg)
 
 	if err != nil {
+		var pqError *pq.Error
+		if errors.As(err, &pqError) && pqError.Code.Name() == "foreign_key_violation" {
+			context.JSON(http.StatusBadRequest, errorResponse(missingReference(pqError)))
+			return
+		}
 		context.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	postResponse := mapToPostResponse(post)
-	context.JSON(http.StatusOK, postResponse)
+	s.respondWithPost(context, post)
+}
+
+// toCreatePostParams turns a valid create request into the new post, rendering its content.
+func (s *Server) toCreatePostParams(request createPostRequest) (db.CreatePostTxParams, error) {
+	publishAt, err := s.schedule(request.PublishAt)
+	if err != nil {
+		return db.CreatePostTxParams{}, statusError{status: http.StatusBadRequest, err: err}
+	}
+
+	if len(request.ContentFormat) == 0 {
+		request.ContentFormat = markup.FormatPlain
+	}
+	contentHtml, err := markup.Render(request.ContentFormat, request.Content)
+	if err != nil {
+		return db.CreatePostTxParams{}, err
+	}
+
+	arg := db.CreatePostParams{
+		Title:         request.Title,
+		Content:       request.Content,
+		ContentFormat: db.ContentFormat(request.ContentFormat),
+		ContentHtml:   contentHtml,
+		PublishAt:     publishAt,
+	}
+	if request.AuthorID != nil {
+		arg.AuthorID = sql.NullInt32{Int32: int32(*request.AuthorID), Valid: true}
+	}
+	if request.CategoryID != nil {
+		arg.CategoryID = sql.NullInt32{Int32: int32(*request.CategoryID), Valid: true}
+	}
+	return db.CreatePostTxParams{CreatePostParams: arg, Tags: normalizeTags(request.Tags)}, nil
 }
 
 // @Summary Get posts
 // @Tags Post
-// @Description Get all posts
+// @Description Get a page of filtered and sorted posts. Pass the returned nextCursor to fetch the following page
 // @ID get-posts
 // @Produce json
-// @Success 200 {object} []PostResponse
+// @Param limit query int false "maximum number of posts in the page (1-100, defaults to 20)"
+// @Param cursor query string false "opaque cursor returned as nextCursor by the previous page"
+// @Param sort query string false "sort order, prefix with - for descending" Enums(id, -id, created_at, -created_at, updated_at, -updated_at, title, -title, likes, -likes)
+// @Param created_after query string false "only posts created after the RFC 3339 timestamp"
+// @Param created_before query string false "only posts created before the RFC 3339 timestamp"
+// @Param updated_since query string false "only posts updated at or after the RFC 3339 timestamp"
+// @Param title_contains query string false "case-insensitive substring of the title"
+// @Param status query string false "lifecycle status of the posts, defaults to published. Other statuses need the admin token" Enums(draft, published, archived)
+// @Param tag query []string false "only posts with these tags, repeat the parameter for several" collectionFormat(multi)
+// @Param tag_match query string false "whether posts need any or all of the tags, defaults to any" Enums(any, all)
+// @Param X-Admin-Token header string false "admin token"
+// @Success 200 {object} ListPostsResponse
 // @Failure 400 {object} ErrResponse
+// @Failure 403 {object} ErrResponse
 // @Failure 500 {object} ErrResponse
 // @Router /posts [get]
 func (s *Server) getPosts(context *gin.Context) {
-	posts, err := s.store.GetPosts(context)
+	s.listPosts(context, postsScope{})
+}
+
+// postsScope narrows a posts listing down to the posts of one author or category
+type postsScope struct {
+	authorID   sql.NullInt32
+	categoryID sql.NullInt32
+}
+
+// listPosts answers with a page of the posts in scope matching the query.
+func (s *Server) listPosts(context *gin.Context, scope postsScope) {
+	var request listPostsRequest
+
+	if err := context.ShouldBindQuery(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if request.Limit == 0 {
+		request.Limit = defaultPageSize
+	}
+	if len(request.Sort) == 0 {
+		request.Sort = defaultSort
+	}
+	if len(request.Status) == 0 {
+		request.Status = string(db.PostStatusPublished)
+	}
+	// drafts and archived posts are not public
+	if request.Status != string(db.PostStatusPublished) && !s.isAdmin(context) {
+		context.JSON(http.StatusForbidden, errorResponse(errAdminOnly))
+		return
+	}
+
+	arg, err := request.toListPostsParams()
+	if err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	arg.AuthorID = scope.authorID
+	arg.CategoryID = scope.categoryID
+
+	posts, err := s.queryPosts(context, arg)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			context.JSON(http.StatusNotFound, errorResponse(err))
@@ -84,28 +267,52 @@ func (s *Server) getPosts(context *gin.Context) {
 		return
 	}
 
-	postsResponse := mapToPostsResponse(posts)
-	context.JSON(http.StatusOK, postsResponse)
+	hasNextPage := len(posts) > request.Limit
+	if hasNextPage {
+		posts = posts[:request.Limit]
+	}
+	relations, err := s.loadPostRelations(context, posts)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := ListPostsResponse{Items: relations.mapToPostsResponse(posts)}
+	if hasNextPage {
+		last := posts[len(posts)-1]
+		response.NextCursor = encodeCursor(newPostsCursor(request.Sort, last, relations.reactions[last.ID][db.ReactionTypeLike]))
+	}
+	context.JSON(http.StatusOK, response)
 }
 
 // @Summary Get post by id
 // @Tags Post
-// @Description Get a specific post by the specified id
+// @Description Get a specific post by the specified id. Drafts and archived posts need the admin token.
+// @Description Reading a published post counts as a view of it
 // @ID get-post-by-id
 // @Accept json
 // @Produce json
 // @Param id path string true "the specific post id"
+// @Param render query string false "html returns the sanitized HTML rendering of the content" Enums(html)
+// @Param X-Admin-Token header string false "admin token"
 // @Success 200 {object} PostResponse
+// @Header 200 {string} ETag "the post version, to be sent back in If-Match"
 // @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
 // @Failure 500 {object} ErrResponse
 // @Router /posts/{id} [get]
 func (s *Server) getPost(context *gin.Context) {
 	var request getPostRequest
+	var query renderPostRequest
 
 	if err := context.ShouldBindUri(&request); err != nil {
 		context.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
+	if err := context.ShouldBindQuery(&query); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
 	post, err := s.store.GetPostById(context, int32(request.ID))
 
 	if err != nil {
@@ -117,20 +324,103 @@ func (s *Server) getPost(context *gin.Context) {
 		return
 	}
 
-	postResponse := mapToPostResponse(post)
-	context.JSON(http.StatusOK, postResponse)
+	if s.isHidden(context, post) {
+		context.JSON(http.StatusNotFound, errorResponse(sql.ErrNoRows))
+		return
+	}
+
+	s.recordView(post)
+	context.Header(etagHeader, postETag(post))
+	s.respondWithPost(context, query.rendered(post))
+}
+
+// @Summary Get post by slug
+// @Tags Post
+// @Description Get a specific post by its slug. A slug the post had before its title changed redirects to the current one.
+// @Description Drafts and archived posts need the admin token. Reading a published post counts as a view of it
+// @ID get-post-by-slug
+// @Produce json
+// @Param slug path string true "the current or a former slug of the post"
+// @Param render query string false "html returns the sanitized HTML rendering of the content" Enums(html)
+// @Param X-Admin-Token header string false "admin token"
+// @Success 200 {object} PostResponse
+// @Header 200 {string} ETag "the post version, to be sent back in If-Match"
+// @Success 301
+// @Header 301 {string} Location "the path of the post under its current slug"
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/by-slug/{slug} [get]
+func (s *Server) getPostBySlug(context *gin.Context) {
+	var request getPostBySlugRequest
+	var query renderPostRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if err := context.ShouldBindQuery(&query); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	post, err := s.store.GetPostBySlug(context, request.Slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		post, err = s.store.GetPostBySlugAlias(context, request.Slug)
+		if err == nil && !s.isHidden(context, post) {
+			context.Redirect(http.StatusMovedPermanently, postSlugPath(post.Slug))
+			return
+		}
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			context.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if s.isHidden(context, post) {
+		context.JSON(http.StatusNotFound, errorResponse(sql.ErrNoRows))
+		return
+	}
+
+	s.recordView(post)
+	context.Header(etagHeader, postETag(post))
+	s.respondWithPost(context, query.rendered(post))
+}
+
+// rendered swaps the content of the post for its HTML rendering when it was asked for.
+func (r renderPostRequest) rendered(post db.Post) db.Post {
+	if r.Render == markup.FormatHTML {
+		post.Content = post.ContentHtml
+		post.ContentFormat = db.ContentFormatHtml
+	}
+	return post
+}
+
+// postSlugPath is the canonical location of the post with the slug.
+func postSlugPath(slug string) string {
+	return "/posts/by-slug/" + url.PathEscape(slug)
 }
 
 // @Summary Update post by id
 // @Tags Post
-// @Description Update a specific post by the specified id
+// @Description Replace the title, content, format, schedule, category and tags of a specific post. Omitted fields are reset
+// @Description as for a new post: publishAt clears the schedule, categoryId the category, tags the tags, and contentFormat means plain.
+// @Description A new title gives the post a new slug, the old one keeps redirecting to it
 // @ID update-post-by-id
 // @Accept json
 // @Produce json
 // @Param id path string true "the specific post id"
 // @Param input body updatePostRequestBody true "post entity related data"
-// @Success 200
+// @Param If-Match header string false "the ETag the post must still have"
+// @Success 200 {object} PostResponse
+// @Header 200 {string} ETag "the new post version"
 // @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 412 {object} ErrResponse
 // @Failure 500 {object} ErrResponse
 // @Router /posts/{id} [put]
 func (s *Server) updatePost(context *gin.Context) {
@@ -147,82 +437,502 @@ func (s *Server) updatePost(context *gin.Context) {
 		return
 	}
 
-	post, err := s.store.GetPostById(context, int32(request.ID))
+	requestBody = requestBody.replacement()
+	post, err := s.store.UpdatePostTx(context, int32(request.ID), func(post db.Post) (db.UpdatePostTxParams, error) {
+		return s.toUpdatePostParams(context, post, requestBody)
+	})
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			context.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondTxError(context, err)
 		return
 	}
 
-	arg := db.UpdatePostByIdParams{
-		ID: post.ID,
+	context.Header(etagHeader, postETag(post))
+	s.respondWithPost(context, post)
+}
+
+// replacement is the body of a PUT as the whole new state of a post, with the omitted fields reset the way
+// createPost defaults them. PATCH keeps the tags and, through its document, the content format instead.
+func (b updatePostRequestBody) replacement() updatePostRequestBody {
+	if len(b.ContentFormat) == 0 {
+		b.ContentFormat = markup.FormatPlain
+	}
+	if b.Tags == nil {
+		b.Tags = []string{}
 	}
+	return b
+}
 
-	if len(requestBody.Title) > 0 {
-		arg.Title = requestBody.Title
+// toUpdatePostParams turns the new state of a post into the update of the current one, honoring If-Match.
+func (s *Server) toUpdatePostParams(context *gin.Context, post db.Post, requestBody updatePostRequestBody) (db.UpdatePostTxParams, error) {
+	version, ok := expectedVersion(context, post)
+	if !ok {
+		return db.UpdatePostTxParams{}, statusError{status: http.StatusPreconditionFailed, err: errPreconditionFailed}
 	}
-	if len(requestBody.Content) > 0 {
-		arg.Content = requestBody.Content
+	return s.updatePostParams(post, requestBody, version)
+}
+
+// updatePostParams turns the new state of a post into the update of the current one expecting the version, if any.
+func (s *Server) updatePostParams(post db.Post, requestBody updatePostRequestBody, version sql.NullInt32) (db.UpdatePostTxParams, error) {
+	// an unchanged schedule is kept even if it is already due and waits for the publisher
+	publishAt := post.PublishAt
+	if !sameSchedule(post.PublishAt, requestBody.PublishAt) {
+		var err error
+		publishAt, err = s.schedule(requestBody.PublishAt)
+		if err != nil {
+			return db.UpdatePostTxParams{}, statusError{status: http.StatusBadRequest, err: err}
+		}
 	}
 
-	post, err = s.store.UpdatePostById(context, arg)
+	contentFormat := post.ContentFormat
+	if len(requestBody.ContentFormat) > 0 {
+		contentFormat = db.ContentFormat(requestBody.ContentFormat)
+	}
+	contentHtml, err := markup.Render(string(contentFormat), requestBody.Content)
 	if err != nil {
-		var pqError *pq.Error
-		if errors.As(err, &pqError) {
-			switch pqError.Code.Name() {
-			case "modifying_sql_data_not_permitted":
-				context.JSON(http.StatusBadRequest, errorResponse(err))
-				return
-			}
-		}
-		context.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return db.UpdatePostTxParams{}, err
 	}
-	postResponse := mapToPostResponse(post)
-	context.JSON(http.StatusOK, postResponse)
+
+	arg := db.UpdatePostTxParams{
+		UpdatePostByIdParams: db.UpdatePostByIdParams{
+			ID:              post.ID,
+			Title:           requestBody.Title,
+			Content:         requestBody.Content,
+			ContentFormat:   contentFormat,
+			ContentHtml:     contentHtml,
+			PublishAt:       publishAt,
+			ExpectedVersion: version,
+		},
+		Tags: normalizeTags(requestBody.Tags),
+	}
+	if requestBody.CategoryID != nil {
+		arg.CategoryID = sql.NullInt32{Int32: int32(*requestBody.CategoryID), Valid: true}
+	}
+	return arg, nil
 }
 
 // @Summary Delete post by id
 // @Tags Post
-// @Description Delete a specific post by the specified id
+// @Description Move a specific post to the trash. Admins can purge it permanently with hard=true
 // @ID delete-post-by-id
 // @Accept json
 // @Produce json
 // @Param id path string true "the specific post id"
+// @Param hard query bool false "permanently delete the post, requires the X-Admin-Token header"
+// @Param X-Admin-Token header string false "admin token"
+// @Param If-Match header string false "the ETag the post must still have"
 // @Success 200
 // @Failure 400 {object} ErrResponse
+// @Failure 403 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 412 {object} ErrResponse
 // @Failure 500 {object} ErrResponse
 // @Router /posts/{id} [delete]
 func (s *Server) deletePost(context *gin.Context) {
 	var request getPostRequest
+	var query deletePostRequest
 
 	if err := context.ShouldBindUri(&request); err != nil {
 		context.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	post, err := s.store.GetPostById(context, int32(request.ID))
+	if err := context.ShouldBindQuery(&query); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
 
+	if query.Hard {
+		s.purgePost(context, int32(request.ID))
+		return
+	}
+
+	err := s.store.DeletePostTx(context, int32(request.ID), func(post db.Post) error {
+		if _, ok := expectedVersion(context, post); !ok {
+			return statusError{status: http.StatusPreconditionFailed, err: errPreconditionFailed}
+		}
+		return nil
+	})
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			context.JSON(http.StatusNotFound, errorResponse(err))
-			return
+		respondTxError(context, err)
+		return
+	}
+	context.Status(http.StatusOK)
+}
+
+// respondTxError answers with the status matching an error returned from a store transaction.
+func respondTxError(context *gin.Context, err error) {
+	status, err := txErrorStatus(err)
+	context.JSON(status, errorResponse(err))
+}
+
+// txErrorStatus maps an error returned from a store transaction to the response status and the error to report.
+func txErrorStatus(err error) (int, error) {
+	var statusErr statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.status, statusErr.err
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return http.StatusNotFound, err
+	}
+	if errors.Is(err, db.ErrVersionMismatch) {
+		return http.StatusPreconditionFailed, errPreconditionFailed
+	}
+	var pqError *pq.Error
+	if errors.As(err, &pqError) {
+		switch pqError.Code.Name() {
+		case "modifying_sql_data_not_permitted":
+			return http.StatusBadRequest, err
+		case "foreign_key_violation":
+			return http.StatusBadRequest, missingReference(pqError)
 		}
+	}
+	return http.StatusInternalServerError, err
+}
+
+// missingReference names what a post refers to but does not exist, going by the violated foreign key.
+func missingReference(pqError *pq.Error) error {
+	switch pqError.Constraint {
+	case "posts_author_id_fkey":
+		return errAuthorNotFound
+	case "posts_category_id_fkey":
+		return errCategoryNotFound
+	}
+	return pqError
+}
+
+func (s *Server) purgePost(context *gin.Context, id int32) {
+	if !s.isAdmin(context) {
+		context.JSON(http.StatusForbidden, errorResponse(errAdminOnly))
+		return
+	}
+
+	// the attachment and cover rows go with the post, their blobs have to be removed separately
+	attachments, err := s.store.GetAttachmentsByPostIds(context, []int32{id})
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	covers, err := s.store.GetPostCoversByPostIds(context, []int32{id})
+	if err != nil {
 		context.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	err = s.store.DeletePost(context, post.ID)
+	purged, err := s.store.PurgePost(context, id)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
+	if purged == 0 {
+		context.JSON(http.StatusNotFound, errorResponse(sql.ErrNoRows))
+		return
+	}
+
+	keys := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		keys = append(keys, attachment.BlobKey)
+	}
+	for _, cover := range covers {
+		keys = append(keys, coverBlobKeys(cover)...)
+	}
+	s.deleteBlobs(context, keys...)
 	context.Status(http.StatusOK)
 }
 
+// schedule validates the requested publish time, which has to be in the future.
+func (s *Server) schedule(publishAt *time.Time) (sql.NullTime, error) {
+	if publishAt == nil {
+		return sql.NullTime{}, nil
+	}
+	if !publishAt.After(s.clock.Now()) {
+		return sql.NullTime{}, errPublishAtInPast
+	}
+	return sql.NullTime{Time: *publishAt, Valid: true}, nil
+}
+
+func sameSchedule(current sql.NullTime, requested *time.Time) bool {
+	if requested == nil {
+		return !current.Valid
+	}
+	return current.Valid && current.Time.Equal(*requested)
+}
+
+// publicPost fetches a post readers can see, answering with 404 when there is none.
+func (s *Server) publicPost(context *gin.Context, id int32) (db.Post, bool) {
+	post, err := s.store.GetPostById(context, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			context.JSON(http.StatusNotFound, errorResponse(err))
+			return db.Post{}, false
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return db.Post{}, false
+	}
+
+	if post.Status != db.PostStatusPublished || isScheduled(post, s.clock.Now()) {
+		context.JSON(http.StatusNotFound, errorResponse(sql.ErrNoRows))
+		return db.Post{}, false
+	}
+	return post, true
+}
+
+// isHidden reports whether the reader may not see the post: drafts and archived posts are for admins only,
+// and scheduled posts stay hidden until their publish time.
+func (s *Server) isHidden(context *gin.Context, post db.Post) bool {
+	if isScheduled(post, s.clock.Now()) {
+		return true
+	}
+	return post.Status != db.PostStatusPublished && !s.isAdmin(context)
+}
+
+// isScheduled reports whether the post waits for its publish time and has to stay hidden until then.
+func isScheduled(post db.Post, now time.Time) bool {
+	return post.PublishAt.Valid && post.PublishAt.Time.After(now)
+}
+
+// listPostsParams are the filters of a posts listing with its sort order and the value of the sort column it seeks from.
+// The filters are those of ListPostsByID, the other listing queries take the same ones.
+type listPostsParams struct {
+	db.ListPostsByIDParams
+	Sort        string
+	CursorTime  sql.NullTime
+	CursorTitle sql.NullString
+	CursorLikes sql.NullInt32
+}
+
+func (r listPostsRequest) toListPostsParams() (listPostsParams, error) {
+	arg := listPostsParams{Sort: r.Sort}
+	arg.Status = db.PostStatus(r.Status)
+	// one extra row tells whether there is a next page
+	arg.PageSize = int32(r.Limit + 1)
+
+	if !r.CreatedAfter.IsZero() && !r.CreatedBefore.IsZero() && !r.CreatedAfter.Before(r.CreatedBefore) {
+		return arg, errInvalidCreatedRange
+	}
+	if !r.CreatedAfter.IsZero() {
+		arg.CreatedAfter = sql.NullTime{Time: r.CreatedAfter, Valid: true}
+	}
+	if !r.CreatedBefore.IsZero() {
+		arg.CreatedBefore = sql.NullTime{Time: r.CreatedBefore, Valid: true}
+	}
+	if !r.UpdatedSince.IsZero() {
+		arg.UpdatedSince = sql.NullTime{Time: r.UpdatedSince, Valid: true}
+	}
+	if len(r.TitleContains) > 0 {
+		arg.TitleContains = sql.NullString{String: escapeLikePattern(r.TitleContains), Valid: true}
+	}
+	if len(r.Tags) > 0 {
+		arg.Tags = normalizeTags(r.Tags)
+		arg.TagMatch = r.TagMatch
+		if len(arg.TagMatch) == 0 {
+			arg.TagMatch = defaultTagMatch
+		}
+	}
+
+	if len(r.Cursor) > 0 {
+		cursor, err := decodeCursor(r.Cursor, r.Sort)
+		if err != nil {
+			return arg, err
+		}
+		cursor.apply(&arg)
+	}
+	return arg, nil
+}
+
+// queryPosts runs the listing query of the sort order, each of which seeks by (column, id) on the index of its column.
+func (s *Server) queryPosts(ctx context.Context, arg listPostsParams) ([]db.Post, error) {
+	f := arg.ListPostsByIDParams
+	switch arg.Sort {
+	case "-id":
+		return s.store.ListPostsByIDDesc(ctx, db.ListPostsByIDDescParams(f))
+	case "created_at", "-created_at", "updated_at", "-updated_at":
+		byTime := db.ListPostsByCreatedAtParams{
+			CreatedAfter:  f.CreatedAfter,
+			CreatedBefore: f.CreatedBefore,
+			UpdatedSince:  f.UpdatedSince,
+			TitleContains: f.TitleContains,
+			Status:        f.Status,
+			AuthorID:      f.AuthorID,
+			Tags:          f.Tags,
+			TagMatch:      f.TagMatch,
+			CategoryID:    f.CategoryID,
+			CursorID:      f.CursorID,
+			CursorTime:    arg.CursorTime,
+			PageSize:      f.PageSize,
+		}
+		switch arg.Sort {
+		case "created_at":
+			return s.store.ListPostsByCreatedAt(ctx, byTime)
+		case "-created_at":
+			return s.store.ListPostsByCreatedAtDesc(ctx, db.ListPostsByCreatedAtDescParams(byTime))
+		case "updated_at":
+			return s.store.ListPostsByUpdatedAt(ctx, db.ListPostsByUpdatedAtParams(byTime))
+		}
+		return s.store.ListPostsByUpdatedAtDesc(ctx, db.ListPostsByUpdatedAtDescParams(byTime))
+	case "title", "-title":
+		byTitle := db.ListPostsByTitleParams{
+			CreatedAfter:  f.CreatedAfter,
+			CreatedBefore: f.CreatedBefore,
+			UpdatedSince:  f.UpdatedSince,
+			TitleContains: f.TitleContains,
+			Status:        f.Status,
+			AuthorID:      f.AuthorID,
+			Tags:          f.Tags,
+			TagMatch:      f.TagMatch,
+			CategoryID:    f.CategoryID,
+			CursorID:      f.CursorID,
+			CursorTitle:   arg.CursorTitle,
+			PageSize:      f.PageSize,
+		}
+		if arg.Sort == "title" {
+			return s.store.ListPostsByTitle(ctx, byTitle)
+		}
+		return s.store.ListPostsByTitleDesc(ctx, db.ListPostsByTitleDescParams(byTitle))
+	case "likes", "-likes":
+		byLikes := db.ListPostsByLikesParams{
+			CreatedAfter:  f.CreatedAfter,
+			CreatedBefore: f.CreatedBefore,
+			UpdatedSince:  f.UpdatedSince,
+			TitleContains: f.TitleContains,
+			Status:        f.Status,
+			AuthorID:      f.AuthorID,
+			Tags:          f.Tags,
+			TagMatch:      f.TagMatch,
+			CategoryID:    f.CategoryID,
+			CursorID:      f.CursorID,
+			CursorLikes:   arg.CursorLikes,
+			PageSize:      f.PageSize,
+		}
+		if arg.Sort == "likes" {
+			return s.store.ListPostsByLikes(ctx, byLikes)
+		}
+		return s.store.ListPostsByLikesDesc(ctx, db.ListPostsByLikesDescParams(byLikes))
+	}
+	return s.store.ListPostsByID(ctx, f)
+}
+
+// escapeLikePattern makes user input match literally inside an ILIKE pattern.
+func escapeLikePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// postRelations holds what gets embedded into post responses, loaded for all posts of a response at once
+type postRelations struct {
+	authors       map[int32]db.Author
+	tags          map[int32][]string
+	breadcrumbs   map[int32][]BreadcrumbResponse
+	commentCounts map[int32]int64
+	reactions     map[int32]map[db.ReactionType]int32
+	viewCounts    map[int32]int64
+	attachments   map[int32][]AttachmentResponse
+	covers        map[int32]CoverImageResponse
+}
+
+func (s *Server) loadPostRelations(context *gin.Context, posts []db.Post) (postRelations, error) {
+	authorIDs := make([]sql.NullInt32, 0, len(posts))
+	categoryIDs := make([]sql.NullInt32, 0, len(posts))
+	postIDs := make([]int32, 0, len(posts))
+	for _, post := range posts {
+		authorIDs = append(authorIDs, post.AuthorID)
+		categoryIDs = append(categoryIDs, post.CategoryID)
+		postIDs = append(postIDs, post.ID)
+	}
+
+	authors, err := s.loadAuthors(context, authorIDs...)
+	if err != nil {
+		return postRelations{}, err
+	}
+	tags, err := s.loadTags(context, postIDs)
+	if err != nil {
+		return postRelations{}, err
+	}
+	breadcrumbs, err := s.loadBreadcrumbs(context, categoryIDs...)
+	if err != nil {
+		return postRelations{}, err
+	}
+	commentCounts, err := s.loadCommentCounts(context, postIDs)
+	if err != nil {
+		return postRelations{}, err
+	}
+	reactions, err := s.loadReactionCounts(context, postIDs)
+	if err != nil {
+		return postRelations{}, err
+	}
+	viewCounts, err := s.loadViewCounts(context, postIDs)
+	if err != nil {
+		return postRelations{}, err
+	}
+	attachments, err := s.loadAttachments(context, postIDs)
+	if err != nil {
+		return postRelations{}, err
+	}
+	covers, err := s.loadCovers(context, postIDs)
+	if err != nil {
+		return postRelations{}, err
+	}
+	return postRelations{
+		authors:       authors,
+		tags:          tags,
+		breadcrumbs:   breadcrumbs,
+		commentCounts: commentCounts,
+		reactions:     reactions,
+		viewCounts:    viewCounts,
+		attachments:   attachments,
+		covers:        covers,
+	}, nil
+}
+
+// respondWithPost answers with the post and its relations embedded.
+func (s *Server) respondWithPost(context *gin.Context, post db.Post) {
+	relations, err := s.loadPostRelations(context, []db.Post{post})
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	context.JSON(http.StatusOK, relations.mapToPostResponse(post))
+}
+
+// postsResponse maps the posts with their relations embedded.
+func (s *Server) postsResponse(context *gin.Context, posts []db.Post) ([]PostResponse, error) {
+	relations, err := s.loadPostRelations(context, posts)
+	if err != nil {
+		return nil, err
+	}
+	return relations.mapToPostsResponse(posts), nil
+}
+
+func (r postRelations) mapToPostsResponse(posts []db.Post) []PostResponse {
+	responsePosts := make([]PostResponse, 0, len(posts))
+	for _, post := range posts {
+		responsePosts = append(responsePosts, r.mapToPostResponse(post))
+	}
+	return responsePosts
+}
+
+func (r postRelations) mapToPostResponse(post db.Post) PostResponse {
+	postResponse := mapToPostResponse(post)
+	if author, ok := r.authors[post.AuthorID.Int32]; ok && post.AuthorID.Valid {
+		postResponse.Author = &PostAuthorResponse{ID: int(author.ID), Name: author.Name}
+	}
+	postResponse.Tags = r.tags[post.ID]
+	if post.CategoryID.Valid {
+		postResponse.Breadcrumbs = r.breadcrumbs[post.CategoryID.Int32]
+	}
+	postResponse.CommentCount = int(r.commentCounts[post.ID])
+	if counts, ok := r.reactions[post.ID]; ok {
+		postResponse.Reactions = make(map[string]int, len(counts))
+		for reactionType, count := range counts {
+			postResponse.Reactions[string(reactionType)] = int(count)
+		}
+	}
+	postResponse.ViewCount = r.viewCounts[post.ID]
+	postResponse.Attachments = r.attachments[post.ID]
+	if cover, ok := r.covers[post.ID]; ok {
+		postResponse.CoverImage = &cover
+	}
+	return postResponse
+}
+
 func mapToPostsResponse(posts []db.Post) []PostResponse {
 	responsePosts := make([]PostResponse, 0, len(posts))
 
@@ -236,11 +946,21 @@ func mapToPostsResponse(posts []db.Post) []PostResponse {
 func mapToPostResponse(post db.Post) PostResponse {
 
 	postResponse := PostResponse{
-		ID:        int(post.ID),
-		Title:     post.Title,
-		Content:   post.Content,
-		CreatedAt: post.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt: post.UpdatedAt.Format("2006-01-02 15:04:05"),
+		ID:            int(post.ID),
+		Title:         post.Title,
+		Slug:          post.Slug,
+		Content:       post.Content,
+		ContentFormat: string(post.ContentFormat),
+		Status:        string(post.Status),
+		Version:       int(post.Version),
+		CreatedAt:     post.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:     post.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if post.PublishedAt.Valid {
+		postResponse.PublishedAt = post.PublishedAt.Time.Format("2006-01-02 15:04:05")
+	}
+	if post.PublishAt.Valid {
+		postResponse.PublishAt = post.PublishAt.Time.Format("2006-01-02 15:04:05")
 	}
 	return postResponse
 }
@@ -2,30 +2,36 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
+	"html"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	mockdb "promova-test-task/db/mock"
 	db "promova-test-task/db/sqlc"
 	"promova-test-task/util"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
 
 func TestCreatePosts(t *testing.T) {
 	randomPost := generateRandomPost()
+	randomAuthor := generateRandomAuthor()
 	postResponse := mapToPostResponse(randomPost)
 
 	testCases := []struct {
 		name          string
 		body          gin.H
-		buildStubs    func(store *mockdb.MockQuerier)
+		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
@@ -34,13 +40,15 @@ func TestCreatePosts(t *testing.T) {
 				"title":   randomPost.Title,
 				"content": randomPost.Content,
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
+			buildStubs: func(querier *mockdb.MockStore) {
 				arg := db.CreatePostParams{
-					Title:   randomPost.Title,
-					Content: randomPost.Content,
+					Title:         randomPost.Title,
+					Content:       randomPost.Content,
+					ContentFormat: db.ContentFormatPlain,
+					ContentHtml:   randomPost.ContentHtml,
 				}
 				querier.EXPECT().
-					CreatePost(gomock.Any(), gomock.Eq(arg)).
+					CreatePostTx(gomock.Any(), gomock.Eq(db.CreatePostTxParams{CreatePostParams: arg})).
 					Times(1).
 					Return(randomPost, nil)
 			},
@@ -49,12 +57,248 @@ func TestCreatePosts(t *testing.T) {
 				requireBodyMatchPost(t, recorder.Body, postResponse)
 			},
 		},
+		{
+			name: "positive_CreatePost_Scheduled",
+			body: gin.H{
+				"title":     randomPost.Title,
+				"content":   randomPost.Content,
+				"publishAt": testNow.Add(time.Hour),
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.CreatePostParams{
+					Title:         randomPost.Title,
+					Content:       randomPost.Content,
+					ContentFormat: db.ContentFormatPlain,
+					ContentHtml:   randomPost.ContentHtml,
+					PublishAt:     sql.NullTime{Time: testNow.Add(time.Hour), Valid: true},
+				}
+				querier.EXPECT().
+					CreatePostTx(gomock.Any(), gomock.Eq(db.CreatePostTxParams{CreatePostParams: arg})).
+					Times(1).
+					Return(randomPost, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPost(t, recorder.Body, postResponse)
+			},
+		},
+		{
+			name: "positive_CreatePost_WithAuthor",
+			body: gin.H{
+				"title":    randomPost.Title,
+				"content":  randomPost.Content,
+				"authorId": randomAuthor.ID,
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.CreatePostParams{
+					Title:         randomPost.Title,
+					Content:       randomPost.Content,
+					ContentFormat: db.ContentFormatPlain,
+					ContentHtml:   randomPost.ContentHtml,
+					AuthorID:      sql.NullInt32{Int32: randomAuthor.ID, Valid: true},
+				}
+				authoredPost := randomPost
+				authoredPost.AuthorID = arg.AuthorID
+				querier.EXPECT().
+					CreatePostTx(gomock.Any(), gomock.Eq(db.CreatePostTxParams{CreatePostParams: arg})).
+					Times(1).
+					Return(authoredPost, nil)
+				querier.EXPECT().
+					GetAuthorsByIds(gomock.Any(), gomock.Eq([]int32{randomAuthor.ID})).
+					Times(1).
+					Return([]db.Author{randomAuthor}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				expected := mapToPostResponse(randomPost)
+				expected.Author = &PostAuthorResponse{ID: int(randomAuthor.ID), Name: randomAuthor.Name}
+				requireBodyMatchPost(t, recorder.Body, expected)
+			},
+		},
+		{
+			name: "positive_CreatePost_WithTags",
+			body: gin.H{
+				"title":   randomPost.Title,
+				"content": randomPost.Content,
+				"tags":    []string{" Go", "sql", "go"},
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.CreatePostTxParams{
+					CreatePostParams: db.CreatePostParams{
+						Title:         randomPost.Title,
+						Content:       randomPost.Content,
+						ContentFormat: db.ContentFormatPlain,
+						ContentHtml:   randomPost.ContentHtml,
+					},
+					Tags: []string{"go", "sql"},
+				}
+				querier.EXPECT().
+					CreatePostTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(randomPost, nil)
+				querier.EXPECT().
+					GetTagsByPostIds(gomock.Any(), gomock.Eq([]int32{randomPost.ID})).
+					Times(1).
+					Return([]db.GetTagsByPostIdsRow{
+						{PostID: randomPost.ID, ID: 1, Name: "go"},
+						{PostID: randomPost.ID, ID: 2, Name: "sql"},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				expected := mapToPostResponse(randomPost)
+				expected.Tags = []string{"go", "sql"}
+				requireBodyMatchPost(t, recorder.Body, expected)
+			},
+		},
+		{
+			name: "positive_CreatePost_Markdown",
+			body: gin.H{
+				"title":         randomPost.Title,
+				"content":       "Some **bold** text",
+				"contentFormat": "markdown",
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.CreatePostParams{
+					Title:         randomPost.Title,
+					Content:       "Some **bold** text",
+					ContentFormat: db.ContentFormatMarkdown,
+					ContentHtml:   "<p>Some <strong>bold</strong> text</p>\n",
+				}
+				markdownPost := randomPost
+				markdownPost.Content = arg.Content
+				markdownPost.ContentFormat = arg.ContentFormat
+				markdownPost.ContentHtml = arg.ContentHtml
+				querier.EXPECT().
+					CreatePostTx(gomock.Any(), gomock.Eq(db.CreatePostTxParams{CreatePostParams: arg})).
+					Times(1).
+					Return(markdownPost, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				expected := mapToPostResponse(randomPost)
+				expected.Content = "Some **bold** text"
+				expected.ContentFormat = "markdown"
+				requireBodyMatchPost(t, recorder.Body, expected)
+			},
+		},
+		{
+			name: "positive_CreatePost_SanitizesHTML",
+			body: gin.H{
+				"title":         randomPost.Title,
+				"content":       `<p onclick="steal()">Hi<script>alert(1)</script> <a href="javascript:alert(1)">there</a></p>`,
+				"contentFormat": "html",
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.CreatePostParams{
+					Title:         randomPost.Title,
+					Content:       `<p onclick="steal()">Hi<script>alert(1)</script> <a href="javascript:alert(1)">there</a></p>`,
+					ContentFormat: db.ContentFormatHtml,
+					ContentHtml:   "<p>Hi there</p>",
+				}
+				querier.EXPECT().
+					CreatePostTx(gomock.Any(), gomock.Eq(db.CreatePostTxParams{CreatePostParams: arg})).
+					Times(1).
+					Return(randomPost, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "negative_CreatePost_UnknownContentFormat",
+			body: gin.H{
+				"title":         randomPost.Title,
+				"content":       randomPost.Content,
+				"contentFormat": "rtf",
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					CreatePostTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "negative_CreatePost_TooLongTag",
+			body: gin.H{
+				"title":   randomPost.Title,
+				"content": randomPost.Content,
+				"tags":    []string{strings.Repeat("a", 51)},
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					CreatePostTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "negative_CreatePost_AuthorNotFound",
+			body: gin.H{
+				"title":    randomPost.Title,
+				"content":  randomPost.Content,
+				"authorId": randomAuthor.ID,
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					CreatePostTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Post{}, &pq.Error{Code: "23503", Constraint: "posts_author_id_fkey"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errAuthorNotFound.Error()})
+			},
+		},
+		{
+			name: "negative_CreatePost_CategoryNotFound",
+			body: gin.H{
+				"title":      randomPost.Title,
+				"content":    randomPost.Content,
+				"categoryId": 42,
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					CreatePostTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Post{}, &pq.Error{Code: "23503", Constraint: "posts_category_id_fkey"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errCategoryNotFound.Error()})
+			},
+		},
+		{
+			name: "negative_CreatePost_PublishAtInPast",
+			body: gin.H{
+				"title":     randomPost.Title,
+				"content":   randomPost.Content,
+				"publishAt": testNow.Add(-time.Minute),
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					CreatePostTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errPublishAtInPast.Error()})
+			},
+		},
 		{
 			name: "negative_CreatePost_EmptyRequestBody",
 			body: gin.H{},
-			buildStubs: func(querier *mockdb.MockQuerier) {
+			buildStubs: func(querier *mockdb.MockStore) {
 				querier.EXPECT().
-					CreatePost(gomock.Any(), gomock.Any()).
+					CreatePostTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -68,13 +312,15 @@ func TestCreatePosts(t *testing.T) {
 				"title":   randomPost.Title,
 				"content": randomPost.Content,
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
+			buildStubs: func(querier *mockdb.MockStore) {
 				arg := db.CreatePostParams{
-					Title:   randomPost.Title,
-					Content: randomPost.Content,
+					Title:         randomPost.Title,
+					Content:       randomPost.Content,
+					ContentFormat: db.ContentFormatPlain,
+					ContentHtml:   randomPost.ContentHtml,
 				}
 				querier.EXPECT().
-					CreatePost(gomock.Any(), gomock.Eq(arg)).
+					CreatePostTx(gomock.Any(), gomock.Eq(db.CreatePostTxParams{CreatePostParams: arg})).
 					Times(1).
 					Return(db.Post{}, sql.ErrConnDone)
 			},
@@ -89,9 +335,9 @@ func TestCreatePosts(t *testing.T) {
 			controller := gomock.NewController(t)
 			defer controller.Finish()
 
-			store := mockdb.NewMockQuerier(controller)
+			store := mockdb.NewMockStore(controller)
 			testCase.buildStubs(store)
-			server := NewServer(store)
+			server := newTestServer(store)
 			recorder := httptest.NewRecorder()
 
 			// Marshal body data to JSON
@@ -111,16 +357,23 @@ func TestCreatePosts(t *testing.T) {
 
 func TestGetPostById(t *testing.T) {
 	randomPost := generateRandomPost()
+	randomPost.Status = db.PostStatusPublished
 	postResponse := mapToPostResponse(randomPost)
+	draftPost := generateRandomPost()
+	draftPost.ID = randomPost.ID
+	archivedPost := draftPost
+	archivedPost.Status = db.PostStatusArchived
 
 	testCases := []struct {
 		name          string
-		buildStubs    func(store *mockdb.MockQuerier)
+		query         string
+		adminToken    string
+		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name: "positive_GetPostById",
-			buildStubs: func(querier *mockdb.MockQuerier) {
+			buildStubs: func(querier *mockdb.MockStore) {
 				arg := randomPost.ID
 
 				querier.EXPECT().
@@ -131,11 +384,206 @@ func TestGetPostById(t *testing.T) {
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 				requireBodyMatchPost(t, recorder.Body, postResponse)
+				require.Equal(t, postETag(randomPost), recorder.Header().Get(etagHeader))
+			},
+		},
+		{
+			name:  "positive_GetPostById_RenderHTML",
+			query: "render=html",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				expected := mapToPostResponse(randomPost)
+				expected.Content = randomPost.ContentHtml
+				expected.ContentFormat = "html"
+				requireBodyMatchPost(t, recorder.Body, expected)
+			},
+		},
+		{
+			name:  "negative_GetPostById_UnknownRender",
+			query: "render=pdf",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "negative_GetPostById_Scheduled",
+			buildStubs: func(querier *mockdb.MockStore) {
+				scheduledPost := randomPost
+				scheduledPost.PublishAt = sql.NullTime{Time: testNow.Add(time.Hour), Valid: true}
+
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(scheduledPost, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: "sql: no rows in result set"})
+			},
+		},
+		{
+			name: "negative_GetPostById_Draft",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(draftPost, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: "sql: no rows in result set"})
+			},
+		},
+		{
+			name:       "negative_GetPostById_ArchivedWithWrongAdminToken",
+			adminToken: "wrong",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(archivedPost, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:       "positive_GetPostById_DraftWithAdminToken",
+			adminToken: testAdminToken,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(draftPost, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPost(t, recorder.Body, mapToPostResponse(draftPost))
+			},
+		},
+		{
+			name: "positive_GetPostById_CommentCount",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+				querier.EXPECT().
+					GetCommentCountsByPostIds(gomock.Any(), gomock.Eq([]int32{randomPost.ID})).
+					Times(1).
+					Return([]db.GetCommentCountsByPostIdsRow{{PostID: randomPost.ID, CommentCount: 3}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				expected := postResponse
+				expected.CommentCount = 3
+				requireBodyMatchPost(t, recorder.Body, expected)
+			},
+		},
+		{
+			name: "positive_GetPostById_Attachments",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+				querier.EXPECT().
+					GetAttachmentsByPostIds(gomock.Any(), gomock.Eq([]int32{randomPost.ID})).
+					Times(1).
+					Return([]db.Attachment{{
+						ID:          5,
+						PostID:      randomPost.ID,
+						BlobKey:     "posts/1/cover.png",
+						FileName:    "cover.png",
+						ContentType: "image/png",
+						Size:        2048,
+						CreatedAt:   testNow,
+					}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				expected := postResponse
+				expected.Attachments = []AttachmentResponse{{
+					ID:          5,
+					URL:         "/media/posts/1/cover.png",
+					FileName:    "cover.png",
+					ContentType: "image/png",
+					Size:        2048,
+					CreatedAt:   "2024-05-01 12:00:00",
+				}}
+				requireBodyMatchPost(t, recorder.Body, expected)
+			},
+		},
+		{
+			name: "positive_GetPostById_CoverImage",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+				querier.EXPECT().
+					GetPostCoversByPostIds(gomock.Any(), gomock.Eq([]int32{randomPost.ID})).
+					Times(1).
+					Return([]db.PostCover{{
+						PostID:       randomPost.ID,
+						BlobKey:      "covers/1/abc/original.png",
+						ContentType:  "image/png",
+						Width:        2400,
+						Height:       1200,
+						Status:       db.CoverStatusReady,
+						ThumbnailKey: sql.NullString{String: "covers/1/abc/thumbnail.png", Valid: true},
+						MediumKey:    sql.NullString{String: "covers/1/abc/medium.png", Valid: true},
+						LargeKey:     sql.NullString{String: "covers/1/abc/large.png", Valid: true},
+					}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				expected := postResponse
+				expected.CoverImage = &CoverImageResponse{
+					Status:    "ready",
+					Width:     2400,
+					Height:    1200,
+					Original:  "/media/covers/1/abc/original.png",
+					Thumbnail: "/media/covers/1/abc/thumbnail.png",
+					Medium:    "/media/covers/1/abc/medium.png",
+					Large:     "/media/covers/1/abc/large.png",
+				}
+				requireBodyMatchPost(t, recorder.Body, expected)
+			},
+		},
+		{
+			name: "positive_GetPostById_ScheduleIsDue",
+			buildStubs: func(querier *mockdb.MockStore) {
+				duePost := randomPost
+				duePost.PublishAt = sql.NullTime{Time: testNow, Valid: true}
+
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(duePost, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
 		{
 			name: "negative_GetPostById_PostNotFound",
-			buildStubs: func(querier *mockdb.MockQuerier) {
+			buildStubs: func(querier *mockdb.MockStore) {
 				arg := randomPost.ID
 
 				querier.EXPECT().
@@ -150,7 +598,7 @@ func TestGetPostById(t *testing.T) {
 		},
 		{
 			name: "negative_GetPostById_InternalError",
-			buildStubs: func(querier *mockdb.MockQuerier) {
+			buildStubs: func(querier *mockdb.MockStore) {
 				arg := randomPost.ID
 
 				querier.EXPECT().
@@ -169,15 +617,188 @@ func TestGetPostById(t *testing.T) {
 			controller := gomock.NewController(t)
 			defer controller.Finish()
 
-			store := mockdb.NewMockQuerier(controller)
+			store := mockdb.NewMockStore(controller)
 			testCase.buildStubs(store)
-			server := NewServer(store)
+			server := newTestServer(store)
 			recorder := httptest.NewRecorder()
 
 			path := "/posts"
-			requestUrl := fmt.Sprintf("%s/%d", path, randomPost.ID)
+			requestUrl := fmt.Sprintf("%s/%d?%s", path, randomPost.ID, testCase.query)
 			request, err := http.NewRequest(http.MethodGet, requestUrl, nil)
 			require.NoError(t, err)
+			if len(testCase.adminToken) > 0 {
+				request.Header.Set(adminTokenHeader, testCase.adminToken)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetPostBySlug(t *testing.T) {
+	randomPost := generateRandomPost()
+	randomPost.Status = db.PostStatusPublished
+	oldSlug := "old-" + randomPost.Slug
+	draftPost := randomPost
+	draftPost.Status = db.PostStatusDraft
+
+	testCases := []struct {
+		name          string
+		slug          string
+		adminToken    string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "positive_GetPostBySlug",
+			slug: randomPost.Slug,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostBySlug(gomock.Any(), gomock.Eq(randomPost.Slug)).
+					Times(1).
+					Return(randomPost, nil)
+				querier.EXPECT().
+					GetPostBySlugAlias(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPost(t, recorder.Body, mapToPostResponse(randomPost))
+				require.Equal(t, postETag(randomPost), recorder.Header().Get(etagHeader))
+			},
+		},
+		{
+			name: "positive_GetPostBySlug_RedirectsFromOldSlug",
+			slug: oldSlug,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostBySlug(gomock.Any(), gomock.Eq(oldSlug)).
+					Times(1).
+					Return(db.Post{}, sql.ErrNoRows)
+				querier.EXPECT().
+					GetPostBySlugAlias(gomock.Any(), gomock.Eq(oldSlug)).
+					Times(1).
+					Return(randomPost, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusMovedPermanently, recorder.Code)
+				require.Equal(t, "/posts/by-slug/"+randomPost.Slug, recorder.Header().Get("Location"))
+			},
+		},
+		{
+			name: "negative_GetPostBySlug_Scheduled",
+			slug: randomPost.Slug,
+			buildStubs: func(querier *mockdb.MockStore) {
+				scheduledPost := randomPost
+				scheduledPost.PublishAt = sql.NullTime{Time: testNow.Add(time.Hour), Valid: true}
+
+				querier.EXPECT().
+					GetPostBySlug(gomock.Any(), gomock.Eq(randomPost.Slug)).
+					Times(1).
+					Return(scheduledPost, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "negative_GetPostBySlug_Draft",
+			slug: randomPost.Slug,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostBySlug(gomock.Any(), gomock.Eq(randomPost.Slug)).
+					Times(1).
+					Return(draftPost, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: "sql: no rows in result set"})
+			},
+		},
+		{
+			name: "negative_GetPostBySlug_DraftFromOldSlug",
+			slug: oldSlug,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostBySlug(gomock.Any(), gomock.Eq(oldSlug)).
+					Times(1).
+					Return(db.Post{}, sql.ErrNoRows)
+				querier.EXPECT().
+					GetPostBySlugAlias(gomock.Any(), gomock.Eq(oldSlug)).
+					Times(1).
+					Return(draftPost, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:       "positive_GetPostBySlug_DraftWithAdminToken",
+			slug:       randomPost.Slug,
+			adminToken: testAdminToken,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostBySlug(gomock.Any(), gomock.Eq(randomPost.Slug)).
+					Times(1).
+					Return(draftPost, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPost(t, recorder.Body, mapToPostResponse(draftPost))
+			},
+		},
+		{
+			name: "negative_GetPostBySlug_PostNotFound",
+			slug: randomPost.Slug,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostBySlug(gomock.Any(), gomock.Eq(randomPost.Slug)).
+					Times(1).
+					Return(db.Post{}, sql.ErrNoRows)
+				querier.EXPECT().
+					GetPostBySlugAlias(gomock.Any(), gomock.Eq(randomPost.Slug)).
+					Times(1).
+					Return(db.Post{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: "sql: no rows in result set"})
+			},
+		},
+		{
+			name: "negative_GetPostBySlug_InternalError",
+			slug: randomPost.Slug,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostBySlug(gomock.Any(), gomock.Eq(randomPost.Slug)).
+					Times(1).
+					Return(db.Post{}, sql.ErrConnDone)
+				querier.EXPECT().
+					GetPostBySlugAlias(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/posts/by-slug/"+testCase.slug, nil)
+			require.NoError(t, err)
+			if len(testCase.adminToken) > 0 {
+				request.Header.Set(adminTokenHeader, testCase.adminToken)
+			}
 
 			server.router.ServeHTTP(recorder, request)
 			testCase.checkResponse(t, recorder)
@@ -187,31 +808,340 @@ func TestGetPostById(t *testing.T) {
 
 func TestGetPosts(t *testing.T) {
 	randomPost := generateRandomPost()
+	nextPost := generateRandomPost()
 	postsResponse := mapToPostsResponse([]db.Post{randomPost})
+	cursorTime := newPostsCursor("-created_at", randomPost, 0)
 
 	testCases := []struct {
 		name          string
-		buildStubs    func(store *mockdb.MockQuerier)
+		query         string
+		adminToken    string
+		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
-			name: "positive_GetPosts",
-			buildStubs: func(querier *mockdb.MockQuerier) {
+			name: "positive_GetPosts",
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListPostsByIDParams{Status: db.PostStatusPublished, PageSize: defaultPageSize + 1}
+
+				querier.EXPECT().
+					ListPostsByID(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Post{randomPost}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPosts(t, recorder.Body, ListPostsResponse{Items: postsResponse})
+			},
+		},
+		{
+			name:  "positive_GetPosts_HasNextPage",
+			query: "?limit=1",
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListPostsByIDParams{Status: db.PostStatusPublished, PageSize: 2}
+
+				querier.EXPECT().
+					ListPostsByID(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Post{randomPost, nextPost}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPosts(t, recorder.Body, ListPostsResponse{
+					Items:      postsResponse,
+					NextCursor: encodeCursor(newPostsCursor(defaultSort, randomPost, 0)),
+				})
+			},
+		},
+		{
+			name:  "positive_GetPosts_SortedByLikes",
+			query: "?sort=-likes&limit=1",
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListPostsByLikesDescParams{Status: db.PostStatusPublished, PageSize: 2}
+
+				querier.EXPECT().
+					ListPostsByLikesDesc(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Post{randomPost, nextPost}, nil)
+				querier.EXPECT().
+					GetReactionCountsByPostIds(gomock.Any(), gomock.Eq([]int32{randomPost.ID})).
+					Times(1).
+					Return([]db.PostReactionCounter{
+						{PostID: randomPost.ID, Type: db.ReactionTypeLike, Count: 12},
+						{PostID: randomPost.ID, Type: db.ReactionTypeWow, Count: 2},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				expected := mapToPostResponse(randomPost)
+				expected.Reactions = map[string]int{"like": 12, "wow": 2}
+				requireBodyMatchPosts(t, recorder.Body, ListPostsResponse{
+					Items:      []PostResponse{expected},
+					NextCursor: encodeCursor(newPostsCursor("-likes", randomPost, 12)),
+				})
+			},
+		},
+		{
+			name:  "positive_GetPosts_WithLikesCursor",
+			query: "?sort=-likes&cursor=" + encodeCursor(newPostsCursor("-likes", randomPost, 12)),
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListPostsByLikesDescParams{
+					CursorID:    sql.NullInt32{Int32: randomPost.ID, Valid: true},
+					CursorLikes: sql.NullInt32{Int32: 12, Valid: true},
+					Status:      db.PostStatusPublished,
+					PageSize:    defaultPageSize + 1,
+				}
+
+				querier.EXPECT().
+					ListPostsByLikesDesc(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Post{nextPost}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "positive_GetPosts_WithCursor",
+			query: "?limit=1&cursor=" + encodeCursor(postsCursor{Sort: defaultSort, ID: 7}),
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListPostsByIDParams{
+					CursorID: sql.NullInt32{Int32: 7, Valid: true},
+					Status:   db.PostStatusPublished,
+					PageSize: 2,
+				}
+
+				querier.EXPECT().
+					ListPostsByID(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Post{randomPost}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPosts(t, recorder.Body, ListPostsResponse{Items: postsResponse})
+			},
+		},
+		{
+			name:  "positive_GetPosts_WithTitleCursor",
+			query: "?sort=title&cursor=" + encodeCursor(newPostsCursor("title", randomPost, 0)),
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListPostsByTitleParams{
+					Status:      db.PostStatusPublished,
+					CursorID:    sql.NullInt32{Int32: randomPost.ID, Valid: true},
+					CursorTitle: sql.NullString{String: randomPost.Title, Valid: true},
+					PageSize:    defaultPageSize + 1,
+				}
+
+				querier.EXPECT().
+					ListPostsByTitle(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Post{nextPost}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "positive_GetPosts_SortedAndFiltered",
+			query: "?sort=-created_at&created_after=2024-01-01T00:00:00Z&title_contains=50%25_off&cursor=" + encodeCursor(cursorTime),
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListPostsByCreatedAtDescParams{
+					CreatedAfter:  sql.NullTime{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+					TitleContains: sql.NullString{String: `50\%\_off`, Valid: true},
+					CursorID:      sql.NullInt32{Int32: randomPost.ID, Valid: true},
+					CursorTime:    sql.NullTime{Time: randomPost.CreatedAt, Valid: true},
+					Status:        db.PostStatusPublished,
+					PageSize:      defaultPageSize + 1,
+				}
+
+				querier.EXPECT().
+					ListPostsByCreatedAtDesc(gomock.Any(), eqListPostsParams(arg)).
+					Times(1).
+					Return([]db.Post{randomPost}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPosts(t, recorder.Body, ListPostsResponse{Items: postsResponse})
+			},
+		},
+		{
+			name:       "positive_GetPosts_Drafts",
+			query:      "?status=draft",
+			adminToken: testAdminToken,
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListPostsByIDParams{Status: db.PostStatusDraft, PageSize: defaultPageSize + 1}
+
+				querier.EXPECT().
+					ListPostsByID(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Post{randomPost}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPosts(t, recorder.Body, ListPostsResponse{Items: postsResponse})
+			},
+		},
+		{
+			name:  "positive_GetPosts_TaggedAll",
+			query: "?tag=Go&tag=sql&tag_match=all",
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListPostsByIDParams{
+					Status:   db.PostStatusPublished,
+					Tags:     []string{"go", "sql"},
+					TagMatch: "all",
+					PageSize: defaultPageSize + 1,
+				}
+
+				querier.EXPECT().
+					ListPostsByID(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Post{randomPost}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPosts(t, recorder.Body, ListPostsResponse{Items: postsResponse})
+			},
+		},
+		{
+			name:  "positive_GetPosts_TaggedAnyByDefault",
+			query: "?tag=go",
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListPostsByIDParams{
+					Status:   db.PostStatusPublished,
+					Tags:     []string{"go"},
+					TagMatch: defaultTagMatch,
+					PageSize: defaultPageSize + 1,
+				}
+
+				querier.EXPECT().
+					ListPostsByID(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Post{randomPost}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPosts(t, recorder.Body, ListPostsResponse{Items: postsResponse})
+			},
+		},
+		{
+			name:  "negative_GetPosts_DraftsWithoutAdminToken",
+			query: "?status=draft",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListPostsByID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errAdminOnly.Error()})
+			},
+		},
+		{
+			name:       "negative_GetPosts_ArchivedWithWrongAdminToken",
+			query:      "?status=archived",
+			adminToken: "wrong",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListPostsByID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:  "negative_GetPosts_UnknownTagMatch",
+			query: "?tag=go&tag_match=some",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListPostsByID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "negative_GetPosts_UnknownStatus",
+			query: "?status=deleted",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListPostsByID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "negative_GetPosts_UnknownSort",
+			query: "?sort=content",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListPostsByID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "negative_GetPosts_CursorOfAnotherSort",
+			query: "?sort=title&cursor=" + encodeCursor(cursorTime),
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListPostsByTitle(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errInvalidCursor.Error()})
+			},
+		},
+		{
+			name:  "negative_GetPosts_InvalidCreatedRange",
+			query: "?created_after=2024-02-01T00:00:00Z&created_before=2024-01-01T00:00:00Z",
+			buildStubs: func(querier *mockdb.MockStore) {
 				querier.EXPECT().
-					GetPosts(gomock.Any()).
-					Times(1).
-					Return([]db.Post{randomPost}, nil)
+					ListPostsByID(gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchPosts(t, recorder.Body, postsResponse)
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errInvalidCreatedRange.Error()})
+			},
+		},
+		{
+			name:  "negative_GetPosts_InvalidCursor",
+			query: "?cursor=not-a-cursor",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListPostsByID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errInvalidCursor.Error()})
+			},
+		},
+		{
+			name:  "negative_GetPosts_LimitTooBig",
+			query: "?limit=101",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListPostsByID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
 			name: "negative_GetPosts_PostsNotFound",
-			buildStubs: func(querier *mockdb.MockQuerier) {
+			buildStubs: func(querier *mockdb.MockStore) {
 				querier.EXPECT().
-					GetPosts(gomock.Any()).
+					ListPostsByID(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return([]db.Post{}, sql.ErrNoRows)
 			},
@@ -222,9 +1152,9 @@ func TestGetPosts(t *testing.T) {
 		},
 		{
 			name: "negative_GetPosts_InternalError",
-			buildStubs: func(querier *mockdb.MockQuerier) {
+			buildStubs: func(querier *mockdb.MockStore) {
 				querier.EXPECT().
-					GetPosts(gomock.Any()).
+					ListPostsByID(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return([]db.Post{}, sql.ErrConnDone)
 			},
@@ -239,15 +1169,18 @@ func TestGetPosts(t *testing.T) {
 			controller := gomock.NewController(t)
 			defer controller.Finish()
 
-			store := mockdb.NewMockQuerier(controller)
+			store := mockdb.NewMockStore(controller)
 			testCase.buildStubs(store)
-			server := NewServer(store)
+			server := newTestServer(store)
 			recorder := httptest.NewRecorder()
 
 			path := "/posts"
-			requestUrl := fmt.Sprintf("%s", path)
+			requestUrl := fmt.Sprintf("%s%s", path, testCase.query)
 			request, err := http.NewRequest(http.MethodGet, requestUrl, nil)
 			require.NoError(t, err)
+			if len(testCase.adminToken) > 0 {
+				request.Header.Set(adminTokenHeader, testCase.adminToken)
+			}
 
 			server.router.ServeHTTP(recorder, request)
 			testCase.checkResponse(t, recorder)
@@ -257,230 +1190,353 @@ func TestGetPosts(t *testing.T) {
 
 func TestUpdatePostById(t *testing.T) {
 	randomPost := generateRandomPost()
-	postResponse := mapToPostResponse(randomPost)
 
 	testCases := []struct {
 		name          string
 		body          gin.H
-		buildStubs    func(store *mockdb.MockQuerier)
+		ifMatch       string
+		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name: "positive_UpdatePost",
 			body: gin.H{
-				"title":   randomPost.Title,
-				"content": randomPost.Content,
+				"title":   "New title",
+				"content": "New content",
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				arg := randomPost.ID
+			buildStubs: func(querier *mockdb.MockStore) {
+				updateArg := db.UpdatePostByIdParams{
+					ID:            randomPost.ID,
+					Title:         "New title",
+					Content:       "New content",
+					ContentFormat: db.ContentFormatPlain,
+					ContentHtml:   "<p>New content</p>",
+				}
+				updatedPost := randomPost
+				updatedPost.Title = updateArg.Title
+				updatedPost.Content = updateArg.Content
+				updatedPost.Version++
 
 				querier.EXPECT().
-					GetPostById(gomock.Any(), gomock.Eq(arg)).
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
 					Times(1).
-					Return(randomPost, nil)
+					DoAndReturn(replacePostTx(randomPost, updateArg, updatedPost))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
 
-				updateArg := db.UpdatePostByIdParams{
-					ID:      randomPost.ID,
-					Title:   randomPost.Title,
-					Content: randomPost.Content,
-				}
+				postResponse := mapToPostResponse(randomPost)
+				postResponse.Title = "New title"
+				postResponse.Content = "New content"
+				postResponse.Version = int(randomPost.Version) + 1
+				requireBodyMatchPost(t, recorder.Body, postResponse)
+				require.Equal(t, `"2"`, recorder.Header().Get(etagHeader))
+			},
+		},
+		{
+			name: "positive_UpdatePost_ResetsContentFormat",
+			body: gin.H{
+				"title":   randomPost.Title,
+				"content": "# New content",
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				markdownPost := randomPost
+				markdownPost.ContentFormat = db.ContentFormatMarkdown
 
-				updatedPost := db.Post{
-					ID:        updateArg.ID,
-					Title:     updateArg.Title,
-					Content:   updateArg.Content,
-					CreatedAt: randomPost.CreatedAt,
-					UpdatedAt: time.Now(),
+				updateArg := db.UpdatePostByIdParams{
+					ID:            randomPost.ID,
+					Title:         randomPost.Title,
+					Content:       "# New content",
+					ContentFormat: db.ContentFormatPlain,
+					ContentHtml:   "<p># New content</p>",
 				}
-
-				postResponse.Title = updatedPost.Title
-				postResponse.Content = updatedPost.Content
-				postResponse.UpdatedAt = updatedPost.UpdatedAt.Format("2006-01-02 15:04:05")
+				updatedPost := randomPost
+				updatedPost.Content = updateArg.Content
 
 				querier.EXPECT().
-					UpdatePostById(gomock.Any(), gomock.Eq(updateArg)).
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
 					Times(1).
-					Return(updatedPost, nil)
+					DoAndReturn(replacePostTx(markdownPost, updateArg, updatedPost))
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchPost(t, recorder.Body, postResponse)
 			},
 		},
 		{
-			name: "positive_UpdatePost_UpdateOnlyTitle",
+			name: "positive_UpdatePost_ReplacesTags",
 			body: gin.H{
-				"title": randomPost.Title,
+				"title":   randomPost.Title,
+				"content": randomPost.Content,
+				"tags":    []string{},
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				arg := randomPost.ID
+			buildStubs: func(querier *mockdb.MockStore) {
+				updateArg := db.UpdatePostTxParams{
+					UpdatePostByIdParams: db.UpdatePostByIdParams{
+						ID:            randomPost.ID,
+						Title:         randomPost.Title,
+						Content:       randomPost.Content,
+						ContentFormat: db.ContentFormatPlain,
+						ContentHtml:   randomPost.ContentHtml,
+					},
+					Tags: []string{},
+				}
 
 				querier.EXPECT().
-					GetPostById(gomock.Any(), gomock.Eq(arg)).
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
 					Times(1).
-					Return(randomPost, nil)
-
+					DoAndReturn(updatePostWithTagsTx(randomPost, updateArg, randomPost))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPost(t, recorder.Body, mapToPostResponse(randomPost))
+			},
+		},
+		{
+			name: "positive_UpdatePost_MovesToCategory",
+			body: gin.H{
+				"title":      randomPost.Title,
+				"content":    randomPost.Content,
+				"categoryId": 7,
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
 				updateArg := db.UpdatePostByIdParams{
-					ID:    randomPost.ID,
-					Title: randomPost.Title,
-				}
-
-				updatedPost := db.Post{
-					ID:        updateArg.ID,
-					Title:     updateArg.Title,
-					Content:   randomPost.Content,
-					CreatedAt: randomPost.CreatedAt,
-					UpdatedAt: time.Now(),
+					ID:            randomPost.ID,
+					Title:         randomPost.Title,
+					Content:       randomPost.Content,
+					ContentFormat: db.ContentFormatPlain,
+					ContentHtml:   randomPost.ContentHtml,
+					CategoryID:    sql.NullInt32{Int32: 7, Valid: true},
 				}
+				updatedPost := randomPost
+				updatedPost.CategoryID = updateArg.CategoryID
 
-				postResponse.Title = updatedPost.Title
-				postResponse.UpdatedAt = updatedPost.UpdatedAt.Format("2006-01-02 15:04:05")
-
 				querier.EXPECT().
-					UpdatePostById(gomock.Any(), gomock.Eq(updateArg)).
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
+					Times(1).
+					DoAndReturn(replacePostTx(randomPost, updateArg, updatedPost))
+				querier.EXPECT().
+					GetCategoryPaths(gomock.Any(), gomock.Eq([]int32{7})).
 					Times(1).
-					Return(updatedPost, nil)
+					Return([]db.GetCategoryPathsRow{
+						{CategoryID: 7, ID: 1, Name: "News"},
+						{CategoryID: 7, ID: 7, Name: "Releases"},
+					}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
+
+				postResponse := mapToPostResponse(randomPost)
+				postResponse.Breadcrumbs = []BreadcrumbResponse{{ID: 1, Name: "News"}, {ID: 7, Name: "Releases"}}
 				requireBodyMatchPost(t, recorder.Body, postResponse)
 			},
 		},
 		{
-			name: "positive_UpdatePost_UpdateOnlyContent",
+			name: "negative_UpdatePost_CategoryNotFound",
 			body: gin.H{
-				"content": randomPost.Content,
+				"title":      randomPost.Title,
+				"content":    randomPost.Content,
+				"categoryId": 7,
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				arg := randomPost.ID
-
+			buildStubs: func(querier *mockdb.MockStore) {
 				querier.EXPECT().
-					GetPostById(gomock.Any(), gomock.Eq(arg)).
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
 					Times(1).
-					Return(randomPost, nil)
-
+					Return(db.Post{}, &pq.Error{Code: "23503", Constraint: "posts_category_id_fkey"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errCategoryNotFound.Error()})
+			},
+		},
+		{
+			name: "positive_UpdatePost_IfMatch",
+			body: gin.H{
+				"title":   randomPost.Title,
+				"content": randomPost.Content,
+			},
+			ifMatch: postETag(randomPost),
+			buildStubs: func(querier *mockdb.MockStore) {
 				updateArg := db.UpdatePostByIdParams{
-					ID:      randomPost.ID,
-					Content: randomPost.Content,
+					ID:              randomPost.ID,
+					Title:           randomPost.Title,
+					Content:         randomPost.Content,
+					ContentFormat:   db.ContentFormatPlain,
+					ContentHtml:     randomPost.ContentHtml,
+					ExpectedVersion: sql.NullInt32{Int32: randomPost.Version, Valid: true},
 				}
 
-				updatedPost := db.Post{
-					ID:        updateArg.ID,
-					Title:     randomPost.Title,
-					Content:   updateArg.Content,
-					CreatedAt: randomPost.CreatedAt,
-					UpdatedAt: time.Now(),
-				}
-
-				postResponse.Content = updatedPost.Content
-				postResponse.UpdatedAt = updatedPost.UpdatedAt.Format("2006-01-02 15:04:05")
-
 				querier.EXPECT().
-					UpdatePostById(gomock.Any(), gomock.Eq(updateArg)).
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
 					Times(1).
-					Return(updatedPost, nil)
+					DoAndReturn(replacePostTx(randomPost, updateArg, randomPost))
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchPost(t, recorder.Body, postResponse)
 			},
 		},
 		{
-			name: "positive_UpdatePost_UpdateOnlyUpdatedAtAttribute",
-			body: gin.H{},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				arg := randomPost.ID
+			name: "positive_UpdatePost_ClearsSchedule",
+			body: gin.H{
+				"title":   randomPost.Title,
+				"content": randomPost.Content,
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				scheduledPost := randomPost
+				scheduledPost.PublishAt = sql.NullTime{Time: testNow.Add(time.Hour), Valid: true}
+
+				updateArg := db.UpdatePostByIdParams{
+					ID:            randomPost.ID,
+					Title:         randomPost.Title,
+					Content:       randomPost.Content,
+					ContentFormat: db.ContentFormatPlain,
+					ContentHtml:   randomPost.ContentHtml,
+				}
 
 				querier.EXPECT().
-					GetPostById(gomock.Any(), gomock.Eq(arg)).
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
 					Times(1).
-					Return(randomPost, nil)
+					DoAndReturn(replacePostTx(scheduledPost, updateArg, randomPost))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "positive_UpdatePost_KeepsDueSchedule",
+			body: gin.H{
+				"title":     randomPost.Title,
+				"content":   randomPost.Content,
+				"publishAt": testNow.Add(-time.Minute),
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				duePost := randomPost
+				duePost.PublishAt = sql.NullTime{Time: testNow.Add(-time.Minute), Valid: true}
 
 				updateArg := db.UpdatePostByIdParams{
-					ID: randomPost.ID,
-				}
-
-				updatedPost := db.Post{
-					ID:        updateArg.ID,
-					Title:     randomPost.Title,
-					Content:   randomPost.Content,
-					CreatedAt: randomPost.CreatedAt,
-					UpdatedAt: time.Now(),
+					ID:            randomPost.ID,
+					Title:         randomPost.Title,
+					Content:       randomPost.Content,
+					ContentFormat: db.ContentFormatPlain,
+					ContentHtml:   randomPost.ContentHtml,
+					PublishAt:     duePost.PublishAt,
 				}
 
-				postResponse.UpdatedAt = updatedPost.UpdatedAt.Format("2006-01-02 15:04:05")
-
 				querier.EXPECT().
-					UpdatePostById(gomock.Any(), gomock.Eq(updateArg)).
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
 					Times(1).
-					Return(updatedPost, nil)
+					DoAndReturn(replacePostTx(duePost, updateArg, duePost))
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchPost(t, recorder.Body, postResponse)
 			},
 		},
 		{
-			name: "negative_UpdatePost_PostNotFound",
+			name: "negative_UpdatePost_SchedulesInPast",
+			body: gin.H{
+				"title":     randomPost.Title,
+				"content":   randomPost.Content,
+				"publishAt": testNow.Add(-time.Minute),
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
+					Times(1).
+					DoAndReturn(updatePostTx(randomPost, db.UpdatePostByIdParams{}, db.Post{}))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errPublishAtInPast.Error()})
+			},
+		},
+		{
+			name: "negative_UpdatePost_IfMatchStale",
 			body: gin.H{
 				"title":   randomPost.Title,
 				"content": randomPost.Content,
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				arg := randomPost.ID
-
+			ifMatch: `"7", W/"1"`,
+			buildStubs: func(querier *mockdb.MockStore) {
 				querier.EXPECT().
-					GetPostById(gomock.Any(), gomock.Eq(arg)).
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
 					Times(1).
-					Return(db.Post{}, sql.ErrNoRows)
-
-				querier.EXPECT().
-					UpdatePostById(gomock.Any(), gomock.Any()).
-					Times(0)
+					DoAndReturn(updatePostTx(randomPost, db.UpdatePostByIdParams{}, db.Post{}))
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusNotFound, recorder.Code)
-				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: "sql: no rows in result set"})
+				require.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errPreconditionFailed.Error()})
 			},
 		},
 		{
-			name: "negative_UpdatePostById_FetchPostByIdInternalError",
+			name: "negative_UpdatePost_IfMatchChangedConcurrently",
 			body: gin.H{
 				"title":   randomPost.Title,
 				"content": randomPost.Content,
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				arg := randomPost.ID
-
+			ifMatch: "*",
+			buildStubs: func(querier *mockdb.MockStore) {
 				querier.EXPECT().
-					GetPostById(gomock.Any(), gomock.Eq(arg)).
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
 					Times(1).
-					Return(db.Post{}, sql.ErrConnDone)
-
+					Return(db.Post{}, db.ErrVersionMismatch)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+			},
+		},
+		{
+			name: "negative_UpdatePost_MissingTitle",
+			body: gin.H{
+				"content": randomPost.Content,
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
 				querier.EXPECT().
-					UpdatePostById(gomock.Any(), gomock.Any()).
+					UpdatePostTx(gomock.Any(), gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "negative_UpdatePost_MissingContent",
+			body: gin.H{
+				"title": randomPost.Title,
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
-			name: "negative_UpdatePostById_UpdatePostByIdInternalError",
+			name: "negative_UpdatePost_PostNotFound",
 			body: gin.H{
 				"title":   randomPost.Title,
 				"content": randomPost.Content,
 			},
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				arg := randomPost.ID
-
+			buildStubs: func(querier *mockdb.MockStore) {
 				querier.EXPECT().
-					GetPostById(gomock.Any(), gomock.Eq(arg)).
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
 					Times(1).
-					Return(randomPost, nil)
-
+					Return(db.Post{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: "sql: no rows in result set"})
+			},
+		},
+		{
+			name: "negative_UpdatePostById_InternalError",
+			body: gin.H{
+				"title":   randomPost.Title,
+				"content": randomPost.Content,
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
 				querier.EXPECT().
-					UpdatePostById(gomock.Any(), gomock.Any()).
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
 					Times(1).
 					Return(db.Post{}, sql.ErrConnDone)
 			},
@@ -496,9 +1552,9 @@ func TestUpdatePostById(t *testing.T) {
 			controller := gomock.NewController(t)
 			defer controller.Finish()
 
-			store := mockdb.NewMockQuerier(controller)
+			store := mockdb.NewMockStore(controller)
 			testCase.buildStubs(store)
-			server := NewServer(store)
+			server := newTestServer(store)
 			recorder := httptest.NewRecorder()
 
 			// Marshal body data to JSON
@@ -509,6 +1565,9 @@ func TestUpdatePostById(t *testing.T) {
 			requestUrl := fmt.Sprintf("%s/%d", path, randomPost.ID)
 			request, err := http.NewRequest(http.MethodPut, requestUrl, bytes.NewReader(data))
 			require.NoError(t, err)
+			if len(testCase.ifMatch) > 0 {
+				request.Header.Set(ifMatchHeader, testCase.ifMatch)
+			}
 
 			server.router.ServeHTTP(recorder, request)
 			testCase.checkResponse(t, recorder)
@@ -521,59 +1580,115 @@ func TestDeletePostById(t *testing.T) {
 
 	testCases := []struct {
 		name          string
-		buildStubs    func(store *mockdb.MockQuerier)
+		query         string
+		adminToken    string
+		ifMatch       string
+		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name: "positive_DeletePost",
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				arg := randomPost.ID
-
+			buildStubs: func(querier *mockdb.MockStore) {
 				querier.EXPECT().
-					GetPostById(gomock.Any(), gomock.Eq(arg)).
+					DeletePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
 					Times(1).
-					Return(randomPost, nil)
-
+					DoAndReturn(deletePostTx(randomPost))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "positive_DeletePost_IfMatch",
+			ifMatch: postETag(randomPost),
+			buildStubs: func(querier *mockdb.MockStore) {
 				querier.EXPECT().
-					DeletePost(gomock.Any(), gomock.Eq(arg)).
+					DeletePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
 					Times(1).
-					Return(nil)
+					DoAndReturn(deletePostTx(randomPost))
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
 		{
-			name: "negative_DeletePost_PostNotFound",
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				arg := randomPost.ID
-
+			name:    "negative_DeletePost_IfMatchStale",
+			ifMatch: `"0"`,
+			buildStubs: func(querier *mockdb.MockStore) {
 				querier.EXPECT().
-					GetPostById(gomock.Any(), gomock.Eq(arg)).
+					DeletePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
 					Times(1).
-					Return(db.Post{}, sql.ErrNoRows)
+					DoAndReturn(deletePostTx(randomPost))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errPreconditionFailed.Error()})
+			},
+		},
+		{
+			name:       "positive_DeletePost_Hard",
+			query:      "?hard=true",
+			adminToken: testAdminToken,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					PurgePost(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(int64(1), nil)
 
 				querier.EXPECT().
-					DeletePost(gomock.Any(), gomock.Eq(arg)).
+					DeletePostTx(gomock.Any(), gomock.Any(), gomock.Any()).
 					Times(0)
 			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "negative_DeletePost_HardPostNotFound",
+			query:      "?hard=true",
+			adminToken: testAdminToken,
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					PurgePost(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(int64(0), nil)
+			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
-				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: "sql: no rows in result set"})
 			},
 		},
 		{
-			name: "negative_DeletePost_InternalError",
-			buildStubs: func(querier *mockdb.MockQuerier) {
-				arg := randomPost.ID
-
+			name:       "negative_DeletePost_HardNotAdmin",
+			query:      "?hard=true",
+			adminToken: "wrong-token",
+			buildStubs: func(querier *mockdb.MockStore) {
 				querier.EXPECT().
-					GetPostById(gomock.Any(), gomock.Eq(arg)).
+					PurgePost(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errAdminOnly.Error()})
+			},
+		},
+		{
+			name: "negative_DeletePost_PostNotFound",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					DeletePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
 					Times(1).
-					Return(randomPost, nil)
-
+					Return(sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: "sql: no rows in result set"})
+			},
+		},
+		{
+			name: "negative_DeletePost_InternalError",
+			buildStubs: func(querier *mockdb.MockStore) {
 				querier.EXPECT().
-					DeletePost(gomock.Any(), gomock.Eq(arg)).
+					DeletePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
 					Times(1).
 					Return(sql.ErrConnDone)
 			},
@@ -588,15 +1703,21 @@ func TestDeletePostById(t *testing.T) {
 			controller := gomock.NewController(t)
 			defer controller.Finish()
 
-			store := mockdb.NewMockQuerier(controller)
+			store := mockdb.NewMockStore(controller)
 			testCase.buildStubs(store)
-			server := NewServer(store)
+			server := newTestServer(store)
 			recorder := httptest.NewRecorder()
 
 			path := "/posts"
-			requestUrl := fmt.Sprintf("%s/%d", path, randomPost.ID)
+			requestUrl := fmt.Sprintf("%s/%d%s", path, randomPost.ID, testCase.query)
 			request, err := http.NewRequest(http.MethodDelete, requestUrl, nil)
 			require.NoError(t, err)
+			if len(testCase.adminToken) > 0 {
+				request.Header.Set(adminTokenHeader, testCase.adminToken)
+			}
+			if len(testCase.ifMatch) > 0 {
+				request.Header.Set(ifMatchHeader, testCase.ifMatch)
+			}
 
 			server.router.ServeHTTP(recorder, request)
 			testCase.checkResponse(t, recorder)
@@ -604,20 +1725,73 @@ func TestDeletePostById(t *testing.T) {
 	}
 }
 
-type PostCollection struct {
-	Posts []PostResponse
+type listPostsParamsMatcher struct {
+	expected db.ListPostsByCreatedAtDescParams
+}
+
+// eqListPostsParams compares timestamps with time.Equal, as they lose their monotonic clock reading on the way through a cursor.
+func eqListPostsParams(expected db.ListPostsByCreatedAtDescParams) gomock.Matcher {
+	return listPostsParamsMatcher{expected: expected}
+}
+
+func (m listPostsParamsMatcher) Matches(x interface{}) bool {
+	actual, ok := x.(db.ListPostsByCreatedAtDescParams)
+	if !ok {
+		return false
+	}
+	expected := m.expected
+	if !expected.CursorTime.Time.Equal(actual.CursorTime.Time) || !expected.CreatedAfter.Time.Equal(actual.CreatedAfter.Time) {
+		return false
+	}
+	expected.CursorTime.Time, actual.CursorTime.Time = time.Time{}, time.Time{}
+	expected.CreatedAfter.Time, actual.CreatedAfter.Time = time.Time{}, time.Time{}
+	return gomock.Eq(expected).Matches(actual)
+}
+
+func (m listPostsParamsMatcher) String() string {
+	return fmt.Sprintf("is equal to %v", m.expected)
+}
+
+// updatePostTx stubs the transaction by running the update of the handler against current
+// and failing unless it produces the expected params and leaves the tags alone.
+func updatePostTx(current db.Post, expected db.UpdatePostByIdParams, result db.Post) func(context.Context, int32, func(db.Post) (db.UpdatePostTxParams, error)) (db.Post, error) {
+	return updatePostWithTagsTx(current, db.UpdatePostTxParams{UpdatePostByIdParams: expected}, result)
+}
+
+// replacePostTx is updatePostTx for the updates of PUT, which clear the tags when the body has none.
+func replacePostTx(current db.Post, expected db.UpdatePostByIdParams, result db.Post) func(context.Context, int32, func(db.Post) (db.UpdatePostTxParams, error)) (db.Post, error) {
+	return updatePostWithTagsTx(current, db.UpdatePostTxParams{UpdatePostByIdParams: expected, Tags: []string{}}, result)
+}
+
+// updatePostWithTagsTx is updatePostTx for updates that may change the tags.
+func updatePostWithTagsTx(current db.Post, expected db.UpdatePostTxParams, result db.Post) func(context.Context, int32, func(db.Post) (db.UpdatePostTxParams, error)) (db.Post, error) {
+	return func(_ context.Context, _ int32, update func(db.Post) (db.UpdatePostTxParams, error)) (db.Post, error) {
+		arg, err := update(current)
+		if err != nil {
+			return db.Post{}, err
+		}
+		if !reflect.DeepEqual(expected, arg) {
+			return db.Post{}, fmt.Errorf("unexpected update %+v, expected %+v", arg, expected)
+		}
+		return result, nil
+	}
+}
+
+// deletePostTx stubs the transaction by running the check of the handler against current.
+func deletePostTx(current db.Post) func(context.Context, int32, func(db.Post) error) error {
+	return func(_ context.Context, _ int32, check func(db.Post) error) error {
+		return check(current)
+	}
 }
 
-func requireBodyMatchPosts(t *testing.T, body *bytes.Buffer, expected []PostResponse) {
+func requireBodyMatchPosts(t *testing.T, body *bytes.Buffer, expected ListPostsResponse) {
 	data, err := io.ReadAll(body)
 	require.NoError(t, err)
 
-	var actual PostCollection
-	err = json.Unmarshal(data, &actual.Posts)
-	for index, actual := range actual.Posts {
-		require.NoError(t, err)
-		require.Equal(t, expected[index], actual)
-	}
+	var actual ListPostsResponse
+	err = json.Unmarshal(data, &actual)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
 }
 
 func requireBodyMatchPost(t *testing.T, body *bytes.Buffer, expected PostResponse) {
@@ -643,10 +1817,15 @@ func requireBodyMatchErrResponse(t *testing.T, body *bytes.Buffer, expected ErrR
 func generateRandomPost() db.Post {
 	randomPost := util.GenerateRandomPost()
 	return db.Post{
-		ID:        randomPost.ID,
-		Title:     randomPost.Title,
-		Content:   randomPost.Content,
-		CreatedAt: randomPost.CreatedAt,
-		UpdatedAt: randomPost.UpdatedAt,
+		ID:            randomPost.ID,
+		Title:         randomPost.Title,
+		Content:       randomPost.Content,
+		ContentFormat: db.ContentFormatPlain,
+		ContentHtml:   "<p>" + html.EscapeString(randomPost.Content) + "</p>",
+		Slug:          util.Slugify(randomPost.Title),
+		CreatedAt:     randomPost.CreatedAt,
+		UpdatedAt:     randomPost.UpdatedAt,
+		Status:        db.PostStatusDraft,
+		Version:       1,
 	}
 }
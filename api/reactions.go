@@ -0,0 +1,177 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+)
+
+const (
+	clientIDHeader    = "X-Client-Id"
+	maxClientIDLength = 200
+)
+
+var (
+	errInvalidClientID  = errors.New("the X-Client-Id header must identify the client in up to 200 characters")
+	errReactionNotFound = errors.New("the client has not reacted to the post this way")
+)
+
+type addReactionRequest struct {
+	Type string `json:"type" binding:"required,oneof=like love laugh wow sad angry"`
+}
+
+type removeReactionRequest struct {
+	ID   int    `uri:"id" binding:"required"`
+	Type string `uri:"type" binding:"required,oneof=like love laugh wow sad angry"`
+}
+
+type ReactionsResponse struct {
+	Reactions map[string]int `json:"reactions"`
+}
+
+// @Summary React to a post
+// @Tags Reaction
+// @Description React to a published post. A client reacts to a post at most once per reaction type, repeating the reaction changes nothing
+// @ID add-reaction
+// @Accept json
+// @Produce json
+// @Param id path string true "the specific post id"
+// @Param X-Client-Id header string true "identifier of the reacting client"
+// @Param input body addReactionRequest true "reaction type"
+// @Success 200 {object} ReactionsResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/{id}/reactions [post]
+func (s *Server) addReaction(context *gin.Context) {
+	var request getPostRequest
+	var requestBody addReactionRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := context.ShouldBindJSON(&requestBody); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	clientID, ok := reactingClient(context)
+	if !ok {
+		return
+	}
+
+	post, ok := s.publicPost(context, int32(request.ID))
+	if !ok {
+		return
+	}
+
+	_, err := s.store.AddReactionTx(context, db.CreatePostReactionParams{
+		PostID:   post.ID,
+		ClientID: clientID,
+		Type:     db.ReactionType(requestBody.Type),
+	})
+	if err != nil {
+		var pqError *pq.Error
+		if errors.As(err, &pqError) && pqError.Code.Name() == "foreign_key_violation" {
+			context.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	s.respondWithReactions(context, post.ID)
+}
+
+// @Summary Undo a reaction
+// @Tags Reaction
+// @Description Take back a reaction of the client to a post
+// @ID remove-reaction
+// @Produce json
+// @Param id path string true "the specific post id"
+// @Param type path string true "reaction type" Enums(like, love, laugh, wow, sad, angry)
+// @Param X-Client-Id header string true "identifier of the reacting client"
+// @Success 200 {object} ReactionsResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/{id}/reactions/{type} [delete]
+func (s *Server) removeReaction(context *gin.Context) {
+	var request removeReactionRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	clientID, ok := reactingClient(context)
+	if !ok {
+		return
+	}
+
+	removed, err := s.store.RemoveReactionTx(context, db.DeletePostReactionParams{
+		PostID:   int32(request.ID),
+		ClientID: clientID,
+		Type:     db.ReactionType(request.Type),
+	})
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if !removed {
+		context.JSON(http.StatusNotFound, errorResponse(errReactionNotFound))
+		return
+	}
+
+	s.respondWithReactions(context, int32(request.ID))
+}
+
+// reactingClient reads the client identifier, answering with 400 when it is missing or too long.
+func reactingClient(context *gin.Context) (string, bool) {
+	clientID := context.GetHeader(clientIDHeader)
+	if len(clientID) == 0 || len(clientID) > maxClientIDLength {
+		context.JSON(http.StatusBadRequest, errorResponse(errInvalidClientID))
+		return "", false
+	}
+	return clientID, true
+}
+
+// respondWithReactions answers with the current reaction counts of the post.
+func (s *Server) respondWithReactions(context *gin.Context, postID int32) {
+	reactions, err := s.loadReactionCounts(context, []int32{postID})
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := ReactionsResponse{Reactions: make(map[string]int, len(reactions[postID]))}
+	for reactionType, count := range reactions[postID] {
+		response.Reactions[string(reactionType)] = int(count)
+	}
+	context.JSON(http.StatusOK, response)
+}
+
+// loadReactionCounts fetches the reaction counters of the given posts in a single query.
+func (s *Server) loadReactionCounts(context *gin.Context, postIDs []int32) (map[int32]map[db.ReactionType]int32, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+
+	counters, err := s.store.GetReactionCountsByPostIds(context, postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	reactions := make(map[int32]map[db.ReactionType]int32, len(postIDs))
+	for _, counter := range counters {
+		if reactions[counter.PostID] == nil {
+			reactions[counter.PostID] = make(map[db.ReactionType]int32)
+		}
+		reactions[counter.PostID][counter.Type] = counter.Count
+	}
+	return reactions, nil
+}
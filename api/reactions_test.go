@@ -0,0 +1,269 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"strings"
+	"testing"
+)
+
+func TestAddReaction(t *testing.T) {
+	publishedPost := generateRandomPost()
+	publishedPost.Status = db.PostStatusPublished
+	clientID := "client-1"
+
+	testCases := []struct {
+		name          string
+		clientID      string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "positive_AddReaction",
+			clientID: clientID,
+			body:     gin.H{"type": "like"},
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.CreatePostReactionParams{PostID: publishedPost.ID, ClientID: clientID, Type: db.ReactionTypeLike}
+
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(publishedPost.ID)).
+					Times(1).
+					Return(publishedPost, nil)
+				querier.EXPECT().
+					AddReactionTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(true, nil)
+				querier.EXPECT().
+					GetReactionCountsByPostIds(gomock.Any(), gomock.Eq([]int32{publishedPost.ID})).
+					Times(1).
+					Return([]db.PostReactionCounter{{PostID: publishedPost.ID, Type: db.ReactionTypeLike, Count: 5}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchReactions(t, recorder.Body, ReactionsResponse{Reactions: map[string]int{"like": 5}})
+			},
+		},
+		{
+			name:     "positive_AddReaction_Repeated",
+			clientID: clientID,
+			body:     gin.H{"type": "like"},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(publishedPost.ID)).
+					Times(1).
+					Return(publishedPost, nil)
+				querier.EXPECT().
+					AddReactionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(false, nil)
+				querier.EXPECT().
+					GetReactionCountsByPostIds(gomock.Any(), gomock.Eq([]int32{publishedPost.ID})).
+					Times(1).
+					Return([]db.PostReactionCounter{{PostID: publishedPost.ID, Type: db.ReactionTypeLike, Count: 5}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchReactions(t, recorder.Body, ReactionsResponse{Reactions: map[string]int{"like": 5}})
+			},
+		},
+		{
+			name: "negative_AddReaction_MissingClientID",
+			body: gin.H{"type": "like"},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					AddReactionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errInvalidClientID.Error()})
+			},
+		},
+		{
+			name:     "negative_AddReaction_TooLongClientID",
+			clientID: strings.Repeat("c", maxClientIDLength+1),
+			body:     gin.H{"type": "like"},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					AddReactionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "negative_AddReaction_UnknownType",
+			clientID: clientID,
+			body:     gin.H{"type": "meh"},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					AddReactionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "negative_AddReaction_PostNotFound",
+			clientID: clientID,
+			body:     gin.H{"type": "like"},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(publishedPost.ID)).
+					Times(1).
+					Return(db.Post{}, sql.ErrNoRows)
+				querier.EXPECT().
+					AddReactionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "negative_AddReaction_InternalError",
+			clientID: clientID,
+			body:     gin.H{"type": "like"},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(publishedPost.ID)).
+					Times(1).
+					Return(publishedPost, nil)
+				querier.EXPECT().
+					AddReactionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(false, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(testCase.body)
+			require.NoError(t, err)
+
+			requestUrl := fmt.Sprintf("/posts/%d/reactions", publishedPost.ID)
+			request, err := http.NewRequest(http.MethodPost, requestUrl, bytes.NewReader(data))
+			require.NoError(t, err)
+			if len(testCase.clientID) > 0 {
+				request.Header.Set(clientIDHeader, testCase.clientID)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRemoveReaction(t *testing.T) {
+	postID := int32(42)
+	clientID := "client-1"
+
+	testCases := []struct {
+		name          string
+		reactionType  string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:         "positive_RemoveReaction",
+			reactionType: "love",
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.DeletePostReactionParams{PostID: postID, ClientID: clientID, Type: db.ReactionTypeLove}
+
+				querier.EXPECT().
+					RemoveReactionTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(true, nil)
+				querier.EXPECT().
+					GetReactionCountsByPostIds(gomock.Any(), gomock.Eq([]int32{postID})).
+					Times(1).
+					Return([]db.PostReactionCounter{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchReactions(t, recorder.Body, ReactionsResponse{Reactions: map[string]int{}})
+			},
+		},
+		{
+			name:         "negative_RemoveReaction_NotReacted",
+			reactionType: "love",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					RemoveReactionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(false, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errReactionNotFound.Error()})
+			},
+		},
+		{
+			name:         "negative_RemoveReaction_UnknownType",
+			reactionType: "meh",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					RemoveReactionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/posts/%d/reactions/%s", postID, testCase.reactionType)
+			request, err := http.NewRequest(http.MethodDelete, requestUrl, nil)
+			require.NoError(t, err)
+			request.Header.Set(clientIDHeader, clientID)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func requireBodyMatchReactions(t *testing.T, body *bytes.Buffer, expected ReactionsResponse) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var actual ReactionsResponse
+	err = json.Unmarshal(data, &actual)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
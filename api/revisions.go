@@ -0,0 +1,284 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+	"promova-test-task/markup"
+	"promova-test-task/util"
+)
+
+type listPostRevisionsRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+type getPostRevisionRequest struct {
+	ID       int `uri:"id" binding:"required"`
+	Revision int `uri:"rev" binding:"required,min=1"`
+}
+
+type diffPostRevisionsRequest struct {
+	From int `form:"from" binding:"required,min=1"`
+	To   int `form:"to" binding:"required,min=1"`
+}
+
+type PostRevisionResponse struct {
+	PostID        int    `json:"postId"`
+	Revision      int    `json:"revision"`
+	Title         string `json:"title"`
+	Content       string `json:"content"`
+	ContentFormat string `json:"contentFormat"`
+	CreatedAt     string `json:"createdAt"`
+}
+
+type ListPostRevisionsResponse struct {
+	Items []PostRevisionResponse `json:"items"`
+}
+
+type DiffLineResponse struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+type PostRevisionsDiffResponse struct {
+	From    int                `json:"from"`
+	To      int                `json:"to"`
+	Title   []DiffLineResponse `json:"title"`
+	Content []DiffLineResponse `json:"content"`
+}
+
+// @Summary Get post revisions
+// @Tags Post
+// @Description Get the previous versions of a post, newest first. A revision is saved on every update.
+// @ID get-post-revisions
+// @Produce json
+// @Param id path string true "the specific post id"
+// @Param limit query int false "maximum number of revisions (1-100, defaults to 20)"
+// @Param offset query int false "number of revisions to skip"
+// @Success 200 {object} ListPostRevisionsResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/{id}/revisions [get]
+func (s *Server) getPostRevisions(context *gin.Context) {
+	var request getPostRequest
+	var query listPostRevisionsRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if err := context.ShouldBindQuery(&query); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultPageSize
+	}
+
+	post, err := s.store.GetPostById(context, int32(request.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			context.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	revisions, err := s.store.ListPostRevisions(context, db.ListPostRevisionsParams{
+		PostID:     post.ID,
+		PageSize:   int32(query.Limit),
+		PageOffset: int32(query.Offset),
+	})
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := ListPostRevisionsResponse{Items: make([]PostRevisionResponse, 0, len(revisions))}
+	for _, revision := range revisions {
+		response.Items = append(response.Items, mapToPostRevisionResponse(revision))
+	}
+	context.JSON(http.StatusOK, response)
+}
+
+// @Summary Get post revision
+// @Tags Post
+// @Description Get a single previous version of a post
+// @ID get-post-revision
+// @Produce json
+// @Param id path string true "the specific post id"
+// @Param rev path int true "the revision number"
+// @Success 200 {object} PostRevisionResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/{id}/revisions/{rev} [get]
+func (s *Server) getPostRevision(context *gin.Context) {
+	var request getPostRevisionRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	revision, err := s.store.GetPostRevision(context, db.GetPostRevisionParams{
+		PostID:   int32(request.ID),
+		Revision: int32(request.Revision),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			context.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	context.JSON(http.StatusOK, mapToPostRevisionResponse(revision))
+}
+
+// @Summary Diff post revisions
+// @Tags Post
+// @Description Compare two revisions of a post line by line. Revisions longer than 1000 lines cannot be compared
+// @ID diff-post-revisions
+// @Produce json
+// @Param id path string true "the specific post id"
+// @Param from query int true "the revision to compare from"
+// @Param to query int true "the revision to compare to"
+// @Success 200 {object} PostRevisionsDiffResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 422 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/{id}/revisions/diff [get]
+func (s *Server) diffPostRevisions(context *gin.Context) {
+	var request getPostRequest
+	var query diffPostRevisionsRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if err := context.ShouldBindQuery(&query); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	revisions := make([]db.PostRevision, 0, 2)
+	for _, number := range []int{query.From, query.To} {
+		revision, err := s.store.GetPostRevision(context, db.GetPostRevisionParams{
+			PostID:   int32(request.ID),
+			Revision: int32(number),
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				context.JSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+			context.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		revisions = append(revisions, revision)
+	}
+
+	from, to := revisions[0], revisions[1]
+	title, err := util.LineDiff(from.Title, to.Title)
+	if err != nil {
+		context.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		return
+	}
+	content, err := util.LineDiff(from.Content, to.Content)
+	if err != nil {
+		context.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		return
+	}
+	context.JSON(http.StatusOK, PostRevisionsDiffResponse{
+		From:    int(from.Revision),
+		To:      int(to.Revision),
+		Title:   mapToDiffLinesResponse(title),
+		Content: mapToDiffLinesResponse(content),
+	})
+}
+
+// @Summary Restore post revision
+// @Tags Post
+// @Description Roll the title and content of a post back to a previous revision. The replaced version is saved as a new revision.
+// @ID restore-post-revision
+// @Produce json
+// @Param id path string true "the specific post id"
+// @Param rev path int true "the revision number"
+// @Success 200 {object} PostResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/{id}/revisions/{rev}/restore [post]
+func (s *Server) restorePostRevision(context *gin.Context) {
+	var request getPostRevisionRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	revision, err := s.store.GetPostRevision(context, db.GetPostRevisionParams{
+		PostID:   int32(request.ID),
+		Revision: int32(request.Revision),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			context.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// only the text is rolled back, the schedule and category stay as they are now
+	post, err := s.store.UpdatePostTx(context, revision.PostID, func(post db.Post) (db.UpdatePostTxParams, error) {
+		contentHtml, err := markup.Render(string(revision.ContentFormat), revision.Content)
+		if err != nil {
+			return db.UpdatePostTxParams{}, err
+		}
+		return db.UpdatePostTxParams{
+			UpdatePostByIdParams: db.UpdatePostByIdParams{
+				ID:            post.ID,
+				Title:         revision.Title,
+				Content:       revision.Content,
+				ContentFormat: revision.ContentFormat,
+				ContentHtml:   contentHtml,
+				PublishAt:     post.PublishAt,
+				CategoryID:    post.CategoryID,
+			},
+		}, nil
+	})
+	if err != nil {
+		respondTxError(context, err)
+		return
+	}
+
+	s.respondWithPost(context, post)
+}
+
+func mapToPostRevisionResponse(revision db.PostRevision) PostRevisionResponse {
+	return PostRevisionResponse{
+		PostID:        int(revision.PostID),
+		Revision:      int(revision.Revision),
+		Title:         revision.Title,
+		Content:       revision.Content,
+		ContentFormat: string(revision.ContentFormat),
+		CreatedAt:     revision.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func mapToDiffLinesResponse(lines []util.DiffLine) []DiffLineResponse {
+	response := make([]DiffLineResponse, 0, len(lines))
+	for _, line := range lines {
+		response = append(response, DiffLineResponse{Op: string(line.Op), Text: line.Text})
+	}
+	return response
+}
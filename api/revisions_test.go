@@ -0,0 +1,456 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"promova-test-task/util"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetPostRevisions(t *testing.T) {
+	randomPost := generateRandomPost()
+	revision := generateRandomPostRevision(randomPost.ID, 1)
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "positive_GetPostRevisions",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+
+				arg := db.ListPostRevisionsParams{PostID: randomPost.ID, PageSize: defaultPageSize}
+				querier.EXPECT().
+					ListPostRevisions(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.PostRevision{revision}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPostRevisions(t, recorder.Body, ListPostRevisionsResponse{
+					Items: []PostRevisionResponse{mapToPostRevisionResponse(revision)},
+				})
+			},
+		},
+		{
+			name:  "negative_GetPostRevisions_InvalidLimit",
+			query: "?limit=1000",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "negative_GetPostRevisions_PostNotFound",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(db.Post{}, sql.ErrNoRows)
+
+				querier.EXPECT().
+					ListPostRevisions(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "negative_GetPostRevisions_InternalError",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostById(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+
+				querier.EXPECT().
+					ListPostRevisions(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/posts/%d/revisions%s", randomPost.ID, testCase.query)
+			request, err := http.NewRequest(http.MethodGet, requestUrl, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetPostRevision(t *testing.T) {
+	revision := generateRandomPostRevision(generateRandomPost().ID, 2)
+	arg := db.GetPostRevisionParams{PostID: revision.PostID, Revision: revision.Revision}
+
+	testCases := []struct {
+		name          string
+		revision      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "positive_GetPostRevision",
+			revision: "2",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostRevision(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(revision, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPostRevision(t, recorder.Body, mapToPostRevisionResponse(revision))
+			},
+		},
+		{
+			name:     "negative_GetPostRevision_InvalidRevision",
+			revision: "abc",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostRevision(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "negative_GetPostRevision_NotFound",
+			revision: "2",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostRevision(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.PostRevision{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/posts/%d/revisions/%s", revision.PostID, testCase.revision)
+			request, err := http.NewRequest(http.MethodGet, requestUrl, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDiffPostRevisions(t *testing.T) {
+	postID := generateRandomPost().ID
+	from := generateRandomPostRevision(postID, 1)
+	from.Title = "Title"
+	from.Content = "first\nsecond"
+	to := generateRandomPostRevision(postID, 2)
+	to.Title = "Title"
+	to.Content = "first\nthird"
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "positive_DiffPostRevisions",
+			query: "?from=1&to=2",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostRevision(gomock.Any(), gomock.Eq(db.GetPostRevisionParams{PostID: postID, Revision: 1})).
+					Times(1).
+					Return(from, nil)
+
+				querier.EXPECT().
+					GetPostRevision(gomock.Any(), gomock.Eq(db.GetPostRevisionParams{PostID: postID, Revision: 2})).
+					Times(1).
+					Return(to, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var actual PostRevisionsDiffResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actual))
+				require.Equal(t, PostRevisionsDiffResponse{
+					From:  1,
+					To:    2,
+					Title: []DiffLineResponse{{Op: "equal", Text: "Title"}},
+					Content: []DiffLineResponse{
+						{Op: "equal", Text: "first"},
+						{Op: "delete", Text: "second"},
+						{Op: "insert", Text: "third"},
+					},
+				}, actual)
+			},
+		},
+		{
+			name:  "negative_DiffPostRevisions_TooLarge",
+			query: "?from=1&to=2",
+			buildStubs: func(querier *mockdb.MockStore) {
+				long := to
+				long.Content = strings.Repeat("line\n", util.MaxDiffLines+1)
+
+				querier.EXPECT().
+					GetPostRevision(gomock.Any(), gomock.Eq(db.GetPostRevisionParams{PostID: postID, Revision: 1})).
+					Times(1).
+					Return(from, nil)
+
+				querier.EXPECT().
+					GetPostRevision(gomock.Any(), gomock.Eq(db.GetPostRevisionParams{PostID: postID, Revision: 2})).
+					Times(1).
+					Return(long, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: util.ErrDiffTooLarge.Error()})
+			},
+		},
+		{
+			name:  "negative_DiffPostRevisions_MissingRevision",
+			query: "?from=1",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostRevision(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "negative_DiffPostRevisions_RevisionNotFound",
+			query: "?from=1&to=3",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostRevision(gomock.Any(), gomock.Eq(db.GetPostRevisionParams{PostID: postID, Revision: 1})).
+					Times(1).
+					Return(from, nil)
+
+				querier.EXPECT().
+					GetPostRevision(gomock.Any(), gomock.Eq(db.GetPostRevisionParams{PostID: postID, Revision: 3})).
+					Times(1).
+					Return(db.PostRevision{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/posts/%d/revisions/diff%s", postID, testCase.query)
+			request, err := http.NewRequest(http.MethodGet, requestUrl, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRestorePostRevision(t *testing.T) {
+	randomPost := generateRandomPost()
+	revision := generateRandomPostRevision(randomPost.ID, 1)
+	revision.Content = "Restored **content**"
+	revision.ContentFormat = db.ContentFormatMarkdown
+	arg := db.GetPostRevisionParams{PostID: revision.PostID, Revision: revision.Revision}
+
+	restoredPost := randomPost
+	restoredPost.Title = revision.Title
+	restoredPost.Content = revision.Content
+	restoredPost.ContentFormat = revision.ContentFormat
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "positive_RestorePostRevision",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostRevision(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(revision, nil)
+
+				updateArg := db.UpdatePostByIdParams{
+					ID:            randomPost.ID,
+					Title:         revision.Title,
+					Content:       revision.Content,
+					ContentFormat: revision.ContentFormat,
+					ContentHtml:   "<p>Restored <strong>content</strong></p>\n",
+					PublishAt:     randomPost.PublishAt,
+				}
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Eq(randomPost.ID), gomock.Any()).
+					Times(1).
+					DoAndReturn(updatePostTx(randomPost, updateArg, restoredPost))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPost(t, recorder.Body, mapToPostResponse(restoredPost))
+			},
+		},
+		{
+			name: "negative_RestorePostRevision_RevisionNotFound",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostRevision(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.PostRevision{}, sql.ErrNoRows)
+
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "negative_RestorePostRevision_PostDeleted",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostRevision(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(revision, nil)
+
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Post{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "negative_RestorePostRevision_InternalError",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetPostRevision(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(revision, nil)
+
+				querier.EXPECT().
+					UpdatePostTx(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Post{}, sql.ErrTxDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/posts/%d/revisions/%d/restore", randomPost.ID, revision.Revision)
+			request, err := http.NewRequest(http.MethodPost, requestUrl, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func requireBodyMatchPostRevisions(t *testing.T, body *bytes.Buffer, expected ListPostRevisionsResponse) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var actual ListPostRevisionsResponse
+	err = json.Unmarshal(data, &actual)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func requireBodyMatchPostRevision(t *testing.T, body *bytes.Buffer, expected PostRevisionResponse) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var actual PostRevisionResponse
+	err = json.Unmarshal(data, &actual)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func generateRandomPostRevision(postID int32, revision int32) db.PostRevision {
+	randomPost := generateRandomPost()
+	return db.PostRevision{
+		ID:            randomPost.ID,
+		PostID:        postID,
+		Revision:      revision,
+		Title:         randomPost.Title,
+		Content:       randomPost.Content,
+		ContentFormat: randomPost.ContentFormat,
+		CreatedAt:     time.Now(),
+	}
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+)
+
+type searchPostsRequest struct {
+	Query  string `form:"q" binding:"required,max=200"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int    `form:"offset" binding:"omitempty,min=0,max=10000"`
+}
+
+type SearchPostResponse struct {
+	PostResponse
+	Score   float32 `json:"score"`
+	Snippet string  `json:"snippet"`
+}
+
+type SearchPostsResponse struct {
+	Items []SearchPostResponse `json:"items"`
+}
+
+// @Summary Search posts
+// @Tags Post
+// @Description Full-text search over titles and content of published posts, best matches first
+// @ID search-posts
+// @Produce json
+// @Param q query string true "search query, supports quoted phrases, OR and -exclusions"
+// @Param limit query int false "maximum number of results (1-100, defaults to 20)"
+// @Param offset query int false "number of results to skip"
+// @Success 200 {object} SearchPostsResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/search [get]
+func (s *Server) searchPosts(context *gin.Context) {
+	var request searchPostsRequest
+
+	if err := context.ShouldBindQuery(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if request.Limit == 0 {
+		request.Limit = defaultPageSize
+	}
+
+	rows, err := s.store.SearchPosts(context, db.SearchPostsParams{
+		Query:      request.Query,
+		PageSize:   int32(request.Limit),
+		PageOffset: int32(request.Offset),
+	})
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	posts := make([]db.Post, 0, len(rows))
+	for _, row := range rows {
+		posts = append(posts, searchRowToPost(row))
+	}
+	relations, err := s.loadPostRelations(context, posts)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	context.JSON(http.StatusOK, mapToSearchPostsResponse(rows, relations))
+}
+
+func mapToSearchPostsResponse(rows []db.SearchPostsRow, relations postRelations) SearchPostsResponse {
+	response := SearchPostsResponse{Items: make([]SearchPostResponse, 0, len(rows))}
+
+	for _, row := range rows {
+		response.Items = append(response.Items, SearchPostResponse{
+			PostResponse: relations.mapToPostResponse(searchRowToPost(row)),
+			Score:        row.Score,
+			Snippet:      row.Snippet,
+		})
+	}
+	return response
+}
+
+func searchRowToPost(row db.SearchPostsRow) db.Post {
+	return db.Post{
+		ID:            row.ID,
+		Title:         row.Title,
+		Slug:          row.Slug,
+		Content:       row.Content,
+		ContentFormat: row.ContentFormat,
+		ContentHtml:   row.ContentHtml,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+		Status:        row.Status,
+		PublishedAt:   row.PublishedAt,
+		PublishAt:     row.PublishAt,
+		Version:       row.Version,
+		AuthorID:      row.AuthorID,
+		CategoryID:    row.CategoryID,
+	}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"testing"
+)
+
+func TestSearchPosts(t *testing.T) {
+	randomPost := generateRandomPost()
+	row := db.SearchPostsRow{
+		ID:            randomPost.ID,
+		Title:         randomPost.Title,
+		Slug:          randomPost.Slug,
+		Content:       randomPost.Content,
+		ContentFormat: randomPost.ContentFormat,
+		ContentHtml:   randomPost.ContentHtml,
+		CreatedAt:     randomPost.CreatedAt,
+		UpdatedAt:     randomPost.UpdatedAt,
+		Status:        randomPost.Status,
+		Version:       randomPost.Version,
+		Score:         0.6,
+		Snippet:       "the <mark>news</mark> of the day",
+	}
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "positive_SearchPosts",
+			query: url.Values{"q": {"news"}},
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.SearchPostsParams{Query: "news", PageSize: defaultPageSize}
+
+				querier.EXPECT().
+					SearchPosts(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.SearchPostsRow{row}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchSearchPosts(t, recorder.Body, SearchPostsResponse{
+					Items: []SearchPostResponse{{
+						PostResponse: mapToPostResponse(randomPost),
+						Score:        row.Score,
+						Snippet:      row.Snippet,
+					}},
+				})
+			},
+		},
+		{
+			name:  "positive_SearchPosts_WithPaging",
+			query: url.Values{"q": {"product release"}, "limit": {"5"}, "offset": {"10"}},
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.SearchPostsParams{Query: "product release", PageSize: 5, PageOffset: 10}
+
+				querier.EXPECT().
+					SearchPosts(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.SearchPostsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchSearchPosts(t, recorder.Body, SearchPostsResponse{Items: []SearchPostResponse{}})
+			},
+		},
+		{
+			name:  "negative_SearchPosts_MissingQuery",
+			query: url.Values{},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					SearchPosts(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "negative_SearchPosts_InternalError",
+			query: url.Values{"q": {"news"}},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					SearchPosts(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/posts/search?%s", testCase.query.Encode())
+			request, err := http.NewRequest(http.MethodGet, requestUrl, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func requireBodyMatchSearchPosts(t *testing.T, body *bytes.Buffer, expected SearchPostsResponse) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var actual SearchPostsResponse
+	err = json.Unmarshal(data, &actual)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
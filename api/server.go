@@ -1,43 +1,145 @@
 package api
 
 import (
+	"context"
+	"crypto/subtle"
+	"errors"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"net/http"
 	db "promova-test-task/db/sqlc"
 	_ "promova-test-task/docs"
+	"promova-test-task/storage"
+	"promova-test-task/util"
+	"strings"
 )
 
+const adminTokenHeader = "X-Admin-Token"
+
+var errUnknownMethod = errors.New("unknown method")
+
 type ErrResponse struct {
 	Error string `json:"error"`
 }
 
 // Server serves HTTP requests for attendance service
 type Server struct {
-	store  db.Store
-	router *gin.Engine
+	config     util.Config
+	store      db.Store
+	views      ViewRecorder
+	blobs      storage.BlobStore
+	clock      util.Clock
+	sitemaps   *sitemapCache
+	router     *gin.Engine
+	httpServer *http.Server
 }
 
 // NewServer creates a new HTTP server and sets up routing.
-func NewServer(store db.Store) *Server {
-	server := &Server{store: store}
+func NewServer(config util.Config, store db.Store, views ViewRecorder, blobs storage.BlobStore) *Server {
+	server := &Server{config: config, store: store, views: views, blobs: blobs, clock: util.RealClock{}, sitemaps: newSitemapCache()}
 	router := gin.Default()
 
 	router.GET("/posts", server.getPosts)
+	router.GET("/posts/search", server.searchPosts)
+	router.GET("/posts/export", server.exportPosts)
+	router.GET("/posts/popular", server.getPopularPosts)
+	router.GET("/posts/trash", server.getTrashedPosts)
+	router.GET("/posts/by-slug/:slug", server.getPostBySlug)
 	router.GET("/posts/:id", server.getPost)
 	router.POST("/posts", server.createPost)
+	router.POST("/posts:method", customMethods(map[string]gin.HandlerFunc{":batch": server.batchPosts}))
+	router.POST("/posts/import", server.importPosts)
 	router.PUT("/posts/:id", server.updatePost)
+	router.PATCH("/posts/:id", server.patchPost)
 	router.DELETE("/posts/:id", server.deletePost)
+	router.POST("/posts/:id/publish", server.publishPost)
+	router.POST("/posts/:id/unpublish", server.unpublishPost)
+	router.POST("/posts/:id/archive", server.archivePost)
+	router.POST("/posts/:id/restore", server.restorePost)
+	router.GET("/posts/:id/revisions", server.getPostRevisions)
+	router.GET("/posts/:id/revisions/diff", server.diffPostRevisions)
+	router.GET("/posts/:id/revisions/:rev", server.getPostRevision)
+	router.POST("/posts/:id/revisions/:rev/restore", server.restorePostRevision)
+	router.GET("/posts/:id/comments", server.getComments)
+	router.POST("/posts/:id/comments", server.createComment)
+	router.POST("/posts/:id/reactions", server.addReaction)
+	router.DELETE("/posts/:id/reactions/:type", server.removeReaction)
+	router.POST("/posts/:id/attachments", server.createAttachment)
+	router.DELETE("/posts/:id/attachments/:attachmentId", server.deleteAttachment)
+	router.PUT("/posts/:id/cover", server.putCover)
+
+	router.GET("/authors", server.getAuthors)
+	router.GET("/authors/:id", server.getAuthor)
+	router.GET("/authors/:id/posts", server.getAuthorPosts)
+	router.POST("/authors", server.createAuthor)
+	router.PUT("/authors/:id", server.updateAuthor)
+	router.DELETE("/authors/:id", server.deleteAuthor)
+
+	router.GET("/tags", server.getTags)
+
+	router.GET("/feed.rss", server.getRSSFeed)
+	router.GET("/feed.atom", server.getAtomFeed)
+	router.GET("/feed.json", server.getJSONFeed)
+	router.GET("/sitemap.xml", server.getSitemapIndex)
+	router.GET("/sitemaps/:file", server.getSitemap)
+
+	router.GET("/categories/tree", server.getCategoryTree)
+	router.GET("/categories/:id/posts", server.getCategoryPosts)
+	router.POST("/categories", server.createCategory)
+
+	router.POST("/comments/:id/approve", server.approveComment)
+	router.POST("/comments/:id/reject", server.rejectComment)
+	router.DELETE("/comments/:id", server.deleteComment)
+
+	// blobs kept on the local filesystem are served by the API itself
+	if local, ok := blobs.(*storage.LocalStore); ok {
+		router.Static(storage.LocalMediaPath, local.Dir())
+	}
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	server.router = router
+	server.httpServer = &http.Server{Handler: router}
 	return server
 }
 
 // Start runs the HTTP server on a specific address
 func (s *Server) Start(address string) error {
-	return s.router.Run(address)
+	s.httpServer.Addr = address
+	return s.httpServer.ListenAndServe()
+}
+
+// Shutdown stops accepting new connections and waits for the active requests to complete.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
+
+// isAdmin reports whether the request carries the configured admin token.
+func (s *Server) isAdmin(context *gin.Context) bool {
+	token := context.GetHeader(adminTokenHeader)
+	if len(s.config.AdminToken) == 0 || len(token) == 0 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.config.AdminToken)) == 1
+}
+
+// absoluteURL turns a path of the API into the URL it is publicly reachable at.
+func (s *Server) absoluteURL(path string) string {
+	return strings.TrimSuffix(s.config.BaseURL, "/") + path
+}
+
+// customMethods serves the custom methods of a collection, like POST /posts:batch. Gin routes no literal colon
+// within a path segment, so the route takes what follows the collection as a parameter to pick the method by.
+func customMethods(methods map[string]gin.HandlerFunc) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		handler, ok := methods[context.Param("method")]
+		if !ok {
+			context.JSON(http.StatusNotFound, errorResponse(errUnknownMethod))
+			return
+		}
+		handler(context)
+	}
 }
 
 func errorResponse(err error) ErrResponse {
@@ -0,0 +1,252 @@
+package api
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+	"sync"
+	"time"
+)
+
+const (
+	// sitemapShardSize is the most URLs a sitemap may list
+	sitemapShardSize   = 50000
+	sitemapIndexName   = "sitemap.xml"
+	sitemapNamespace   = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	sitemapContentType = "application/xml; charset=utf-8"
+)
+
+var errSitemapNotFound = errors.New("sitemap not found")
+
+type getSitemapRequest struct {
+	File string `uri:"file" binding:"required"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name       `xml:"sitemapindex"`
+	XMLNS    string         `xml:"xmlns,attr"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+type sitemapEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+type sitemapURL struct {
+	XMLName xml.Name `xml:"url"`
+	Loc     string   `xml:"loc"`
+	LastMod string   `xml:"lastmod"`
+}
+
+// sitemapCache keeps the generated sitemaps while the published posts stay the same, as told by their state.
+// The state is read from the posts themselves, so that writing posts takes no lock for the sake of the cache.
+type sitemapCache struct {
+	mu        sync.Mutex
+	state     db.GetSitemapStateRow
+	shards    []db.ListSitemapShardsRow
+	documents map[string][]byte
+}
+
+func newSitemapCache() *sitemapCache {
+	return &sitemapCache{documents: make(map[string][]byte)}
+}
+
+// sync drops what was generated from posts in another state.
+func (c *sitemapCache) sync(state db.GetSitemapStateRow) {
+	if state.Posts != c.state.Posts || state.IDSum != c.state.IDSum || !state.LastModified.Equal(c.state.LastModified) {
+		c.state = state
+		c.shards = nil
+		c.documents = make(map[string][]byte)
+	}
+}
+
+func (c *sitemapCache) getShards(state db.GetSitemapStateRow) ([]db.ListSitemapShardsRow, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sync(state)
+	return c.shards, c.shards != nil
+}
+
+func (c *sitemapCache) setShards(state db.GetSitemapStateRow, shards []db.ListSitemapShardsRow) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sync(state)
+	c.shards = shards
+}
+
+func (c *sitemapCache) getDocument(state db.GetSitemapStateRow, name string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sync(state)
+	document, ok := c.documents[name]
+	return document, ok
+}
+
+func (c *sitemapCache) setDocument(state db.GetSitemapStateRow, name string, document []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sync(state)
+	c.documents[name] = document
+}
+
+// @Summary Get sitemap index
+// @Tags Sitemap
+// @Description Get the sitemap index listing the sitemaps of the published posts, each of at most 50,000 URLs.
+// @Description Sitemaps are generated again only once a post changed
+// @ID get-sitemap-index
+// @Produce xml
+// @Success 200 {string} string
+// @Failure 500 {object} ErrResponse
+// @Router /sitemap.xml [get]
+func (s *Server) getSitemapIndex(context *gin.Context) {
+	state, err := s.store.GetSitemapState(context)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if document, ok := s.sitemaps.getDocument(state, sitemapIndexName); ok {
+		context.Data(http.StatusOK, sitemapContentType, document)
+		return
+	}
+
+	shards, err := s.sitemapShards(context, state)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	index := sitemapIndex{XMLNS: sitemapNamespace, Sitemaps: make([]sitemapEntry, 0, len(shards))}
+	for i, shard := range shards {
+		index.Sitemaps = append(index.Sitemaps, sitemapEntry{
+			Loc:     s.absoluteURL(fmt.Sprintf("/sitemaps/%s", sitemapShardName(i+1))),
+			LastMod: shard.LastModified.UTC().Format(time.RFC3339),
+		})
+	}
+	data, err := xml.Marshal(index)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	document := append([]byte(xml.Header), data...)
+	s.sitemaps.setDocument(state, sitemapIndexName, document)
+	context.Data(http.StatusOK, sitemapContentType, document)
+}
+
+// @Summary Get sitemap
+// @Tags Sitemap
+// @Description Get a sitemap of published posts listed by the sitemap index, with the time each post was last modified
+// @ID get-sitemap
+// @Produce xml
+// @Param file path string true "the name of the sitemap, posts-{n}.xml with n starting at 1"
+// @Success 200 {string} string
+// @Failure 404 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /sitemaps/{file} [get]
+func (s *Server) getSitemap(context *gin.Context) {
+	var request getSitemapRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	var number int
+	if _, err := fmt.Sscanf(request.File, "posts-%d.xml", &number); err != nil || number < 1 || sitemapShardName(number) != request.File {
+		context.JSON(http.StatusNotFound, errorResponse(errSitemapNotFound))
+		return
+	}
+
+	state, err := s.store.GetSitemapState(context)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if document, ok := s.sitemaps.getDocument(state, request.File); ok {
+		context.Data(http.StatusOK, sitemapContentType, document)
+		return
+	}
+
+	shards, err := s.sitemapShards(context, state)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if number > len(shards) {
+		context.JSON(http.StatusNotFound, errorResponse(errSitemapNotFound))
+		return
+	}
+
+	// the sitemap is written out while the posts are read and kept for the following requests
+	var document bytes.Buffer
+	writer := io.MultiWriter(context.Writer, &document)
+	encoder := xml.NewEncoder(writer)
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+		started = true
+		context.Header("Content-Type", sitemapContentType)
+		context.Status(http.StatusOK)
+		_, err := io.WriteString(writer, xml.Header+`<urlset xmlns="`+sitemapNamespace+`">`)
+		return err
+	}
+
+	err = s.store.StreamSitemapPosts(context, db.ListSitemapPostsParams{
+		FirstID:   shards[number-1].FirstID,
+		ShardSize: sitemapShardSize,
+	}, func(post db.ListSitemapPostsRow) error {
+		if err := start(); err != nil {
+			return err
+		}
+		return encoder.Encode(sitemapURL{
+			Loc:     s.absoluteURL(postSlugPath(post.Slug)),
+			LastMod: post.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	})
+	if err == nil {
+		if err = start(); err == nil {
+			_, err = io.WriteString(writer, "</urlset>\n")
+		}
+	}
+	if err != nil {
+		if !started {
+			context.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		// the status is already sent, all that is left is not to cache the truncated sitemap
+		log.Printf("failed to stream sitemap %s: %v", request.File, err)
+		return
+	}
+
+	s.sitemaps.setDocument(state, request.File, document.Bytes())
+}
+
+// sitemapShards splits the published posts into the sitemaps listed by the index.
+func (s *Server) sitemapShards(context *gin.Context, state db.GetSitemapStateRow) ([]db.ListSitemapShardsRow, error) {
+	if shards, ok := s.sitemaps.getShards(state); ok {
+		return shards, nil
+	}
+
+	shards, err := s.store.ListSitemapShards(context, sitemapShardSize)
+	if err != nil {
+		return nil, err
+	}
+	s.sitemaps.setShards(state, shards)
+	return shards, nil
+}
+
+func sitemapShardName(number int) string {
+	return fmt.Sprintf("posts-%d.xml", number)
+}
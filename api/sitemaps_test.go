@@ -0,0 +1,235 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"testing"
+	"time"
+)
+
+type testSitemap struct {
+	URLs []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"url"`
+}
+
+func TestGetSitemaps(t *testing.T) {
+	shards := []db.ListSitemapShardsRow{
+		{FirstID: 1, LastModified: testNow.Add(-time.Hour)},
+		{FirstID: 50007, LastModified: testNow},
+	}
+	state := db.GetSitemapStateRow{Posts: 50010, LastModified: testNow, IDSum: 1250625055}
+	rows := []db.ListSitemapPostsRow{
+		{ID: 50007, Slug: "first-post", UpdatedAt: testNow.Add(-time.Minute)},
+		{ID: 50010, Slug: "second post", UpdatedAt: testNow},
+	}
+
+	testCases := []struct {
+		name          string
+		path          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "positive_GetSitemapIndex",
+			path: "/sitemap.xml",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetSitemapState(gomock.Any()).
+					Times(1).
+					Return(state, nil)
+				querier.EXPECT().
+					ListSitemapShards(gomock.Any(), gomock.Eq(int64(sitemapShardSize))).
+					Times(1).
+					Return(shards, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, sitemapContentType, recorder.Header().Get("Content-Type"))
+
+				var index sitemapIndex
+				require.NoError(t, xml.Unmarshal(recorder.Body.Bytes(), &index))
+				require.Equal(t, []sitemapEntry{
+					{Loc: testBaseURL + "/sitemaps/posts-1.xml", LastMod: "2024-05-01T11:00:00Z"},
+					{Loc: testBaseURL + "/sitemaps/posts-2.xml", LastMod: "2024-05-01T12:00:00Z"},
+				}, index.Sitemaps)
+			},
+		},
+		{
+			name: "positive_GetSitemap",
+			path: "/sitemaps/posts-2.xml",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetSitemapState(gomock.Any()).
+					Times(1).
+					Return(state, nil)
+				querier.EXPECT().
+					ListSitemapShards(gomock.Any(), gomock.Eq(int64(sitemapShardSize))).
+					Times(1).
+					Return(shards, nil)
+				querier.EXPECT().
+					StreamSitemapPosts(gomock.Any(), gomock.Eq(db.ListSitemapPostsParams{FirstID: 50007, ShardSize: sitemapShardSize}), gomock.Any()).
+					Times(1).
+					DoAndReturn(streamSitemapPosts(rows, nil))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, sitemapContentType, recorder.Header().Get("Content-Type"))
+
+				var sitemap testSitemap
+				require.NoError(t, xml.Unmarshal(recorder.Body.Bytes(), &sitemap))
+				require.Len(t, sitemap.URLs, 2)
+				require.Equal(t, testBaseURL+"/posts/by-slug/first-post", sitemap.URLs[0].Loc)
+				require.Equal(t, "2024-05-01T11:59:00Z", sitemap.URLs[0].LastMod)
+				require.Equal(t, testBaseURL+"/posts/by-slug/second%20post", sitemap.URLs[1].Loc)
+			},
+		},
+		{
+			name: "negative_GetSitemap_BeyondLastShard",
+			path: "/sitemaps/posts-3.xml",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetSitemapState(gomock.Any()).
+					Times(1).
+					Return(state, nil)
+				querier.EXPECT().
+					ListSitemapShards(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(shards, nil)
+				querier.EXPECT().
+					StreamSitemapPosts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "negative_GetSitemap_UnknownName",
+			path: "/sitemaps/posts-01.xml",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetSitemapState(gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errSitemapNotFound.Error()})
+			},
+		},
+		{
+			name: "negative_GetSitemap_InternalError",
+			path: "/sitemaps/posts-1.xml",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetSitemapState(gomock.Any()).
+					Times(1).
+					Return(state, nil)
+				querier.EXPECT().
+					ListSitemapShards(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(shards, nil)
+				querier.EXPECT().
+					StreamSitemapPosts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(streamSitemapPosts(nil, sql.ErrConnDone))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "negative_GetSitemapIndex_InternalError",
+			path: "/sitemap.xml",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					GetSitemapState(gomock.Any()).
+					Times(1).
+					Return(db.GetSitemapStateRow{}, sql.ErrConnDone)
+				querier.EXPECT().
+					ListSitemapShards(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, testCase.path, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetSitemaps_CachedUntilPostsChange(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	store := mockdb.NewMockStore(controller)
+	shards := []db.ListSitemapShardsRow{{FirstID: 1, LastModified: testNow}}
+	rows := []db.ListSitemapPostsRow{{ID: 1, Slug: "first-post", UpdatedAt: testNow}}
+	state := db.GetSitemapStateRow{Posts: 1, LastModified: testNow, IDSum: 1}
+	// a post published since changes the state even though it was last modified before
+	changed := db.GetSitemapStateRow{Posts: 2, LastModified: testNow, IDSum: 3}
+	gomock.InOrder(
+		store.EXPECT().GetSitemapState(gomock.Any()).Times(2).Return(state, nil),
+		store.EXPECT().GetSitemapState(gomock.Any()).Times(1).Return(db.GetSitemapStateRow{Posts: 1, LastModified: testNow.In(time.FixedZone("", 3600)), IDSum: 1}, nil),
+		store.EXPECT().GetSitemapState(gomock.Any()).Times(1).Return(changed, nil),
+	)
+	store.EXPECT().
+		ListSitemapShards(gomock.Any(), gomock.Any()).
+		Times(2).
+		Return(shards, nil)
+	store.EXPECT().
+		StreamSitemapPosts(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(streamSitemapPosts(rows, nil))
+	server := newTestServer(store)
+
+	var bodies []string
+	for _, path := range []string{"/sitemaps/posts-1.xml", "/sitemap.xml", "/sitemaps/posts-1.xml", "/sitemaps/posts-1.xml"} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		bodies = append(bodies, recorder.Body.String())
+	}
+	require.Equal(t, bodies[0], bodies[2])
+	require.Equal(t, bodies[0], bodies[3])
+}
+
+// streamSitemapPosts stubs the cursor by calling back with the rows, then failing with err if any.
+func streamSitemapPosts(rows []db.ListSitemapPostsRow, err error) func(context.Context, db.ListSitemapPostsParams, func(db.ListSitemapPostsRow) error) error {
+	return func(_ context.Context, _ db.ListSitemapPostsParams, fn func(db.ListSitemapPostsRow) error) error {
+		for _, row := range rows {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+}
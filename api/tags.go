@@ -0,0 +1,104 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+	"strings"
+)
+
+const defaultTagMatch = "any"
+
+type listTagsRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+type TagResponse struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	PostCount int    `json:"postCount"`
+}
+
+type ListTagsResponse struct {
+	Items []TagResponse `json:"items"`
+}
+
+// @Summary Get tags
+// @Tags Tag
+// @Description Get a page of tags with the number of published posts carrying each, most used first
+// @ID get-tags
+// @Produce json
+// @Param limit query int false "maximum number of tags (1-100, defaults to 20)"
+// @Param offset query int false "number of tags to skip"
+// @Success 200 {object} ListTagsResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /tags [get]
+func (s *Server) getTags(context *gin.Context) {
+	var request listTagsRequest
+
+	if err := context.ShouldBindQuery(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if request.Limit == 0 {
+		request.Limit = defaultPageSize
+	}
+
+	tags, err := s.store.ListTags(context, db.ListTagsParams{
+		PageSize:   int32(request.Limit),
+		PageOffset: int32(request.Offset),
+	})
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := ListTagsResponse{Items: make([]TagResponse, 0, len(tags))}
+	for _, tag := range tags {
+		response.Items = append(response.Items, TagResponse{
+			ID:        int(tag.ID),
+			Name:      tag.Name,
+			PostCount: int(tag.PostCount),
+		})
+	}
+	context.JSON(http.StatusOK, response)
+}
+
+// loadTags fetches the tag names of the given posts in a single query.
+func (s *Server) loadTags(context *gin.Context, postIDs []int32) (map[int32][]string, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := s.store.GetTagsByPostIds(context, postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[int32][]string, len(postIDs))
+	for _, row := range rows {
+		tags[row.PostID] = append(tags[row.PostID], row.Name)
+	}
+	return tags, nil
+}
+
+// normalizeTags trims and lowercases tag names and drops empty and repeated ones, keeping nil as nil.
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if len(tag) == 0 || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
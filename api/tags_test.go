@@ -0,0 +1,94 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"testing"
+)
+
+func TestGetTags(t *testing.T) {
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "positive_GetTags",
+			query: "?limit=2",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListTags(gomock.Any(), gomock.Eq(db.ListTagsParams{PageSize: 2})).
+					Times(1).
+					Return([]db.ListTagsRow{
+						{ID: 1, Name: "go", PostCount: 3},
+						{ID: 2, Name: "sql", PostCount: 0},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var actual ListTagsResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &actual))
+				require.Equal(t, ListTagsResponse{Items: []TagResponse{
+					{ID: 1, Name: "go", PostCount: 3},
+					{ID: 2, Name: "sql", PostCount: 0},
+				}}, actual)
+			},
+		},
+		{
+			name:  "negative_GetTags_InvalidOffset",
+			query: "?offset=-1",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListTags(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "negative_GetTags_InternalError",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListTags(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/tags"+testCase.query, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	require.Nil(t, normalizeTags(nil))
+	require.Equal(t, []string{}, normalizeTags([]string{" ", ""}))
+	require.Equal(t, []string{"go", "sql"}, normalizeTags([]string{"Go ", "sql", "GO"}))
+}
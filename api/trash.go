@@ -0,0 +1,102 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+)
+
+type listTrashedPostsRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+type TrashedPostResponse struct {
+	PostResponse
+	DeletedAt string `json:"deletedAt"`
+}
+
+type ListTrashedPostsResponse struct {
+	Items []TrashedPostResponse `json:"items"`
+}
+
+// @Summary Get trashed posts
+// @Tags Post
+// @Description Get deleted posts that can still be restored, most recently deleted first
+// @ID get-trashed-posts
+// @Produce json
+// @Param limit query int false "maximum number of posts (1-100, defaults to 20)"
+// @Param offset query int false "number of posts to skip"
+// @Success 200 {object} ListTrashedPostsResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/trash [get]
+func (s *Server) getTrashedPosts(context *gin.Context) {
+	var request listTrashedPostsRequest
+
+	if err := context.ShouldBindQuery(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if request.Limit == 0 {
+		request.Limit = defaultPageSize
+	}
+
+	posts, err := s.store.ListDeletedPosts(context, db.ListDeletedPostsParams{
+		PageSize:   int32(request.Limit),
+		PageOffset: int32(request.Offset),
+	})
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	postsResponse, err := s.postsResponse(context, posts)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := ListTrashedPostsResponse{Items: make([]TrashedPostResponse, 0, len(posts))}
+	for i, post := range posts {
+		response.Items = append(response.Items, TrashedPostResponse{
+			PostResponse: postsResponse[i],
+			DeletedAt:    post.DeletedAt.Time.Format("2006-01-02 15:04:05"),
+		})
+	}
+	context.JSON(http.StatusOK, response)
+}
+
+// @Summary Restore post
+// @Tags Post
+// @Description Bring a deleted post back from the trash
+// @ID restore-post
+// @Produce json
+// @Param id path string true "the specific post id"
+// @Success 200 {object} PostResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 404 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/{id}/restore [post]
+func (s *Server) restorePost(context *gin.Context) {
+	var request getPostRequest
+
+	if err := context.ShouldBindUri(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	post, err := s.store.RestorePost(context, int32(request.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			context.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	s.respondWithPost(context, post)
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"testing"
+	"time"
+)
+
+func TestGetTrashedPosts(t *testing.T) {
+	trashedPost := generateRandomPost()
+	trashedPost.DeletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "positive_GetTrashedPosts",
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListDeletedPostsParams{PageSize: defaultPageSize}
+
+				querier.EXPECT().
+					ListDeletedPosts(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Post{trashedPost}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchTrashedPosts(t, recorder.Body, ListTrashedPostsResponse{
+					Items: []TrashedPostResponse{{
+						PostResponse: mapToPostResponse(trashedPost),
+						DeletedAt:    trashedPost.DeletedAt.Time.Format("2006-01-02 15:04:05"),
+					}},
+				})
+			},
+		},
+		{
+			name:  "positive_GetTrashedPosts_WithPaging",
+			query: "?limit=5&offset=5",
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListDeletedPostsParams{PageSize: 5, PageOffset: 5}
+
+				querier.EXPECT().
+					ListDeletedPosts(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Post{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchTrashedPosts(t, recorder.Body, ListTrashedPostsResponse{Items: []TrashedPostResponse{}})
+			},
+		},
+		{
+			name:  "negative_GetTrashedPosts_InvalidLimit",
+			query: "?limit=0",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListDeletedPosts(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.Post{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "negative_GetTrashedPosts_InternalError",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListDeletedPosts(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/posts/trash%s", testCase.query)
+			request, err := http.NewRequest(http.MethodGet, requestUrl, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRestorePost(t *testing.T) {
+	randomPost := generateRandomPost()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "positive_RestorePost",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					RestorePost(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(randomPost, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPost(t, recorder.Body, mapToPostResponse(randomPost))
+			},
+		},
+		{
+			name: "negative_RestorePost_NotInTrash",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					RestorePost(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(db.Post{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "negative_RestorePost_InternalError",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					RestorePost(gomock.Any(), gomock.Eq(randomPost.ID)).
+					Times(1).
+					Return(db.Post{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/posts/%d/restore", randomPost.ID)
+			request, err := http.NewRequest(http.MethodPost, requestUrl, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func requireBodyMatchTrashedPosts(t *testing.T, body *bytes.Buffer, expected ListTrashedPostsResponse) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var actual ListTrashedPostsResponse
+	err = json.Unmarshal(data, &actual)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	db "promova-test-task/db/sqlc"
+	"promova-test-task/worker"
+	"time"
+)
+
+const defaultPopularWindow = 24 * time.Hour
+
+var errInvalidPopularWindow = errors.New("window must be a duration between 1h and 720h, like 24h")
+
+// ViewRecorder counts post views. Recording must be cheap, implementations buffer the views and write them in batches.
+type ViewRecorder interface {
+	RecordView(postID int32)
+}
+
+type popularPostsRequest struct {
+	Window string `form:"window"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type PopularPostResponse struct {
+	PostResponse
+	Views int64 `json:"views"`
+}
+
+type PopularPostsResponse struct {
+	Items []PopularPostResponse `json:"items"`
+}
+
+// @Summary Get popular posts
+// @Tags Post
+// @Description Get the published posts viewed the most within a recent time window, most viewed first.
+// @Description Views are counted in hourly buckets and written with a delay of a few seconds
+// @ID get-popular-posts
+// @Produce json
+// @Param window query string false "how far back to count views, from 1h to 720h, defaults to 24h"
+// @Param limit query int false "maximum number of posts (1-100, defaults to 20)"
+// @Success 200 {object} PopularPostsResponse
+// @Failure 400 {object} ErrResponse
+// @Failure 500 {object} ErrResponse
+// @Router /posts/popular [get]
+func (s *Server) getPopularPosts(context *gin.Context) {
+	var request popularPostsRequest
+
+	if err := context.ShouldBindQuery(&request); err != nil {
+		context.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if request.Limit == 0 {
+		request.Limit = defaultPageSize
+	}
+
+	window := defaultPopularWindow
+	if len(request.Window) > 0 {
+		var err error
+		window, err = time.ParseDuration(request.Window)
+		if err != nil || window < worker.ViewBucketSize || window > worker.ViewRetention {
+			context.JSON(http.StatusBadRequest, errorResponse(errInvalidPopularWindow))
+			return
+		}
+	}
+
+	rows, err := s.store.ListPopularPosts(context, db.ListPopularPostsParams{
+		Since:    s.clock.Now().UTC().Add(-window).Truncate(worker.ViewBucketSize),
+		PageSize: int32(request.Limit),
+	})
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	posts := make([]db.Post, 0, len(rows))
+	for _, row := range rows {
+		posts = append(posts, popularRowToPost(row))
+	}
+	relations, err := s.loadPostRelations(context, posts)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := PopularPostsResponse{Items: make([]PopularPostResponse, 0, len(rows))}
+	for i, row := range rows {
+		response.Items = append(response.Items, PopularPostResponse{
+			PostResponse: relations.mapToPostResponse(posts[i]),
+			Views:        row.Views,
+		})
+	}
+	context.JSON(http.StatusOK, response)
+}
+
+// recordView counts a view of the post when readers can see it.
+func (s *Server) recordView(post db.Post) {
+	if s.views != nil && post.Status == db.PostStatusPublished {
+		s.views.RecordView(post.ID)
+	}
+}
+
+// loadViewCounts fetches the total views of the given posts in a single query.
+func (s *Server) loadViewCounts(context *gin.Context, postIDs []int32) (map[int32]int64, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := s.store.GetViewCountsByPostIds(context, postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int32]int64, len(rows))
+	for _, row := range rows {
+		counts[row.PostID] = row.Count
+	}
+	return counts, nil
+}
+
+func popularRowToPost(row db.ListPopularPostsRow) db.Post {
+	return db.Post{
+		ID:            row.ID,
+		Title:         row.Title,
+		Slug:          row.Slug,
+		Content:       row.Content,
+		ContentFormat: row.ContentFormat,
+		ContentHtml:   row.ContentHtml,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+		Status:        row.Status,
+		PublishedAt:   row.PublishedAt,
+		PublishAt:     row.PublishAt,
+		Version:       row.Version,
+		AuthorID:      row.AuthorID,
+		CategoryID:    row.CategoryID,
+	}
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"testing"
+	"time"
+)
+
+func TestGetPopularPosts(t *testing.T) {
+	randomPost := generateRandomPost()
+	randomPost.Status = db.PostStatusPublished
+	row := db.ListPopularPostsRow{
+		ID:            randomPost.ID,
+		Title:         randomPost.Title,
+		Slug:          randomPost.Slug,
+		Content:       randomPost.Content,
+		ContentFormat: randomPost.ContentFormat,
+		ContentHtml:   randomPost.ContentHtml,
+		CreatedAt:     randomPost.CreatedAt,
+		UpdatedAt:     randomPost.UpdatedAt,
+		Status:        randomPost.Status,
+		Version:       randomPost.Version,
+		Views:         1200,
+	}
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "positive_GetPopularPosts",
+			query: url.Values{},
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListPopularPostsParams{Since: testNow.Add(-24 * time.Hour), PageSize: defaultPageSize}
+
+				querier.EXPECT().
+					ListPopularPosts(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.ListPopularPostsRow{row}, nil)
+				querier.EXPECT().
+					GetViewCountsByPostIds(gomock.Any(), gomock.Eq([]int32{randomPost.ID})).
+					Times(1).
+					Return([]db.PostViewCount{{PostID: randomPost.ID, Count: 5000}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				expectedPost := mapToPostResponse(randomPost)
+				expectedPost.ViewCount = 5000
+
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPopularPosts(t, recorder.Body, PopularPostsResponse{
+					Items: []PopularPostResponse{{PostResponse: expectedPost, Views: row.Views}},
+				})
+			},
+		},
+		{
+			name:  "positive_GetPopularPosts_WindowStartsAtBucket",
+			query: url.Values{"window": {"90m"}, "limit": {"5"}},
+			buildStubs: func(querier *mockdb.MockStore) {
+				arg := db.ListPopularPostsParams{Since: testNow.Add(-2 * time.Hour), PageSize: 5}
+
+				querier.EXPECT().
+					ListPopularPosts(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.ListPopularPostsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPopularPosts(t, recorder.Body, PopularPostsResponse{Items: []PopularPostResponse{}})
+			},
+		},
+		{
+			name:  "negative_GetPopularPosts_InvalidWindow",
+			query: url.Values{"window": {"day"}},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListPopularPosts(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchErrResponse(t, recorder.Body, ErrResponse{Error: errInvalidPopularWindow.Error()})
+			},
+		},
+		{
+			name:  "negative_GetPopularPosts_WindowBeyondRetention",
+			query: url.Values{"window": {"2000h"}},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListPopularPosts(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "negative_GetPopularPosts_InternalError",
+			query: url.Values{"window": {"1h"}},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ListPopularPosts(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/posts/popular?%s", testCase.query.Encode())
+			request, err := http.NewRequest(http.MethodGet, requestUrl, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			testCase.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetPost_RecordsView(t *testing.T) {
+	publishedPost := generateRandomPost()
+	publishedPost.Status = db.PostStatusPublished
+	draftPost := generateRandomPost()
+	draftPost.ID = publishedPost.ID + 1
+
+	testCases := []struct {
+		name          string
+		post          db.Post
+		adminToken    string
+		expectedViews []int32
+	}{
+		{
+			name:          "positive_GetPost_PublishedCountsView",
+			post:          publishedPost,
+			expectedViews: []int32{publishedPost.ID},
+		},
+		{
+			name:       "positive_GetPost_DraftNotCounted",
+			post:       draftPost,
+			adminToken: testAdminToken,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			store.EXPECT().
+				GetPostById(gomock.Any(), gomock.Eq(testCase.post.ID)).
+				Times(1).
+				Return(testCase.post, nil)
+			server := newTestServer(store)
+			recorder := httptest.NewRecorder()
+
+			requestUrl := fmt.Sprintf("/posts/%d", testCase.post.ID)
+			request, err := http.NewRequest(http.MethodGet, requestUrl, nil)
+			require.NoError(t, err)
+			if len(testCase.adminToken) > 0 {
+				request.Header.Set(adminTokenHeader, testCase.adminToken)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Equal(t, testCase.expectedViews, server.views.(*testViewRecorder).postIDs)
+		})
+	}
+}
+
+func requireBodyMatchPopularPosts(t *testing.T, body *bytes.Buffer, expected PopularPostsResponse) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var actual PopularPostsResponse
+	err = json.Unmarshal(data, &actual)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
@@ -1,19 +1,32 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"github.com/golang-migrate/migrate/v4"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
 	"promova-test-task/api"
 	db "promova-test-task/db/sqlc"
+	"promova-test-task/storage"
 	"promova-test-task/util"
+	"promova-test-task/worker"
+	"syscall"
+	"time"
 
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	_ "github.com/lib/pq"
 )
 
+const (
+	shutdownTimeout  = 5 * time.Second
+	viewFlushTimeout = 5 * time.Second
+)
+
 // @title Promova Test Task
 // @version 0.0.1
 // @description Golang microservice for CURD operations with news
@@ -33,30 +46,68 @@ func main() {
 
 	runDBMigration(config.MigrationURL, config.DBSource)
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	blobs, err := storage.NewBlobStore(config)
+	if err != nil {
+		log.Fatal("cannot create the blob store:", err)
+	}
+
 	store := db.NewStore(conn)
-	server := api.NewServer(store)
+	views := worker.NewViewCounter(store, util.RealClock{}, config.ViewFlushInterval)
+	views.Start(ctx)
+	server := api.NewServer(config, store, views, blobs)
+
+	publisher := worker.NewPublisher(store, util.RealClock{}, config.PublishPollInterval)
+	publisher.Start(ctx)
+	covers := worker.NewCoverProcessor(store, blobs, util.RealClock{}, config.CoverPollInterval)
+	covers.Start(ctx)
+
+	go func() {
+		if err := server.Start(config.ServerAddress); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Cannot start the server at the address {}:\n{}", config.ServerAddress, err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("shutting down")
 
-	if err = server.Start(config.ServerAddress); err != nil {
-		log.Fatal("Cannot start the server at the address {}:\n{}", config.ServerAddress, err)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err = server.Shutdown(shutdownCtx); err != nil {
+		log.Println("failed to shut down the server gracefully:", err)
+	}
+	publisher.Stop()
+	covers.Stop()
+	// the server is down by now, so the final flush gets every view it counted. It has its own deadline,
+	// as a slow shutdown may have used up the one of the server
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), viewFlushTimeout)
+	defer cancelFlush()
+	if err = views.Stop(flushCtx); err != nil {
+		log.Println("failed to flush post views:", err)
 	}
 }
 
+// runDBMigration applies the migrations the database does not have yet, on a new database all of them.
 func runDBMigration(migrationURL string, dbSource string) {
 	m, err := migrate.New(migrationURL, dbSource)
 	if err != nil {
 		log.Fatal("cannot create new migrate instance:", err)
 	}
-	version, _, err := m.Version()
-	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
-		log.Fatal("failed to fetch migration version:", err)
+
+	err = m.Up()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		log.Fatal("failed to run migrate up:", err)
+	}
+	version, _, versionErr := m.Version()
+	if versionErr != nil {
+		log.Fatal("failed to fetch migration version:", versionErr)
 	}
 
-	if errors.Is(err, migrate.ErrNilVersion) {
-		if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-			log.Fatal("failed to run migrate up:", err)
-		}
-		log.Println("db migrated successfully")
-	} else {
+	if errors.Is(err, migrate.ErrNoChange) {
 		log.Printf("db already migrated to version: %d\n", version)
+	} else {
+		log.Printf("db migrated successfully to version: %d\n", version)
 	}
 }
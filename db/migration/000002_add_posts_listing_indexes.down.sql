@@ -0,0 +1,5 @@
+drop index if exists posts_title_id_idx;
+
+drop index if exists posts_updated_at_id_idx;
+
+drop index if exists posts_created_at_id_idx;
@@ -0,0 +1,5 @@
+create index if not exists posts_created_at_id_idx on posts (created_at, id);
+
+create index if not exists posts_updated_at_id_idx on posts (updated_at, id);
+
+create index if not exists posts_title_id_idx on posts (title, id);
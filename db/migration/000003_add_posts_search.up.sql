@@ -0,0 +1,6 @@
+alter table posts
+    add column if not exists search_vector tsvector generated always as (
+        setweight(to_tsvector('english', title), 'A') || setweight(to_tsvector('english', content), 'B')
+    ) stored;
+
+create index if not exists posts_search_vector_idx on posts using gin (search_vector);
@@ -0,0 +1,7 @@
+drop index if exists posts_status_idx;
+
+alter table posts
+    drop column if exists published_at,
+    drop column if exists status;
+
+drop type if exists post_status;
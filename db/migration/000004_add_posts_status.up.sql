@@ -0,0 +1,12 @@
+create type post_status as enum ('draft', 'published', 'archived');
+
+alter table posts
+    add column if not exists status post_status not null default 'draft',
+    add column if not exists published_at timestamptz;
+
+-- posts created before the lifecycle existed were already public
+alter table posts disable trigger update_modified_time;
+update posts set status = 'published', published_at = created_at;
+alter table posts enable trigger update_modified_time;
+
+create index if not exists posts_status_idx on posts (status);
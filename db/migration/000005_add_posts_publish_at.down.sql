@@ -0,0 +1,3 @@
+drop index if exists posts_publish_at_idx;
+
+alter table posts drop column if exists publish_at;
@@ -0,0 +1,3 @@
+alter table posts add column if not exists publish_at timestamptz;
+
+create index if not exists posts_publish_at_idx on posts (publish_at) where publish_at is not null;
@@ -0,0 +1,3 @@
+drop index if exists posts_deleted_at_idx;
+
+alter table posts drop column if exists deleted_at;
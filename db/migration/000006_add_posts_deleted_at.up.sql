@@ -0,0 +1,3 @@
+alter table posts add column if not exists deleted_at timestamptz;
+
+create index if not exists posts_deleted_at_idx on posts (deleted_at) where deleted_at is not null;
@@ -0,0 +1,9 @@
+create table if not exists post_revisions (
+    id serial primary key,
+    post_id integer not null references posts (id) on delete cascade,
+    revision integer not null,
+    title text not null,
+    content text not null,
+    created_at timestamptz not null default (now()),
+    unique (post_id, revision)
+);
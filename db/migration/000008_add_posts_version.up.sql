@@ -0,0 +1,10 @@
+alter table posts add column if not exists version integer not null default 1;
+
+CREATE OR REPLACE FUNCTION update_modified_at()
+RETURNS TRIGGER AS $$
+BEGIN
+  NEW.updated_at = CURRENT_TIMESTAMP;
+  NEW.version = OLD.version + 1;
+RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,5 @@
+drop index if exists posts_author_id_id_idx;
+
+alter table posts drop column if exists author_id;
+
+drop table if exists authors;
@@ -0,0 +1,12 @@
+create table if not exists authors (
+    id serial primary key,
+    name text not null,
+    email text not null unique,
+    bio text not null default '',
+    created_at timestamptz not null default (now()),
+    updated_at timestamptz not null default (now())
+);
+
+alter table posts add column if not exists author_id integer references authors (id) on delete restrict;
+
+create index if not exists posts_author_id_id_idx on posts (author_id, id);
@@ -0,0 +1,3 @@
+drop table if exists post_tags;
+
+drop table if exists tags;
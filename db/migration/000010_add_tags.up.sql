@@ -0,0 +1,12 @@
+create table if not exists tags (
+    id serial primary key,
+    name text not null unique
+);
+
+create table if not exists post_tags (
+    post_id integer not null references posts (id) on delete cascade,
+    tag_id integer not null references tags (id) on delete cascade,
+    primary key (post_id, tag_id)
+);
+
+create index if not exists post_tags_tag_id_post_id_idx on post_tags (tag_id, post_id);
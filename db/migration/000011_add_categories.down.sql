@@ -0,0 +1,5 @@
+drop index if exists posts_category_id_id_idx;
+
+alter table posts drop column if exists category_id;
+
+drop table if exists categories;
@@ -0,0 +1,12 @@
+create table if not exists categories (
+    id serial primary key,
+    parent_id integer references categories (id) on delete restrict,
+    name text not null,
+    created_at timestamptz not null default (now())
+);
+
+create unique index if not exists categories_parent_id_name_idx on categories (coalesce(parent_id, 0), name);
+
+alter table posts add column if not exists category_id integer references categories (id) on delete restrict;
+
+create index if not exists posts_category_id_id_idx on posts (category_id, id);
@@ -0,0 +1,5 @@
+drop table if exists post_slug_aliases;
+
+alter table posts drop constraint if exists posts_slug_key;
+
+alter table posts drop column if exists slug;
@@ -0,0 +1,64 @@
+alter table posts add column if not exists slug text;
+
+alter table posts add constraint posts_slug_key unique (slug);
+
+-- existing posts get the slugs util.Slugify and util.UniqueSlug would give them, in the order they were written:
+-- Latin and Cyrillic letters are transliterated, and a taken slug gets the lowest free -2, -3, ... suffix
+alter table posts disable trigger update_modified_time;
+do $$
+declare
+    post record;
+    pair text[];
+    base text;
+    candidate text;
+    boundary integer;
+    n integer;
+begin
+    for post in select id, title from posts order by id loop
+        base := lower(post.title);
+        foreach pair slice 1 in array array[
+            ['є', 'ie'], ['ё', 'io'], ['ж', 'zh'], ['ї', 'yi'], ['х', 'kh'], ['ц', 'ts'], ['ч', 'ch'],
+            ['щ', 'shch'], ['ш', 'sh'], ['ю', 'iu'], ['я', 'ia'], ['ґ', 'g-'], ['ъ', '-'], ['ь', '-'],
+            ['æ', 'ae'], ['þ', 'th'], ['ß', 'ss'], ['œ', 'oe']
+        ] loop
+            base := replace(base, pair[1], pair[2]);
+        end loop;
+        base := translate(base,
+            'абвгдезийіклмнопрстуфыэàáâãäåçèéêëìíîïðñòóôõöøùúûüýÿłđšžčćńśźżřůěďťňľĺŕőű',
+            'abvgdeziiiklmnoprstufyeaaaaaaceeeeiiiidnoooooouuuuyyldszccnszzruedtnllrou');
+        base := trim(both '-' from regexp_replace(base, '[^a-z0-9]+', '-', 'g'));
+
+        -- long slugs are cut at the last hyphen within the limit, like util.Slugify does
+        if length(base) > 80 then
+            boundary := position('-' in reverse(left(base, 81)));
+            if boundary > 0 and boundary < 81 then
+                base := left(base, 81 - boundary);
+            else
+                base := left(base, 80);
+            end if;
+        end if;
+        if base = '' then
+            base := 'post';
+        end if;
+
+        candidate := base;
+        n := 2;
+        while exists (select 1 from posts where slug = candidate) loop
+            candidate := base || '-' || n;
+            n := n + 1;
+        end loop;
+        update posts set slug = candidate where id = post.id;
+    end loop;
+end
+$$;
+alter table posts enable trigger update_modified_time;
+
+alter table posts alter column slug set not null;
+
+create table if not exists post_slug_aliases (
+    slug text primary key,
+    post_id integer not null references posts (id) on delete cascade,
+    created_at timestamptz not null default (now())
+);
+
+create index if not exists post_slug_aliases_post_id_idx on post_slug_aliases (post_id);
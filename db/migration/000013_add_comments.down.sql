@@ -0,0 +1,3 @@
+drop table if exists comments;
+
+drop type if exists comment_status;
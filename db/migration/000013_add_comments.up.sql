@@ -0,0 +1,16 @@
+create type comment_status as enum ('pending', 'approved', 'rejected');
+
+create table if not exists comments (
+    id serial primary key,
+    post_id integer not null references posts (id) on delete cascade,
+    parent_id integer,
+    author_name text not null,
+    body text not null,
+    status comment_status not null default 'pending',
+    created_at timestamptz not null default (now()),
+    unique (post_id, id),
+    -- replies stay in the thread of their post and go away with the comment they answer
+    foreign key (post_id, parent_id) references comments (post_id, id) on delete cascade
+);
+
+create index if not exists comments_post_id_parent_id_id_idx on comments (post_id, parent_id, id);
@@ -0,0 +1,5 @@
+drop table if exists post_reaction_counters;
+
+drop table if exists post_reactions;
+
+drop type if exists reaction_type;
@@ -0,0 +1,16 @@
+create type reaction_type as enum ('like', 'love', 'laugh', 'wow', 'sad', 'angry');
+
+create table if not exists post_reactions (
+    post_id integer not null references posts (id) on delete cascade,
+    client_id text not null,
+    type reaction_type not null,
+    created_at timestamptz not null default (now()),
+    primary key (post_id, client_id, type)
+);
+
+create table if not exists post_reaction_counters (
+    post_id integer not null references posts (id) on delete cascade,
+    type reaction_type not null,
+    count integer not null default 0 check (count >= 0),
+    primary key (post_id, type)
+);
@@ -0,0 +1,3 @@
+drop table if exists post_view_buckets;
+
+drop table if exists post_view_counts;
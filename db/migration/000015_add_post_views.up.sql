@@ -0,0 +1,13 @@
+create table if not exists post_view_counts (
+    post_id integer primary key references posts (id) on delete cascade,
+    count bigint not null default 0
+);
+
+create table if not exists post_view_buckets (
+    bucket timestamptz not null,
+    post_id integer not null references posts (id) on delete cascade,
+    count bigint not null default 0,
+    primary key (bucket, post_id)
+);
+
+create index if not exists post_view_buckets_post_id_idx on post_view_buckets (post_id);
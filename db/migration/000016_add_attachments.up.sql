@@ -0,0 +1,11 @@
+create table if not exists attachments (
+    id serial primary key,
+    post_id integer not null references posts (id) on delete cascade,
+    blob_key text not null unique,
+    file_name text not null,
+    content_type text not null,
+    size bigint not null check (size >= 0),
+    created_at timestamptz not null default (now())
+);
+
+create index if not exists attachments_post_id_idx on attachments (post_id, id);
@@ -0,0 +1,3 @@
+drop table if exists post_covers;
+
+drop type if exists cover_status;
@@ -0,0 +1,19 @@
+create type cover_status as enum ('pending', 'processing', 'ready', 'failed');
+
+create table if not exists post_covers (
+    post_id integer primary key references posts (id) on delete cascade,
+    blob_key text not null unique,
+    content_type text not null,
+    width integer not null check (width > 0),
+    height integer not null check (height > 0),
+    status cover_status not null default 'pending',
+    thumbnail_key text,
+    medium_key text,
+    large_key text,
+    claimed_at timestamptz,
+    updated_at timestamptz not null default (now())
+);
+
+-- the processor polls for covers waiting for their variants, oldest first
+create index if not exists post_covers_unprocessed_idx on post_covers (updated_at)
+    where status in ('pending', 'processing');
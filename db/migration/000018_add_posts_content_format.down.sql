@@ -0,0 +1,7 @@
+alter table post_revisions drop column if exists content_format;
+
+alter table posts
+    drop column if exists content_html,
+    drop column if exists content_format;
+
+drop type if exists content_format;
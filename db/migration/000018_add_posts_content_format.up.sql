@@ -0,0 +1,21 @@
+create type content_format as enum ('plain', 'markdown', 'html');
+
+alter table posts
+    add column if not exists content_format content_format not null default 'plain',
+    add column if not exists content_html text not null default '';
+
+alter table post_revisions
+    add column if not exists content_format content_format not null default 'plain';
+
+-- existing posts are plain text, rendered the way the API renders it: escaped, one paragraph per block of lines
+alter table posts disable trigger update_modified_time;
+update posts
+set content_html = coalesce((
+    select string_agg('<p>' || btrim(paragraph, E'\n') || '</p>', E'\n' order by n)
+    from regexp_split_to_table(
+        replace(replace(replace(replace(replace(replace(content, E'\r\n', E'\n'), '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;'),
+        E'\n([ \t]*\n)+'
+    ) with ordinality as paragraphs (paragraph, n)
+    where btrim(paragraph, E' \t\n') <> ''
+), '');
+alter table posts enable trigger update_modified_time;
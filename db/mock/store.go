@@ -0,0 +1,1351 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: db/sqlc/store.go
+
+// Package mockdb is a generated GoMock package.
+package mockdb
+
+import (
+	context "context"
+	sqlc "promova-test-task/db/sqlc"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStore is a mock of Store interface.
+type MockStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMockRecorder
+}
+
+// MockStoreMockRecorder is the mock recorder for MockStore.
+type MockStoreMockRecorder struct {
+	mock *MockStore
+}
+
+// NewMockStore creates a new mock instance.
+func NewMockStore(ctrl *gomock.Controller) *MockStore {
+	mock := &MockStore{ctrl: ctrl}
+	mock.recorder = &MockStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStore) EXPECT() *MockStoreMockRecorder {
+	return m.recorder
+}
+
+// AddPostTags mocks base method.
+func (m *MockStore) AddPostTags(ctx context.Context, arg sqlc.AddPostTagsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPostTags", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPostTags indicates an expected call of AddPostTags.
+func (mr *MockStoreMockRecorder) AddPostTags(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPostTags", reflect.TypeOf((*MockStore)(nil).AddPostTags), ctx, arg)
+}
+
+// AddPostViews mocks base method.
+func (m *MockStore) AddPostViews(ctx context.Context, arg sqlc.AddPostViewsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPostViews", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPostViews indicates an expected call of AddPostViews.
+func (mr *MockStoreMockRecorder) AddPostViews(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPostViews", reflect.TypeOf((*MockStore)(nil).AddPostViews), ctx, arg)
+}
+
+// AddReactionCount mocks base method.
+func (m *MockStore) AddReactionCount(ctx context.Context, arg sqlc.AddReactionCountParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReactionCount", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddReactionCount indicates an expected call of AddReactionCount.
+func (mr *MockStoreMockRecorder) AddReactionCount(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReactionCount", reflect.TypeOf((*MockStore)(nil).AddReactionCount), ctx, arg)
+}
+
+// AddReactionTx mocks base method.
+func (m *MockStore) AddReactionTx(ctx context.Context, arg sqlc.CreatePostReactionParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReactionTx", ctx, arg)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddReactionTx indicates an expected call of AddReactionTx.
+func (mr *MockStoreMockRecorder) AddReactionTx(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReactionTx", reflect.TypeOf((*MockStore)(nil).AddReactionTx), ctx, arg)
+}
+
+// AddTagsToPosts mocks base method.
+func (m *MockStore) AddTagsToPosts(ctx context.Context, arg sqlc.AddTagsToPostsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTagsToPosts", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTagsToPosts indicates an expected call of AddTagsToPosts.
+func (mr *MockStoreMockRecorder) AddTagsToPosts(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTagsToPosts", reflect.TypeOf((*MockStore)(nil).AddTagsToPosts), ctx, arg)
+}
+
+// BatchPostsTx mocks base method.
+func (m *MockStore) BatchPostsTx(ctx context.Context, operations []sqlc.PostOperation, atomic bool) ([]sqlc.PostOperationResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchPostsTx", ctx, operations, atomic)
+	ret0, _ := ret[0].([]sqlc.PostOperationResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchPostsTx indicates an expected call of BatchPostsTx.
+func (mr *MockStoreMockRecorder) BatchPostsTx(ctx, operations, atomic interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchPostsTx", reflect.TypeOf((*MockStore)(nil).BatchPostsTx), ctx, operations, atomic)
+}
+
+// ClaimPostCover mocks base method.
+func (m *MockStore) ClaimPostCover(ctx context.Context, arg sqlc.ClaimPostCoverParams) (sqlc.PostCover, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPostCover", ctx, arg)
+	ret0, _ := ret[0].(sqlc.PostCover)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPostCover indicates an expected call of ClaimPostCover.
+func (mr *MockStoreMockRecorder) ClaimPostCover(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPostCover", reflect.TypeOf((*MockStore)(nil).ClaimPostCover), ctx, arg)
+}
+
+// CompletePostCover mocks base method.
+func (m *MockStore) CompletePostCover(ctx context.Context, arg sqlc.CompletePostCoverParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompletePostCover", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompletePostCover indicates an expected call of CompletePostCover.
+func (mr *MockStoreMockRecorder) CompletePostCover(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompletePostCover", reflect.TypeOf((*MockStore)(nil).CompletePostCover), ctx, arg)
+}
+
+// CreateAttachment mocks base method.
+func (m *MockStore) CreateAttachment(ctx context.Context, arg sqlc.CreateAttachmentParams) (sqlc.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAttachment", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAttachment indicates an expected call of CreateAttachment.
+func (mr *MockStoreMockRecorder) CreateAttachment(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAttachment", reflect.TypeOf((*MockStore)(nil).CreateAttachment), ctx, arg)
+}
+
+// CreateAuthor mocks base method.
+func (m *MockStore) CreateAuthor(ctx context.Context, arg sqlc.CreateAuthorParams) (sqlc.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuthor", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuthor indicates an expected call of CreateAuthor.
+func (mr *MockStoreMockRecorder) CreateAuthor(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthor", reflect.TypeOf((*MockStore)(nil).CreateAuthor), ctx, arg)
+}
+
+// CreateCategory mocks base method.
+func (m *MockStore) CreateCategory(ctx context.Context, arg sqlc.CreateCategoryParams) (sqlc.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCategory", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCategory indicates an expected call of CreateCategory.
+func (mr *MockStoreMockRecorder) CreateCategory(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCategory", reflect.TypeOf((*MockStore)(nil).CreateCategory), ctx, arg)
+}
+
+// CreateComment mocks base method.
+func (m *MockStore) CreateComment(ctx context.Context, arg sqlc.CreateCommentParams) (sqlc.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockStoreMockRecorder) CreateComment(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockStore)(nil).CreateComment), ctx, arg)
+}
+
+// CreatePost mocks base method.
+func (m *MockStore) CreatePost(ctx context.Context, arg sqlc.CreatePostParams) (sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePost", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePost indicates an expected call of CreatePost.
+func (mr *MockStoreMockRecorder) CreatePost(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePost", reflect.TypeOf((*MockStore)(nil).CreatePost), ctx, arg)
+}
+
+// CreatePostReaction mocks base method.
+func (m *MockStore) CreatePostReaction(ctx context.Context, arg sqlc.CreatePostReactionParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePostReaction", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePostReaction indicates an expected call of CreatePostReaction.
+func (mr *MockStoreMockRecorder) CreatePostReaction(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePostReaction", reflect.TypeOf((*MockStore)(nil).CreatePostReaction), ctx, arg)
+}
+
+// CreatePostRevision mocks base method.
+func (m *MockStore) CreatePostRevision(ctx context.Context, arg sqlc.CreatePostRevisionParams) (sqlc.PostRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePostRevision", ctx, arg)
+	ret0, _ := ret[0].(sqlc.PostRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePostRevision indicates an expected call of CreatePostRevision.
+func (mr *MockStoreMockRecorder) CreatePostRevision(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePostRevision", reflect.TypeOf((*MockStore)(nil).CreatePostRevision), ctx, arg)
+}
+
+// CreatePostSlugAlias mocks base method.
+func (m *MockStore) CreatePostSlugAlias(ctx context.Context, arg sqlc.CreatePostSlugAliasParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePostSlugAlias", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePostSlugAlias indicates an expected call of CreatePostSlugAlias.
+func (mr *MockStoreMockRecorder) CreatePostSlugAlias(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePostSlugAlias", reflect.TypeOf((*MockStore)(nil).CreatePostSlugAlias), ctx, arg)
+}
+
+// CreatePostTx mocks base method.
+func (m *MockStore) CreatePostTx(ctx context.Context, arg sqlc.CreatePostTxParams) (sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePostTx", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePostTx indicates an expected call of CreatePostTx.
+func (mr *MockStoreMockRecorder) CreatePostTx(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePostTx", reflect.TypeOf((*MockStore)(nil).CreatePostTx), ctx, arg)
+}
+
+// CreatePosts mocks base method.
+func (m *MockStore) CreatePosts(ctx context.Context, arg sqlc.CreatePostsParams) ([]sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePosts", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePosts indicates an expected call of CreatePosts.
+func (mr *MockStoreMockRecorder) CreatePosts(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePosts", reflect.TypeOf((*MockStore)(nil).CreatePosts), ctx, arg)
+}
+
+// DeleteAttachment mocks base method.
+func (m *MockStore) DeleteAttachment(ctx context.Context, arg sqlc.DeleteAttachmentParams) (sqlc.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAttachment", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAttachment indicates an expected call of DeleteAttachment.
+func (mr *MockStoreMockRecorder) DeleteAttachment(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAttachment", reflect.TypeOf((*MockStore)(nil).DeleteAttachment), ctx, arg)
+}
+
+// DeleteAuthor mocks base method.
+func (m *MockStore) DeleteAuthor(ctx context.Context, id int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuthor", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAuthor indicates an expected call of DeleteAuthor.
+func (mr *MockStoreMockRecorder) DeleteAuthor(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuthor", reflect.TypeOf((*MockStore)(nil).DeleteAuthor), ctx, id)
+}
+
+// DeleteComment mocks base method.
+func (m *MockStore) DeleteComment(ctx context.Context, id int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockStoreMockRecorder) DeleteComment(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockStore)(nil).DeleteComment), ctx, id)
+}
+
+// DeletePost mocks base method.
+func (m *MockStore) DeletePost(ctx context.Context, arg sqlc.DeletePostParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePost", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePost indicates an expected call of DeletePost.
+func (mr *MockStoreMockRecorder) DeletePost(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockStore)(nil).DeletePost), ctx, arg)
+}
+
+// DeletePostReaction mocks base method.
+func (m *MockStore) DeletePostReaction(ctx context.Context, arg sqlc.DeletePostReactionParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePostReaction", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePostReaction indicates an expected call of DeletePostReaction.
+func (mr *MockStoreMockRecorder) DeletePostReaction(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePostReaction", reflect.TypeOf((*MockStore)(nil).DeletePostReaction), ctx, arg)
+}
+
+// DeletePostSlugAlias mocks base method.
+func (m *MockStore) DeletePostSlugAlias(ctx context.Context, arg sqlc.DeletePostSlugAliasParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePostSlugAlias", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePostSlugAlias indicates an expected call of DeletePostSlugAlias.
+func (mr *MockStoreMockRecorder) DeletePostSlugAlias(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePostSlugAlias", reflect.TypeOf((*MockStore)(nil).DeletePostSlugAlias), ctx, arg)
+}
+
+// DeletePostTags mocks base method.
+func (m *MockStore) DeletePostTags(ctx context.Context, postID int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePostTags", ctx, postID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePostTags indicates an expected call of DeletePostTags.
+func (mr *MockStoreMockRecorder) DeletePostTags(ctx, postID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePostTags", reflect.TypeOf((*MockStore)(nil).DeletePostTags), ctx, postID)
+}
+
+// DeletePostTx mocks base method.
+func (m *MockStore) DeletePostTx(ctx context.Context, id int32, check func(sqlc.Post) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePostTx", ctx, id, check)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePostTx indicates an expected call of DeletePostTx.
+func (mr *MockStoreMockRecorder) DeletePostTx(ctx, id, check interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePostTx", reflect.TypeOf((*MockStore)(nil).DeletePostTx), ctx, id, check)
+}
+
+// DeletePostViewBucketsBefore mocks base method.
+func (m *MockStore) DeletePostViewBucketsBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePostViewBucketsBefore", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePostViewBucketsBefore indicates an expected call of DeletePostViewBucketsBefore.
+func (mr *MockStoreMockRecorder) DeletePostViewBucketsBefore(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePostViewBucketsBefore", reflect.TypeOf((*MockStore)(nil).DeletePostViewBucketsBefore), ctx, before)
+}
+
+// ExecTx mocks base method.
+func (m *MockStore) ExecTx(ctx context.Context, fn func(*sqlc.Queries) error, options ...sqlc.TxOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, fn}
+	for _, a := range options {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExecTx", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecTx indicates an expected call of ExecTx.
+func (mr *MockStoreMockRecorder) ExecTx(ctx, fn interface{}, options ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, fn}, options...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecTx", reflect.TypeOf((*MockStore)(nil).ExecTx), varargs...)
+}
+
+// FailPostCover mocks base method.
+func (m *MockStore) FailPostCover(ctx context.Context, arg sqlc.FailPostCoverParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailPostCover", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailPostCover indicates an expected call of FailPostCover.
+func (mr *MockStoreMockRecorder) FailPostCover(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailPostCover", reflect.TypeOf((*MockStore)(nil).FailPostCover), ctx, arg)
+}
+
+// GetAttachmentsByPostIds mocks base method.
+func (m *MockStore) GetAttachmentsByPostIds(ctx context.Context, postIds []int32) ([]sqlc.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttachmentsByPostIds", ctx, postIds)
+	ret0, _ := ret[0].([]sqlc.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttachmentsByPostIds indicates an expected call of GetAttachmentsByPostIds.
+func (mr *MockStoreMockRecorder) GetAttachmentsByPostIds(ctx, postIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttachmentsByPostIds", reflect.TypeOf((*MockStore)(nil).GetAttachmentsByPostIds), ctx, postIds)
+}
+
+// GetAuthorById mocks base method.
+func (m *MockStore) GetAuthorById(ctx context.Context, id int32) (sqlc.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorById", ctx, id)
+	ret0, _ := ret[0].(sqlc.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorById indicates an expected call of GetAuthorById.
+func (mr *MockStoreMockRecorder) GetAuthorById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorById", reflect.TypeOf((*MockStore)(nil).GetAuthorById), ctx, id)
+}
+
+// GetAuthorsByIds mocks base method.
+func (m *MockStore) GetAuthorsByIds(ctx context.Context, ids []int32) ([]sqlc.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorsByIds", ctx, ids)
+	ret0, _ := ret[0].([]sqlc.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorsByIds indicates an expected call of GetAuthorsByIds.
+func (mr *MockStoreMockRecorder) GetAuthorsByIds(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorsByIds", reflect.TypeOf((*MockStore)(nil).GetAuthorsByIds), ctx, ids)
+}
+
+// GetCategoryById mocks base method.
+func (m *MockStore) GetCategoryById(ctx context.Context, id int32) (sqlc.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoryById", ctx, id)
+	ret0, _ := ret[0].(sqlc.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoryById indicates an expected call of GetCategoryById.
+func (mr *MockStoreMockRecorder) GetCategoryById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryById", reflect.TypeOf((*MockStore)(nil).GetCategoryById), ctx, id)
+}
+
+// GetCategoryPaths mocks base method.
+func (m *MockStore) GetCategoryPaths(ctx context.Context, ids []int32) ([]sqlc.GetCategoryPathsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoryPaths", ctx, ids)
+	ret0, _ := ret[0].([]sqlc.GetCategoryPathsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoryPaths indicates an expected call of GetCategoryPaths.
+func (mr *MockStoreMockRecorder) GetCategoryPaths(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryPaths", reflect.TypeOf((*MockStore)(nil).GetCategoryPaths), ctx, ids)
+}
+
+// GetCommentCountsByPostIds mocks base method.
+func (m *MockStore) GetCommentCountsByPostIds(ctx context.Context, postIds []int32) ([]sqlc.GetCommentCountsByPostIdsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommentCountsByPostIds", ctx, postIds)
+	ret0, _ := ret[0].([]sqlc.GetCommentCountsByPostIdsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommentCountsByPostIds indicates an expected call of GetCommentCountsByPostIds.
+func (mr *MockStoreMockRecorder) GetCommentCountsByPostIds(ctx, postIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentCountsByPostIds", reflect.TypeOf((*MockStore)(nil).GetCommentCountsByPostIds), ctx, postIds)
+}
+
+// GetPostById mocks base method.
+func (m *MockStore) GetPostById(ctx context.Context, id int32) (sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostById", ctx, id)
+	ret0, _ := ret[0].(sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostById indicates an expected call of GetPostById.
+func (mr *MockStoreMockRecorder) GetPostById(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostById", reflect.TypeOf((*MockStore)(nil).GetPostById), ctx, id)
+}
+
+// GetPostByIdForUpdate mocks base method.
+func (m *MockStore) GetPostByIdForUpdate(ctx context.Context, id int32) (sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostByIdForUpdate", ctx, id)
+	ret0, _ := ret[0].(sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostByIdForUpdate indicates an expected call of GetPostByIdForUpdate.
+func (mr *MockStoreMockRecorder) GetPostByIdForUpdate(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostByIdForUpdate", reflect.TypeOf((*MockStore)(nil).GetPostByIdForUpdate), ctx, id)
+}
+
+// GetPostBySlug mocks base method.
+func (m *MockStore) GetPostBySlug(ctx context.Context, slug string) (sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostBySlug", ctx, slug)
+	ret0, _ := ret[0].(sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostBySlug indicates an expected call of GetPostBySlug.
+func (mr *MockStoreMockRecorder) GetPostBySlug(ctx, slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostBySlug", reflect.TypeOf((*MockStore)(nil).GetPostBySlug), ctx, slug)
+}
+
+// GetPostBySlugAlias mocks base method.
+func (m *MockStore) GetPostBySlugAlias(ctx context.Context, slug string) (sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostBySlugAlias", ctx, slug)
+	ret0, _ := ret[0].(sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostBySlugAlias indicates an expected call of GetPostBySlugAlias.
+func (mr *MockStoreMockRecorder) GetPostBySlugAlias(ctx, slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostBySlugAlias", reflect.TypeOf((*MockStore)(nil).GetPostBySlugAlias), ctx, slug)
+}
+
+// GetPostCoverForUpdate mocks base method.
+func (m *MockStore) GetPostCoverForUpdate(ctx context.Context, postID int32) (sqlc.PostCover, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostCoverForUpdate", ctx, postID)
+	ret0, _ := ret[0].(sqlc.PostCover)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostCoverForUpdate indicates an expected call of GetPostCoverForUpdate.
+func (mr *MockStoreMockRecorder) GetPostCoverForUpdate(ctx, postID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostCoverForUpdate", reflect.TypeOf((*MockStore)(nil).GetPostCoverForUpdate), ctx, postID)
+}
+
+// GetPostCoversByPostIds mocks base method.
+func (m *MockStore) GetPostCoversByPostIds(ctx context.Context, postIds []int32) ([]sqlc.PostCover, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostCoversByPostIds", ctx, postIds)
+	ret0, _ := ret[0].([]sqlc.PostCover)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostCoversByPostIds indicates an expected call of GetPostCoversByPostIds.
+func (mr *MockStoreMockRecorder) GetPostCoversByPostIds(ctx, postIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostCoversByPostIds", reflect.TypeOf((*MockStore)(nil).GetPostCoversByPostIds), ctx, postIds)
+}
+
+// GetPostRevision mocks base method.
+func (m *MockStore) GetPostRevision(ctx context.Context, arg sqlc.GetPostRevisionParams) (sqlc.PostRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostRevision", ctx, arg)
+	ret0, _ := ret[0].(sqlc.PostRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostRevision indicates an expected call of GetPostRevision.
+func (mr *MockStoreMockRecorder) GetPostRevision(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostRevision", reflect.TypeOf((*MockStore)(nil).GetPostRevision), ctx, arg)
+}
+
+// GetPosts mocks base method.
+func (m *MockStore) GetPosts(ctx context.Context) ([]sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPosts", ctx)
+	ret0, _ := ret[0].([]sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPosts indicates an expected call of GetPosts.
+func (mr *MockStoreMockRecorder) GetPosts(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockStore)(nil).GetPosts), ctx)
+}
+
+// GetReactionCountsByPostIds mocks base method.
+func (m *MockStore) GetReactionCountsByPostIds(ctx context.Context, postIds []int32) ([]sqlc.PostReactionCounter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReactionCountsByPostIds", ctx, postIds)
+	ret0, _ := ret[0].([]sqlc.PostReactionCounter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReactionCountsByPostIds indicates an expected call of GetReactionCountsByPostIds.
+func (mr *MockStoreMockRecorder) GetReactionCountsByPostIds(ctx, postIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReactionCountsByPostIds", reflect.TypeOf((*MockStore)(nil).GetReactionCountsByPostIds), ctx, postIds)
+}
+
+// GetSitemapState mocks base method.
+func (m *MockStore) GetSitemapState(ctx context.Context) (sqlc.GetSitemapStateRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSitemapState", ctx)
+	ret0, _ := ret[0].(sqlc.GetSitemapStateRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSitemapState indicates an expected call of GetSitemapState.
+func (mr *MockStoreMockRecorder) GetSitemapState(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSitemapState", reflect.TypeOf((*MockStore)(nil).GetSitemapState), ctx)
+}
+
+// GetTagsByPostIds mocks base method.
+func (m *MockStore) GetTagsByPostIds(ctx context.Context, postIds []int32) ([]sqlc.GetTagsByPostIdsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagsByPostIds", ctx, postIds)
+	ret0, _ := ret[0].([]sqlc.GetTagsByPostIdsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagsByPostIds indicates an expected call of GetTagsByPostIds.
+func (mr *MockStoreMockRecorder) GetTagsByPostIds(ctx, postIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagsByPostIds", reflect.TypeOf((*MockStore)(nil).GetTagsByPostIds), ctx, postIds)
+}
+
+// GetViewCountsByPostIds mocks base method.
+func (m *MockStore) GetViewCountsByPostIds(ctx context.Context, postIds []int32) ([]sqlc.PostViewCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetViewCountsByPostIds", ctx, postIds)
+	ret0, _ := ret[0].([]sqlc.PostViewCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetViewCountsByPostIds indicates an expected call of GetViewCountsByPostIds.
+func (mr *MockStoreMockRecorder) GetViewCountsByPostIds(ctx, postIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetViewCountsByPostIds", reflect.TypeOf((*MockStore)(nil).GetViewCountsByPostIds), ctx, postIds)
+}
+
+// ImportPostsTx mocks base method.
+func (m *MockStore) ImportPostsTx(ctx context.Context, posts []sqlc.CreatePostTxParams) ([]sqlc.ImportPostResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportPostsTx", ctx, posts)
+	ret0, _ := ret[0].([]sqlc.ImportPostResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportPostsTx indicates an expected call of ImportPostsTx.
+func (mr *MockStoreMockRecorder) ImportPostsTx(ctx, posts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportPostsTx", reflect.TypeOf((*MockStore)(nil).ImportPostsTx), ctx, posts)
+}
+
+// ListAuthors mocks base method.
+func (m *MockStore) ListAuthors(ctx context.Context, arg sqlc.ListAuthorsParams) ([]sqlc.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuthors", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuthors indicates an expected call of ListAuthors.
+func (mr *MockStoreMockRecorder) ListAuthors(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuthors", reflect.TypeOf((*MockStore)(nil).ListAuthors), ctx, arg)
+}
+
+// ListCategories mocks base method.
+func (m *MockStore) ListCategories(ctx context.Context) ([]sqlc.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCategories", ctx)
+	ret0, _ := ret[0].([]sqlc.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCategories indicates an expected call of ListCategories.
+func (mr *MockStoreMockRecorder) ListCategories(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*MockStore)(nil).ListCategories), ctx)
+}
+
+// ListCommentThreads mocks base method.
+func (m *MockStore) ListCommentThreads(ctx context.Context, arg sqlc.ListCommentThreadsParams) ([]sqlc.ListCommentThreadsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCommentThreads", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.ListCommentThreadsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCommentThreads indicates an expected call of ListCommentThreads.
+func (mr *MockStoreMockRecorder) ListCommentThreads(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCommentThreads", reflect.TypeOf((*MockStore)(nil).ListCommentThreads), ctx, arg)
+}
+
+// ListCommentsByStatus mocks base method.
+func (m *MockStore) ListCommentsByStatus(ctx context.Context, arg sqlc.ListCommentsByStatusParams) ([]sqlc.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCommentsByStatus", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCommentsByStatus indicates an expected call of ListCommentsByStatus.
+func (mr *MockStoreMockRecorder) ListCommentsByStatus(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCommentsByStatus", reflect.TypeOf((*MockStore)(nil).ListCommentsByStatus), ctx, arg)
+}
+
+// ListDeletedPosts mocks base method.
+func (m *MockStore) ListDeletedPosts(ctx context.Context, arg sqlc.ListDeletedPostsParams) ([]sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedPosts", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeletedPosts indicates an expected call of ListDeletedPosts.
+func (mr *MockStoreMockRecorder) ListDeletedPosts(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedPosts", reflect.TypeOf((*MockStore)(nil).ListDeletedPosts), ctx, arg)
+}
+
+// ListDuplicatePosts mocks base method.
+func (m *MockStore) ListDuplicatePosts(ctx context.Context, arg sqlc.ListDuplicatePostsParams) ([]int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDuplicatePosts", ctx, arg)
+	ret0, _ := ret[0].([]int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDuplicatePosts indicates an expected call of ListDuplicatePosts.
+func (mr *MockStoreMockRecorder) ListDuplicatePosts(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDuplicatePosts", reflect.TypeOf((*MockStore)(nil).ListDuplicatePosts), ctx, arg)
+}
+
+// ListExportPosts mocks base method.
+func (m *MockStore) ListExportPosts(ctx context.Context, arg sqlc.ListExportPostsParams) ([]sqlc.ListExportPostsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExportPosts", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.ListExportPostsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExportPosts indicates an expected call of ListExportPosts.
+func (mr *MockStoreMockRecorder) ListExportPosts(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExportPosts", reflect.TypeOf((*MockStore)(nil).ListExportPosts), ctx, arg)
+}
+
+// ListPopularPosts mocks base method.
+func (m *MockStore) ListPopularPosts(ctx context.Context, arg sqlc.ListPopularPostsParams) ([]sqlc.ListPopularPostsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPopularPosts", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.ListPopularPostsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPopularPosts indicates an expected call of ListPopularPosts.
+func (mr *MockStoreMockRecorder) ListPopularPosts(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPopularPosts", reflect.TypeOf((*MockStore)(nil).ListPopularPosts), ctx, arg)
+}
+
+// ListPostIDsBySlugs mocks base method.
+func (m *MockStore) ListPostIDsBySlugs(ctx context.Context, slugs []string) ([]sqlc.ListPostIDsBySlugsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPostIDsBySlugs", ctx, slugs)
+	ret0, _ := ret[0].([]sqlc.ListPostIDsBySlugsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPostIDsBySlugs indicates an expected call of ListPostIDsBySlugs.
+func (mr *MockStoreMockRecorder) ListPostIDsBySlugs(ctx, slugs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPostIDsBySlugs", reflect.TypeOf((*MockStore)(nil).ListPostIDsBySlugs), ctx, slugs)
+}
+
+// ListPostRevisions mocks base method.
+func (m *MockStore) ListPostRevisions(ctx context.Context, arg sqlc.ListPostRevisionsParams) ([]sqlc.PostRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPostRevisions", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.PostRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPostRevisions indicates an expected call of ListPostRevisions.
+func (mr *MockStoreMockRecorder) ListPostRevisions(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPostRevisions", reflect.TypeOf((*MockStore)(nil).ListPostRevisions), ctx, arg)
+}
+
+// ListPostsByCreatedAt mocks base method.
+func (m *MockStore) ListPostsByCreatedAt(ctx context.Context, arg sqlc.ListPostsByCreatedAtParams) ([]sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPostsByCreatedAt", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPostsByCreatedAt indicates an expected call of ListPostsByCreatedAt.
+func (mr *MockStoreMockRecorder) ListPostsByCreatedAt(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPostsByCreatedAt", reflect.TypeOf((*MockStore)(nil).ListPostsByCreatedAt), ctx, arg)
+}
+
+// ListPostsByCreatedAtDesc mocks base method.
+func (m *MockStore) ListPostsByCreatedAtDesc(ctx context.Context, arg sqlc.ListPostsByCreatedAtDescParams) ([]sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPostsByCreatedAtDesc", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPostsByCreatedAtDesc indicates an expected call of ListPostsByCreatedAtDesc.
+func (mr *MockStoreMockRecorder) ListPostsByCreatedAtDesc(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPostsByCreatedAtDesc", reflect.TypeOf((*MockStore)(nil).ListPostsByCreatedAtDesc), ctx, arg)
+}
+
+// ListPostsByID mocks base method.
+func (m *MockStore) ListPostsByID(ctx context.Context, arg sqlc.ListPostsByIDParams) ([]sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPostsByID", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPostsByID indicates an expected call of ListPostsByID.
+func (mr *MockStoreMockRecorder) ListPostsByID(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPostsByID", reflect.TypeOf((*MockStore)(nil).ListPostsByID), ctx, arg)
+}
+
+// ListPostsByIDDesc mocks base method.
+func (m *MockStore) ListPostsByIDDesc(ctx context.Context, arg sqlc.ListPostsByIDDescParams) ([]sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPostsByIDDesc", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPostsByIDDesc indicates an expected call of ListPostsByIDDesc.
+func (mr *MockStoreMockRecorder) ListPostsByIDDesc(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPostsByIDDesc", reflect.TypeOf((*MockStore)(nil).ListPostsByIDDesc), ctx, arg)
+}
+
+// ListPostsByLikes mocks base method.
+func (m *MockStore) ListPostsByLikes(ctx context.Context, arg sqlc.ListPostsByLikesParams) ([]sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPostsByLikes", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPostsByLikes indicates an expected call of ListPostsByLikes.
+func (mr *MockStoreMockRecorder) ListPostsByLikes(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPostsByLikes", reflect.TypeOf((*MockStore)(nil).ListPostsByLikes), ctx, arg)
+}
+
+// ListPostsByLikesDesc mocks base method.
+func (m *MockStore) ListPostsByLikesDesc(ctx context.Context, arg sqlc.ListPostsByLikesDescParams) ([]sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPostsByLikesDesc", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPostsByLikesDesc indicates an expected call of ListPostsByLikesDesc.
+func (mr *MockStoreMockRecorder) ListPostsByLikesDesc(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPostsByLikesDesc", reflect.TypeOf((*MockStore)(nil).ListPostsByLikesDesc), ctx, arg)
+}
+
+// ListPostsByTitle mocks base method.
+func (m *MockStore) ListPostsByTitle(ctx context.Context, arg sqlc.ListPostsByTitleParams) ([]sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPostsByTitle", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPostsByTitle indicates an expected call of ListPostsByTitle.
+func (mr *MockStoreMockRecorder) ListPostsByTitle(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPostsByTitle", reflect.TypeOf((*MockStore)(nil).ListPostsByTitle), ctx, arg)
+}
+
+// ListPostsByTitleDesc mocks base method.
+func (m *MockStore) ListPostsByTitleDesc(ctx context.Context, arg sqlc.ListPostsByTitleDescParams) ([]sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPostsByTitleDesc", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPostsByTitleDesc indicates an expected call of ListPostsByTitleDesc.
+func (mr *MockStoreMockRecorder) ListPostsByTitleDesc(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPostsByTitleDesc", reflect.TypeOf((*MockStore)(nil).ListPostsByTitleDesc), ctx, arg)
+}
+
+// ListPostsByUpdatedAt mocks base method.
+func (m *MockStore) ListPostsByUpdatedAt(ctx context.Context, arg sqlc.ListPostsByUpdatedAtParams) ([]sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPostsByUpdatedAt", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPostsByUpdatedAt indicates an expected call of ListPostsByUpdatedAt.
+func (mr *MockStoreMockRecorder) ListPostsByUpdatedAt(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPostsByUpdatedAt", reflect.TypeOf((*MockStore)(nil).ListPostsByUpdatedAt), ctx, arg)
+}
+
+// ListPostsByUpdatedAtDesc mocks base method.
+func (m *MockStore) ListPostsByUpdatedAtDesc(ctx context.Context, arg sqlc.ListPostsByUpdatedAtDescParams) ([]sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPostsByUpdatedAtDesc", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPostsByUpdatedAtDesc indicates an expected call of ListPostsByUpdatedAtDesc.
+func (mr *MockStoreMockRecorder) ListPostsByUpdatedAtDesc(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPostsByUpdatedAtDesc", reflect.TypeOf((*MockStore)(nil).ListPostsByUpdatedAtDesc), ctx, arg)
+}
+
+// ListSitemapPosts mocks base method.
+func (m *MockStore) ListSitemapPosts(ctx context.Context, arg sqlc.ListSitemapPostsParams) ([]sqlc.ListSitemapPostsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSitemapPosts", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.ListSitemapPostsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSitemapPosts indicates an expected call of ListSitemapPosts.
+func (mr *MockStoreMockRecorder) ListSitemapPosts(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSitemapPosts", reflect.TypeOf((*MockStore)(nil).ListSitemapPosts), ctx, arg)
+}
+
+// ListSitemapShards mocks base method.
+func (m *MockStore) ListSitemapShards(ctx context.Context, shardSize int64) ([]sqlc.ListSitemapShardsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSitemapShards", ctx, shardSize)
+	ret0, _ := ret[0].([]sqlc.ListSitemapShardsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSitemapShards indicates an expected call of ListSitemapShards.
+func (mr *MockStoreMockRecorder) ListSitemapShards(ctx, shardSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSitemapShards", reflect.TypeOf((*MockStore)(nil).ListSitemapShards), ctx, shardSize)
+}
+
+// ListTags mocks base method.
+func (m *MockStore) ListTags(ctx context.Context, arg sqlc.ListTagsParams) ([]sqlc.ListTagsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTags", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.ListTagsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTags indicates an expected call of ListTags.
+func (mr *MockStoreMockRecorder) ListTags(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockStore)(nil).ListTags), ctx, arg)
+}
+
+// ListTakenSlugs mocks base method.
+func (m *MockStore) ListTakenSlugs(ctx context.Context, arg sqlc.ListTakenSlugsParams) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTakenSlugs", ctx, arg)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTakenSlugs indicates an expected call of ListTakenSlugs.
+func (mr *MockStoreMockRecorder) ListTakenSlugs(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTakenSlugs", reflect.TypeOf((*MockStore)(nil).ListTakenSlugs), ctx, arg)
+}
+
+// LockSlug mocks base method.
+func (m *MockStore) LockSlug(ctx context.Context, slug string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockSlug", ctx, slug)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockSlug indicates an expected call of LockSlug.
+func (mr *MockStoreMockRecorder) LockSlug(ctx, slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockSlug", reflect.TypeOf((*MockStore)(nil).LockSlug), ctx, slug)
+}
+
+// PublishDuePosts mocks base method.
+func (m *MockStore) PublishDuePosts(ctx context.Context, arg sqlc.PublishDuePostsParams) ([]sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishDuePosts", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishDuePosts indicates an expected call of PublishDuePosts.
+func (mr *MockStoreMockRecorder) PublishDuePosts(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDuePosts", reflect.TypeOf((*MockStore)(nil).PublishDuePosts), ctx, arg)
+}
+
+// PurgePost mocks base method.
+func (m *MockStore) PurgePost(ctx context.Context, id int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgePost", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgePost indicates an expected call of PurgePost.
+func (mr *MockStoreMockRecorder) PurgePost(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgePost", reflect.TypeOf((*MockStore)(nil).PurgePost), ctx, id)
+}
+
+// RemoveReactionTx mocks base method.
+func (m *MockStore) RemoveReactionTx(ctx context.Context, arg sqlc.DeletePostReactionParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReactionTx", ctx, arg)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveReactionTx indicates an expected call of RemoveReactionTx.
+func (mr *MockStoreMockRecorder) RemoveReactionTx(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReactionTx", reflect.TypeOf((*MockStore)(nil).RemoveReactionTx), ctx, arg)
+}
+
+// ReplacePostCoverTx mocks base method.
+func (m *MockStore) ReplacePostCoverTx(ctx context.Context, arg sqlc.UpsertPostCoverParams) (sqlc.PostCover, *sqlc.PostCover, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplacePostCoverTx", ctx, arg)
+	ret0, _ := ret[0].(sqlc.PostCover)
+	ret1, _ := ret[1].(*sqlc.PostCover)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReplacePostCoverTx indicates an expected call of ReplacePostCoverTx.
+func (mr *MockStoreMockRecorder) ReplacePostCoverTx(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplacePostCoverTx", reflect.TypeOf((*MockStore)(nil).ReplacePostCoverTx), ctx, arg)
+}
+
+// RestorePost mocks base method.
+func (m *MockStore) RestorePost(ctx context.Context, id int32) (sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestorePost", ctx, id)
+	ret0, _ := ret[0].(sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestorePost indicates an expected call of RestorePost.
+func (mr *MockStoreMockRecorder) RestorePost(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestorePost", reflect.TypeOf((*MockStore)(nil).RestorePost), ctx, id)
+}
+
+// SearchPosts mocks base method.
+func (m *MockStore) SearchPosts(ctx context.Context, arg sqlc.SearchPostsParams) ([]sqlc.SearchPostsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPosts", ctx, arg)
+	ret0, _ := ret[0].([]sqlc.SearchPostsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchPosts indicates an expected call of SearchPosts.
+func (mr *MockStoreMockRecorder) SearchPosts(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPosts", reflect.TypeOf((*MockStore)(nil).SearchPosts), ctx, arg)
+}
+
+// StreamExportPosts mocks base method.
+func (m *MockStore) StreamExportPosts(ctx context.Context, arg sqlc.ListExportPostsParams, fn func(sqlc.ListExportPostsRow) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamExportPosts", ctx, arg, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamExportPosts indicates an expected call of StreamExportPosts.
+func (mr *MockStoreMockRecorder) StreamExportPosts(ctx, arg, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamExportPosts", reflect.TypeOf((*MockStore)(nil).StreamExportPosts), ctx, arg, fn)
+}
+
+// StreamSitemapPosts mocks base method.
+func (m *MockStore) StreamSitemapPosts(ctx context.Context, arg sqlc.ListSitemapPostsParams, fn func(sqlc.ListSitemapPostsRow) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamSitemapPosts", ctx, arg, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamSitemapPosts indicates an expected call of StreamSitemapPosts.
+func (mr *MockStoreMockRecorder) StreamSitemapPosts(ctx, arg, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamSitemapPosts", reflect.TypeOf((*MockStore)(nil).StreamSitemapPosts), ctx, arg, fn)
+}
+
+// UpdateAuthor mocks base method.
+func (m *MockStore) UpdateAuthor(ctx context.Context, arg sqlc.UpdateAuthorParams) (sqlc.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAuthor", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAuthor indicates an expected call of UpdateAuthor.
+func (mr *MockStoreMockRecorder) UpdateAuthor(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthor", reflect.TypeOf((*MockStore)(nil).UpdateAuthor), ctx, arg)
+}
+
+// UpdateCommentStatus mocks base method.
+func (m *MockStore) UpdateCommentStatus(ctx context.Context, arg sqlc.UpdateCommentStatusParams) (sqlc.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCommentStatus", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCommentStatus indicates an expected call of UpdateCommentStatus.
+func (mr *MockStoreMockRecorder) UpdateCommentStatus(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCommentStatus", reflect.TypeOf((*MockStore)(nil).UpdateCommentStatus), ctx, arg)
+}
+
+// UpdatePostById mocks base method.
+func (m *MockStore) UpdatePostById(ctx context.Context, arg sqlc.UpdatePostByIdParams) (sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePostById", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePostById indicates an expected call of UpdatePostById.
+func (mr *MockStoreMockRecorder) UpdatePostById(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePostById", reflect.TypeOf((*MockStore)(nil).UpdatePostById), ctx, arg)
+}
+
+// UpdatePostStatus mocks base method.
+func (m *MockStore) UpdatePostStatus(ctx context.Context, arg sqlc.UpdatePostStatusParams) (sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePostStatus", ctx, arg)
+	ret0, _ := ret[0].(sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePostStatus indicates an expected call of UpdatePostStatus.
+func (mr *MockStoreMockRecorder) UpdatePostStatus(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePostStatus", reflect.TypeOf((*MockStore)(nil).UpdatePostStatus), ctx, arg)
+}
+
+// UpdatePostTx mocks base method.
+func (m *MockStore) UpdatePostTx(ctx context.Context, id int32, update func(sqlc.Post) (sqlc.UpdatePostTxParams, error)) (sqlc.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePostTx", ctx, id, update)
+	ret0, _ := ret[0].(sqlc.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePostTx indicates an expected call of UpdatePostTx.
+func (mr *MockStoreMockRecorder) UpdatePostTx(ctx, id, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePostTx", reflect.TypeOf((*MockStore)(nil).UpdatePostTx), ctx, id, update)
+}
+
+// UpsertPostCover mocks base method.
+func (m *MockStore) UpsertPostCover(ctx context.Context, arg sqlc.UpsertPostCoverParams) (sqlc.PostCover, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertPostCover", ctx, arg)
+	ret0, _ := ret[0].(sqlc.PostCover)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertPostCover indicates an expected call of UpsertPostCover.
+func (mr *MockStoreMockRecorder) UpsertPostCover(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertPostCover", reflect.TypeOf((*MockStore)(nil).UpsertPostCover), ctx, arg)
+}
+
+// UpsertTags mocks base method.
+func (m *MockStore) UpsertTags(ctx context.Context, names []string) ([]sqlc.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTags", ctx, names)
+	ret0, _ := ret[0].([]sqlc.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTags indicates an expected call of UpsertTags.
+func (mr *MockStoreMockRecorder) UpsertTags(ctx, names interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTags", reflect.TypeOf((*MockStore)(nil).UpsertTags), ctx, names)
+}
@@ -0,0 +1,14 @@
+-- name: CreateAttachment :one
+INSERT INTO attachments (post_id, blob_key, file_name, content_type, size)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: DeleteAttachment :one
+DELETE FROM attachments
+WHERE id = $1 AND post_id = $2
+RETURNING *;
+
+-- name: GetAttachmentsByPostIds :many
+SELECT * FROM attachments
+WHERE post_id = ANY(sqlc.arg(post_ids)::int[])
+ORDER BY post_id, id;
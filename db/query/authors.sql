@@ -0,0 +1,34 @@
+-- name: CreateAuthor :one
+INSERT INTO authors (
+    name, email, bio
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetAuthorById :one
+SELECT * FROM authors
+WHERE id = $1;
+
+-- name: GetAuthorsByIds :many
+SELECT * FROM authors
+WHERE id = ANY(sqlc.arg(ids)::int[])
+ORDER BY id;
+
+-- name: ListAuthors :many
+SELECT * FROM authors
+ORDER BY id
+LIMIT sqlc.arg(page_size)
+OFFSET sqlc.arg(page_offset);
+
+-- name: UpdateAuthor :one
+UPDATE authors
+SET name = sqlc.arg(name),
+    email = sqlc.arg(email),
+    bio = sqlc.arg(bio),
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteAuthor :execrows
+DELETE FROM authors
+WHERE id = $1;
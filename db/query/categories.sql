@@ -0,0 +1,28 @@
+-- name: CreateCategory :one
+INSERT INTO categories (
+    parent_id, name
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: ListCategories :many
+SELECT * FROM categories
+ORDER BY name, id;
+
+-- name: GetCategoryPaths :many
+WITH RECURSIVE path AS (
+    SELECT categories.id AS category_id, categories.id, categories.parent_id, categories.name, 0 AS depth
+    FROM categories
+    WHERE categories.id = ANY(sqlc.arg(ids)::int[])
+  UNION ALL
+    SELECT path.category_id, parent.id, parent.parent_id, parent.name, path.depth + 1
+    FROM categories parent
+    JOIN path ON parent.id = path.parent_id
+)
+SELECT path.category_id::integer AS category_id, path.id::integer AS id, path.name::text AS name
+FROM path
+ORDER BY path.category_id, path.depth DESC;
+
+-- name: GetCategoryById :one
+SELECT * FROM categories
+WHERE id = $1;
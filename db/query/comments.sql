@@ -0,0 +1,57 @@
+-- name: CreateComment :one
+INSERT INTO comments (
+    post_id, parent_id, author_name, body
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetCommentCountsByPostIds :many
+WITH RECURSIVE visible AS (
+    SELECT comments.id, comments.post_id FROM comments
+    WHERE comments.post_id = ANY(sqlc.arg(post_ids)::int[]) AND comments.parent_id IS NULL
+      AND comments.status = 'approved'
+  UNION ALL
+    SELECT comments.id, comments.post_id
+    FROM comments JOIN visible ON comments.parent_id = visible.id
+    WHERE comments.status = 'approved'
+)
+SELECT post_id, count(*) AS comment_count
+FROM visible
+GROUP BY post_id;
+
+-- name: ListCommentThreads :many
+WITH RECURSIVE roots AS (
+    SELECT comments.id FROM comments
+    WHERE comments.post_id = sqlc.arg(post_id) AND comments.parent_id IS NULL
+      AND (sqlc.narg(status)::comment_status IS NULL OR comments.status = sqlc.narg(status))
+    ORDER BY comments.id
+    LIMIT sqlc.arg(page_size)
+    OFFSET sqlc.arg(page_offset)
+), thread AS (
+    SELECT comments.*, 0 AS depth, ARRAY[comments.id] AS path
+    FROM comments JOIN roots ON roots.id = comments.id
+  UNION ALL
+    SELECT comments.*, thread.depth + 1, thread.path || comments.id
+    FROM comments JOIN thread ON comments.parent_id = thread.id
+    WHERE sqlc.narg(status)::comment_status IS NULL OR comments.status = sqlc.narg(status)
+)
+SELECT id, post_id, parent_id, author_name, body, status, created_at, depth
+FROM thread
+ORDER BY path;
+
+-- name: ListCommentsByStatus :many
+SELECT * FROM comments
+WHERE post_id = sqlc.arg(post_id) AND status = sqlc.arg(status)
+ORDER BY id
+LIMIT sqlc.arg(page_size)
+OFFSET sqlc.arg(page_offset);
+
+-- name: UpdateCommentStatus :one
+UPDATE comments
+SET status = sqlc.arg(status)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteComment :execrows
+DELETE FROM comments
+WHERE id = $1;
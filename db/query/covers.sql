@@ -0,0 +1,41 @@
+-- name: ClaimPostCover :one
+UPDATE post_covers
+SET status = 'processing', claimed_at = sqlc.arg(now)::timestamptz, updated_at = sqlc.arg(now)::timestamptz
+WHERE post_id = (
+    SELECT post_id FROM post_covers
+    WHERE status = 'pending' OR (status = 'processing' AND claimed_at < sqlc.arg(stale_before)::timestamptz)
+    ORDER BY updated_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompletePostCover :execrows
+UPDATE post_covers
+SET status = 'ready', width = $3, height = $4, thumbnail_key = $5, medium_key = $6, large_key = $7,
+    claimed_at = NULL, updated_at = now()
+WHERE post_id = $1 AND blob_key = $2 AND status = 'processing';
+
+-- name: FailPostCover :execrows
+UPDATE post_covers
+SET status = 'failed', claimed_at = NULL, updated_at = now()
+WHERE post_id = $1 AND blob_key = $2 AND status = 'processing';
+
+-- name: GetPostCoverForUpdate :one
+SELECT * FROM post_covers
+WHERE post_id = $1
+FOR UPDATE;
+
+-- name: GetPostCoversByPostIds :many
+SELECT * FROM post_covers
+WHERE post_id = ANY(sqlc.arg(post_ids)::int[])
+ORDER BY post_id;
+
+-- name: UpsertPostCover :one
+INSERT INTO post_covers (post_id, blob_key, content_type, width, height)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (post_id) DO UPDATE
+SET blob_key = excluded.blob_key, content_type = excluded.content_type, width = excluded.width,
+    height = excluded.height, status = 'pending', thumbnail_key = NULL, medium_key = NULL, large_key = NULL,
+    claimed_at = NULL, updated_at = now()
+RETURNING *;
@@ -0,0 +1,26 @@
+-- name: ListExportPosts :many
+SELECT posts.id, posts.slug, posts.title, posts.content, posts.content_format, posts.status,
+    posts.author_id, posts.category_id, posts.created_at, posts.updated_at, posts.published_at, posts.version,
+    coalesce((
+        SELECT array_agg(tags.name ORDER BY tags.name)
+        FROM post_tags
+        JOIN tags ON tags.id = post_tags.tag_id
+        WHERE post_tags.post_id = posts.id
+    ), '{}')::text[] AS tags
+FROM posts
+WHERE (sqlc.narg(created_after)::timestamptz IS NULL OR created_at > sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+  AND (sqlc.narg(updated_since)::timestamptz IS NULL OR updated_at >= sqlc.narg(updated_since))
+  AND (sqlc.narg(title_contains)::text IS NULL OR title ILIKE '%' || sqlc.narg(title_contains) || '%')
+  AND status = sqlc.arg(status)
+  AND (
+    coalesce(cardinality(sqlc.arg(tags)::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY(sqlc.arg(tags))
+    ) >= CASE WHEN sqlc.arg(tag_match)::text = 'all' THEN cardinality(sqlc.arg(tags)) ELSE 1 END
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+ORDER BY posts.id;
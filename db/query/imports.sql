@@ -0,0 +1,10 @@
+-- name: ListDuplicatePosts :many
+SELECT DISTINCT new_posts.position::int AS position
+FROM unnest(sqlc.arg(titles)::text[], sqlc.arg(contents)::text[]) WITH ORDINALITY AS new_posts(title, content, position)
+JOIN posts ON posts.title = new_posts.title AND posts.content = new_posts.content
+WHERE posts.deleted_at IS NULL
+ORDER BY position;
+
+-- name: ListPostIDsBySlugs :many
+SELECT id, slug FROM posts
+WHERE slug = ANY(sqlc.arg(slugs)::text[]);
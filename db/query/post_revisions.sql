@@ -0,0 +1,19 @@
+-- name: CreatePostRevision :one
+INSERT INTO post_revisions (post_id, revision, title, content, content_format)
+SELECT sqlc.arg(post_id)::integer, coalesce(max(revision), 0) + 1, sqlc.arg(title)::text, sqlc.arg(content)::text,
+    sqlc.arg(content_format)::content_format
+FROM post_revisions
+WHERE post_id = sqlc.arg(post_id)
+RETURNING *;
+
+-- name: GetPostRevision :one
+SELECT post_revisions.* FROM post_revisions
+JOIN posts ON posts.id = post_revisions.post_id
+WHERE post_revisions.post_id = $1 AND post_revisions.revision = $2 AND posts.deleted_at IS NULL;
+
+-- name: ListPostRevisions :many
+SELECT * FROM post_revisions
+WHERE post_id = sqlc.arg(post_id)
+ORDER BY revision DESC
+LIMIT sqlc.arg(page_size)
+OFFSET sqlc.arg(page_offset);
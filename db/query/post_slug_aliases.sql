@@ -0,0 +1,22 @@
+-- name: CreatePostSlugAlias :exec
+INSERT INTO post_slug_aliases (slug, post_id)
+VALUES ($1, $2);
+
+-- name: DeletePostSlugAlias :exec
+DELETE FROM post_slug_aliases
+WHERE slug = $1 AND post_id = $2;
+
+-- name: GetPostBySlugAlias :one
+SELECT posts.* FROM post_slug_aliases
+JOIN posts ON posts.id = post_slug_aliases.post_id
+WHERE post_slug_aliases.slug = $1 AND posts.deleted_at IS NULL;
+
+-- name: ListTakenSlugs :many
+SELECT slug FROM posts
+WHERE (slug = sqlc.arg(base)::text OR slug LIKE sqlc.arg(base) || '-%') AND id <> sqlc.arg(post_id)::integer
+UNION
+SELECT slug FROM post_slug_aliases
+WHERE (slug = sqlc.arg(base) OR slug LIKE sqlc.arg(base) || '-%') AND post_id <> sqlc.arg(post_id);
+
+-- name: LockSlug :exec
+SELECT pg_advisory_xact_lock(hashtext(sqlc.arg(slug)::text));
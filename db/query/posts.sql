@@ -1,24 +1,442 @@
 -- name: CreatePost :one
 INSERT INTO posts (
-    title, content
+    title, content, publish_at, author_id, category_id, slug, content_format, content_html
 ) VALUES (
-    $1, $2
+    $1, $2, $3, $4, $5, $6, $7, $8
 ) RETURNING *;
 
+-- name: CreatePosts :many
+INSERT INTO posts (
+    title, content, publish_at, author_id, category_id, slug, content_format, content_html
+)
+SELECT unnest(sqlc.arg(titles)::text[]),
+       unnest(sqlc.arg(contents)::text[]),
+       unnest(sqlc.arg(publish_ats)::timestamptz[]),
+       unnest(sqlc.arg(author_ids)::int[]),
+       unnest(sqlc.arg(category_ids)::int[]),
+       unnest(sqlc.arg(slugs)::text[]),
+       unnest(sqlc.arg(content_formats)::content_format[]),
+       unnest(sqlc.arg(content_htmls)::text[])
+RETURNING *;
+
 -- name: GetPostById :one
 SELECT * FROM posts
-WHERE id = $1;
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetPostBySlug :one
+SELECT * FROM posts
+WHERE slug = $1 AND deleted_at IS NULL;
+
+-- name: GetPostByIdForUpdate :one
+SELECT * FROM posts
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE;
 
 -- name: GetPosts :many
 SELECT * FROM posts
+WHERE deleted_at IS NULL
 ORDER BY id;
 
 -- name: UpdatePostById :one
 UPDATE posts
-SET title = $1, content = $2
-WHERE id = $3
+SET title = sqlc.arg(title),
+    content = sqlc.arg(content),
+    content_format = sqlc.arg(content_format),
+    content_html = sqlc.arg(content_html),
+    publish_at = sqlc.narg(publish_at),
+    category_id = sqlc.narg(category_id),
+    slug = coalesce(sqlc.narg(slug), slug)
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+  AND (sqlc.narg(expected_version)::integer IS NULL OR version = sqlc.narg(expected_version))
 RETURNING *;
 
--- name: DeletePost :exec
+-- name: DeletePost :execrows
+UPDATE posts
+SET deleted_at = now()
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+  AND (sqlc.narg(expected_version)::integer IS NULL OR version = sqlc.narg(expected_version));
+
+-- name: ListDeletedPosts :many
+SELECT * FROM posts
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id DESC
+LIMIT sqlc.arg(page_size)
+OFFSET sqlc.arg(page_offset);
+
+-- name: RestorePost :one
+UPDATE posts
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgePost :execrows
 DELETE FROM posts
 WHERE id = $1;
+
+-- name: ListPostsByID :many
+SELECT posts.* FROM posts
+WHERE (sqlc.narg(created_after)::timestamptz IS NULL OR created_at > sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+  AND (sqlc.narg(updated_since)::timestamptz IS NULL OR updated_at >= sqlc.narg(updated_since))
+  AND (sqlc.narg(title_contains)::text IS NULL OR title ILIKE '%' || sqlc.narg(title_contains) || '%')
+  AND status = sqlc.arg(status)
+  AND (sqlc.narg(author_id)::int IS NULL OR author_id = sqlc.narg(author_id))
+  AND (
+    coalesce(cardinality(sqlc.arg(tags)::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY(sqlc.arg(tags))
+    ) >= CASE WHEN sqlc.arg(tag_match)::text = 'all' THEN cardinality(sqlc.arg(tags)) ELSE 1 END
+  )
+  AND (
+    sqlc.narg(category_id)::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = sqlc.narg(category_id)
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND (sqlc.narg(cursor_id)::int IS NULL OR id > sqlc.narg(cursor_id))
+ORDER BY id
+LIMIT sqlc.arg(page_size);
+
+-- name: ListPostsByIDDesc :many
+SELECT posts.* FROM posts
+WHERE (sqlc.narg(created_after)::timestamptz IS NULL OR created_at > sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+  AND (sqlc.narg(updated_since)::timestamptz IS NULL OR updated_at >= sqlc.narg(updated_since))
+  AND (sqlc.narg(title_contains)::text IS NULL OR title ILIKE '%' || sqlc.narg(title_contains) || '%')
+  AND status = sqlc.arg(status)
+  AND (sqlc.narg(author_id)::int IS NULL OR author_id = sqlc.narg(author_id))
+  AND (
+    coalesce(cardinality(sqlc.arg(tags)::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY(sqlc.arg(tags))
+    ) >= CASE WHEN sqlc.arg(tag_match)::text = 'all' THEN cardinality(sqlc.arg(tags)) ELSE 1 END
+  )
+  AND (
+    sqlc.narg(category_id)::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = sqlc.narg(category_id)
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND (sqlc.narg(cursor_id)::int IS NULL OR id < sqlc.narg(cursor_id))
+ORDER BY id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: ListPostsByCreatedAt :many
+SELECT posts.* FROM posts
+WHERE (sqlc.narg(created_after)::timestamptz IS NULL OR created_at > sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+  AND (sqlc.narg(updated_since)::timestamptz IS NULL OR updated_at >= sqlc.narg(updated_since))
+  AND (sqlc.narg(title_contains)::text IS NULL OR title ILIKE '%' || sqlc.narg(title_contains) || '%')
+  AND status = sqlc.arg(status)
+  AND (sqlc.narg(author_id)::int IS NULL OR author_id = sqlc.narg(author_id))
+  AND (
+    coalesce(cardinality(sqlc.arg(tags)::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY(sqlc.arg(tags))
+    ) >= CASE WHEN sqlc.arg(tag_match)::text = 'all' THEN cardinality(sqlc.arg(tags)) ELSE 1 END
+  )
+  AND (
+    sqlc.narg(category_id)::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = sqlc.narg(category_id)
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND (sqlc.narg(cursor_id)::int IS NULL OR (created_at, id) > (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)))
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_size);
+
+-- name: ListPostsByCreatedAtDesc :many
+SELECT posts.* FROM posts
+WHERE (sqlc.narg(created_after)::timestamptz IS NULL OR created_at > sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+  AND (sqlc.narg(updated_since)::timestamptz IS NULL OR updated_at >= sqlc.narg(updated_since))
+  AND (sqlc.narg(title_contains)::text IS NULL OR title ILIKE '%' || sqlc.narg(title_contains) || '%')
+  AND status = sqlc.arg(status)
+  AND (sqlc.narg(author_id)::int IS NULL OR author_id = sqlc.narg(author_id))
+  AND (
+    coalesce(cardinality(sqlc.arg(tags)::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY(sqlc.arg(tags))
+    ) >= CASE WHEN sqlc.arg(tag_match)::text = 'all' THEN cardinality(sqlc.arg(tags)) ELSE 1 END
+  )
+  AND (
+    sqlc.narg(category_id)::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = sqlc.narg(category_id)
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND (sqlc.narg(cursor_id)::int IS NULL OR (created_at, id) < (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: ListPostsByUpdatedAt :many
+SELECT posts.* FROM posts
+WHERE (sqlc.narg(created_after)::timestamptz IS NULL OR created_at > sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+  AND (sqlc.narg(updated_since)::timestamptz IS NULL OR updated_at >= sqlc.narg(updated_since))
+  AND (sqlc.narg(title_contains)::text IS NULL OR title ILIKE '%' || sqlc.narg(title_contains) || '%')
+  AND status = sqlc.arg(status)
+  AND (sqlc.narg(author_id)::int IS NULL OR author_id = sqlc.narg(author_id))
+  AND (
+    coalesce(cardinality(sqlc.arg(tags)::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY(sqlc.arg(tags))
+    ) >= CASE WHEN sqlc.arg(tag_match)::text = 'all' THEN cardinality(sqlc.arg(tags)) ELSE 1 END
+  )
+  AND (
+    sqlc.narg(category_id)::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = sqlc.narg(category_id)
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND (sqlc.narg(cursor_id)::int IS NULL OR (updated_at, id) > (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)))
+ORDER BY updated_at, id
+LIMIT sqlc.arg(page_size);
+
+-- name: ListPostsByUpdatedAtDesc :many
+SELECT posts.* FROM posts
+WHERE (sqlc.narg(created_after)::timestamptz IS NULL OR created_at > sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+  AND (sqlc.narg(updated_since)::timestamptz IS NULL OR updated_at >= sqlc.narg(updated_since))
+  AND (sqlc.narg(title_contains)::text IS NULL OR title ILIKE '%' || sqlc.narg(title_contains) || '%')
+  AND status = sqlc.arg(status)
+  AND (sqlc.narg(author_id)::int IS NULL OR author_id = sqlc.narg(author_id))
+  AND (
+    coalesce(cardinality(sqlc.arg(tags)::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY(sqlc.arg(tags))
+    ) >= CASE WHEN sqlc.arg(tag_match)::text = 'all' THEN cardinality(sqlc.arg(tags)) ELSE 1 END
+  )
+  AND (
+    sqlc.narg(category_id)::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = sqlc.narg(category_id)
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND (sqlc.narg(cursor_id)::int IS NULL OR (updated_at, id) < (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)))
+ORDER BY updated_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: ListPostsByTitle :many
+SELECT posts.* FROM posts
+WHERE (sqlc.narg(created_after)::timestamptz IS NULL OR created_at > sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+  AND (sqlc.narg(updated_since)::timestamptz IS NULL OR updated_at >= sqlc.narg(updated_since))
+  AND (sqlc.narg(title_contains)::text IS NULL OR title ILIKE '%' || sqlc.narg(title_contains) || '%')
+  AND status = sqlc.arg(status)
+  AND (sqlc.narg(author_id)::int IS NULL OR author_id = sqlc.narg(author_id))
+  AND (
+    coalesce(cardinality(sqlc.arg(tags)::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY(sqlc.arg(tags))
+    ) >= CASE WHEN sqlc.arg(tag_match)::text = 'all' THEN cardinality(sqlc.arg(tags)) ELSE 1 END
+  )
+  AND (
+    sqlc.narg(category_id)::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = sqlc.narg(category_id)
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND (sqlc.narg(cursor_id)::int IS NULL OR (title, id) > (sqlc.narg(cursor_title)::text, sqlc.narg(cursor_id)))
+ORDER BY title, id
+LIMIT sqlc.arg(page_size);
+
+-- name: ListPostsByTitleDesc :many
+SELECT posts.* FROM posts
+WHERE (sqlc.narg(created_after)::timestamptz IS NULL OR created_at > sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+  AND (sqlc.narg(updated_since)::timestamptz IS NULL OR updated_at >= sqlc.narg(updated_since))
+  AND (sqlc.narg(title_contains)::text IS NULL OR title ILIKE '%' || sqlc.narg(title_contains) || '%')
+  AND status = sqlc.arg(status)
+  AND (sqlc.narg(author_id)::int IS NULL OR author_id = sqlc.narg(author_id))
+  AND (
+    coalesce(cardinality(sqlc.arg(tags)::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY(sqlc.arg(tags))
+    ) >= CASE WHEN sqlc.arg(tag_match)::text = 'all' THEN cardinality(sqlc.arg(tags)) ELSE 1 END
+  )
+  AND (
+    sqlc.narg(category_id)::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = sqlc.narg(category_id)
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND (sqlc.narg(cursor_id)::int IS NULL OR (title, id) < (sqlc.narg(cursor_title)::text, sqlc.narg(cursor_id)))
+ORDER BY title DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: ListPostsByLikes :many
+SELECT posts.* FROM posts
+LEFT JOIN post_reaction_counters likes ON likes.post_id = posts.id AND likes.type = 'like'
+WHERE (sqlc.narg(created_after)::timestamptz IS NULL OR created_at > sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+  AND (sqlc.narg(updated_since)::timestamptz IS NULL OR updated_at >= sqlc.narg(updated_since))
+  AND (sqlc.narg(title_contains)::text IS NULL OR title ILIKE '%' || sqlc.narg(title_contains) || '%')
+  AND status = sqlc.arg(status)
+  AND (sqlc.narg(author_id)::int IS NULL OR author_id = sqlc.narg(author_id))
+  AND (
+    coalesce(cardinality(sqlc.arg(tags)::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY(sqlc.arg(tags))
+    ) >= CASE WHEN sqlc.arg(tag_match)::text = 'all' THEN cardinality(sqlc.arg(tags)) ELSE 1 END
+  )
+  AND (
+    sqlc.narg(category_id)::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = sqlc.narg(category_id)
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND (sqlc.narg(cursor_id)::int IS NULL OR (coalesce(likes.count, 0), id) > (sqlc.narg(cursor_likes)::int, sqlc.narg(cursor_id)))
+ORDER BY coalesce(likes.count, 0), id
+LIMIT sqlc.arg(page_size);
+
+-- name: ListPostsByLikesDesc :many
+SELECT posts.* FROM posts
+LEFT JOIN post_reaction_counters likes ON likes.post_id = posts.id AND likes.type = 'like'
+WHERE (sqlc.narg(created_after)::timestamptz IS NULL OR created_at > sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR created_at < sqlc.narg(created_before))
+  AND (sqlc.narg(updated_since)::timestamptz IS NULL OR updated_at >= sqlc.narg(updated_since))
+  AND (sqlc.narg(title_contains)::text IS NULL OR title ILIKE '%' || sqlc.narg(title_contains) || '%')
+  AND status = sqlc.arg(status)
+  AND (sqlc.narg(author_id)::int IS NULL OR author_id = sqlc.narg(author_id))
+  AND (
+    coalesce(cardinality(sqlc.arg(tags)::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY(sqlc.arg(tags))
+    ) >= CASE WHEN sqlc.arg(tag_match)::text = 'all' THEN cardinality(sqlc.arg(tags)) ELSE 1 END
+  )
+  AND (
+    sqlc.narg(category_id)::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = sqlc.narg(category_id)
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND (sqlc.narg(cursor_id)::int IS NULL OR (coalesce(likes.count, 0), id) < (sqlc.narg(cursor_likes)::int, sqlc.narg(cursor_id)))
+ORDER BY coalesce(likes.count, 0) DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: SearchPosts :many
+-- The snippet is cut from the sanitized HTML with its tags removed, so the text is escaped
+-- and the only markup in it are the <mark> tags around the matches.
+SELECT posts.*,
+    ts_rank(search_vector, websearch_to_tsquery('english', sqlc.arg(query)))::real AS score,
+    ts_headline('english', regexp_replace(content_html, '<[^>]*>', ' ', 'g'), websearch_to_tsquery('english', sqlc.arg(query)),
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10')::text AS snippet
+FROM posts
+WHERE search_vector @@ websearch_to_tsquery('english', sqlc.arg(query))
+  AND status = 'published'
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+ORDER BY score DESC, id
+LIMIT sqlc.arg(page_size)
+OFFSET sqlc.arg(page_offset);
+
+-- name: UpdatePostStatus :one
+UPDATE posts
+SET status = sqlc.arg(to_status),
+    published_at = CASE WHEN sqlc.arg(to_status) = 'published' THEN now() ELSE published_at END,
+    publish_at = NULL
+WHERE id = sqlc.arg(id) AND status = sqlc.arg(from_status) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: PublishDuePosts :many
+UPDATE posts
+SET status = 'published', published_at = publish_at, publish_at = NULL
+WHERE id IN (
+    SELECT id FROM posts
+    WHERE publish_at <= sqlc.arg(now) AND status <> 'archived' AND deleted_at IS NULL
+    ORDER BY publish_at
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
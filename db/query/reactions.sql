@@ -0,0 +1,19 @@
+-- name: CreatePostReaction :execrows
+INSERT INTO post_reactions (post_id, client_id, type)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: DeletePostReaction :execrows
+DELETE FROM post_reactions
+WHERE post_id = $1 AND client_id = $2 AND type = $3;
+
+-- name: AddReactionCount :exec
+INSERT INTO post_reaction_counters (post_id, type, count)
+VALUES (sqlc.arg(post_id), sqlc.arg(type), sqlc.arg(delta))
+ON CONFLICT (post_id, type) DO UPDATE SET count = post_reaction_counters.count + EXCLUDED.count;
+
+-- name: GetReactionCountsByPostIds :many
+SELECT post_id, type, count
+FROM post_reaction_counters
+WHERE post_id = ANY(sqlc.arg(post_ids)::int[]) AND count > 0
+ORDER BY post_id, type;
@@ -0,0 +1,28 @@
+-- name: GetSitemapState :one
+SELECT count(*) AS posts, coalesce(max(updated_at), 'epoch')::timestamptz AS last_modified, coalesce(sum(id), 0)::bigint AS id_sum
+FROM posts
+WHERE status = 'published'
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL;
+
+-- name: ListSitemapPosts :many
+SELECT id, slug, updated_at
+FROM posts
+WHERE id >= sqlc.arg(first_id)
+  AND status = 'published'
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+ORDER BY id
+LIMIT sqlc.arg(shard_size);
+
+-- name: ListSitemapShards :many
+SELECT min(id)::int AS first_id, max(updated_at)::timestamptz AS last_modified
+FROM (
+    SELECT id, updated_at, (row_number() OVER (ORDER BY id) - 1) / sqlc.arg(shard_size)::bigint AS shard
+    FROM posts
+    WHERE status = 'published'
+      AND (publish_at IS NULL OR publish_at <= now())
+      AND deleted_at IS NULL
+) numbered
+GROUP BY shard
+ORDER BY shard;
@@ -0,0 +1,40 @@
+-- name: UpsertTags :many
+INSERT INTO tags (name)
+SELECT unnest(sqlc.arg(names)::text[])
+ORDER BY 1
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: AddPostTags :exec
+INSERT INTO post_tags (post_id, tag_id)
+SELECT sqlc.arg(post_id)::integer, unnest(sqlc.arg(tag_ids)::int[])
+ON CONFLICT DO NOTHING;
+
+-- name: AddTagsToPosts :exec
+INSERT INTO post_tags (post_id, tag_id)
+SELECT unnest(sqlc.arg(post_ids)::int[]), unnest(sqlc.arg(tag_ids)::int[])
+ON CONFLICT DO NOTHING;
+
+-- name: DeletePostTags :exec
+DELETE FROM post_tags
+WHERE post_id = $1;
+
+-- name: GetTagsByPostIds :many
+SELECT post_tags.post_id, tags.id, tags.name
+FROM post_tags
+JOIN tags ON tags.id = post_tags.tag_id
+WHERE post_tags.post_id = ANY(sqlc.arg(post_ids)::int[])
+ORDER BY post_tags.post_id, tags.name;
+
+-- name: ListTags :many
+SELECT tags.id, tags.name, count(posts.id)::integer AS post_count
+FROM tags
+LEFT JOIN post_tags ON post_tags.tag_id = tags.id
+LEFT JOIN posts ON posts.id = post_tags.post_id
+    AND posts.status = 'published'
+    AND (posts.publish_at IS NULL OR posts.publish_at <= now())
+    AND posts.deleted_at IS NULL
+GROUP BY tags.id
+ORDER BY post_count DESC, tags.name
+LIMIT sqlc.arg(page_size)
+OFFSET sqlc.arg(page_offset);
@@ -0,0 +1,34 @@
+-- name: AddPostViews :exec
+WITH views AS (
+    SELECT unnest(sqlc.arg(post_ids)::int[]) AS post_id, unnest(sqlc.arg(counts)::bigint[]) AS count
+), existing AS (
+    SELECT views.post_id, views.count FROM views JOIN posts ON posts.id = views.post_id
+), totals AS (
+    INSERT INTO post_view_counts (post_id, count)
+    SELECT existing.post_id, existing.count FROM existing
+    ON CONFLICT (post_id) DO UPDATE SET count = post_view_counts.count + EXCLUDED.count
+)
+INSERT INTO post_view_buckets (bucket, post_id, count)
+SELECT sqlc.arg(bucket), existing.post_id, existing.count FROM existing
+ON CONFLICT (bucket, post_id) DO UPDATE SET count = post_view_buckets.count + EXCLUDED.count;
+
+-- name: DeletePostViewBucketsBefore :execrows
+DELETE FROM post_view_buckets
+WHERE bucket < sqlc.arg(before);
+
+-- name: GetViewCountsByPostIds :many
+SELECT post_id, count
+FROM post_view_counts
+WHERE post_id = ANY(sqlc.arg(post_ids)::int[]);
+
+-- name: ListPopularPosts :many
+SELECT posts.*, sum(post_view_buckets.count)::bigint AS views
+FROM post_view_buckets
+JOIN posts ON posts.id = post_view_buckets.post_id
+WHERE post_view_buckets.bucket >= sqlc.arg(since)
+  AND posts.status = 'published'
+  AND (posts.publish_at IS NULL OR posts.publish_at <= now())
+  AND posts.deleted_at IS NULL
+GROUP BY posts.id
+ORDER BY views DESC, posts.id DESC
+LIMIT sqlc.arg(page_size);
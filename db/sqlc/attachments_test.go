@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCreateAttachment(t *testing.T) {
+	createdPost := populateDBWithValidRandomPost(t)
+	arg := CreateAttachmentParams{
+		PostID:      createdPost.ID,
+		BlobKey:     "posts/" + faker.UUIDDigit() + ".png",
+		FileName:    "cover.png",
+		ContentType: "image/png",
+		Size:        2048,
+	}
+
+	attachment, err := testQueries.CreateAttachment(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, attachment.ID)
+	require.Equal(t, arg.BlobKey, attachment.BlobKey)
+	require.Equal(t, arg.Size, attachment.Size)
+
+	attachments, err := testQueries.GetAttachmentsByPostIds(context.Background(), []int32{createdPost.ID})
+	require.NoError(t, err)
+	require.Equal(t, []Attachment{attachment}, attachments)
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	createdPost := populateDBWithValidRandomPost(t)
+	otherPost := populateDBWithValidRandomPost(t)
+	attachment, err := testQueries.CreateAttachment(context.Background(), CreateAttachmentParams{
+		PostID:      createdPost.ID,
+		BlobKey:     "posts/" + faker.UUIDDigit() + ".pdf",
+		FileName:    "report.pdf",
+		ContentType: "application/pdf",
+		Size:        1,
+	})
+	require.NoError(t, err)
+
+	// an attachment is only found under its own post
+	_, err = testQueries.DeleteAttachment(context.Background(), DeleteAttachmentParams{ID: attachment.ID, PostID: otherPost.ID})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	deleted, err := testQueries.DeleteAttachment(context.Background(), DeleteAttachmentParams{ID: attachment.ID, PostID: createdPost.ID})
+	require.NoError(t, err)
+	require.Equal(t, attachment.BlobKey, deleted.BlobKey)
+
+	attachments, err := testQueries.GetAttachmentsByPostIds(context.Background(), []int32{createdPost.ID})
+	require.NoError(t, err)
+	require.Empty(t, attachments)
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-faker/faker/v4"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCreateAuthor(t *testing.T) {
+	populateDBWithValidRandomAuthor(t)
+}
+
+func TestCreateAuthor_EmailTaken(t *testing.T) {
+	author := populateDBWithValidRandomAuthor(t)
+
+	_, err := testQueries.CreateAuthor(context.Background(), CreateAuthorParams{Name: faker.Name(), Email: author.Email})
+
+	var pqError *pq.Error
+	require.ErrorAs(t, err, &pqError)
+	require.Equal(t, "unique_violation", pqError.Code.Name())
+}
+
+func TestGetAuthorsByIds(t *testing.T) {
+	first := populateDBWithValidRandomAuthor(t)
+	second := populateDBWithValidRandomAuthor(t)
+
+	authors, err := testQueries.GetAuthorsByIds(context.Background(), []int32{second.ID, first.ID, -1})
+
+	require.NoError(t, err)
+	require.Len(t, authors, 2)
+	require.Equal(t, first.ID, authors[0].ID)
+	require.Equal(t, second.ID, authors[1].ID)
+}
+
+func TestUpdateAuthor(t *testing.T) {
+	author := populateDBWithValidRandomAuthor(t)
+
+	arg := UpdateAuthorParams{ID: author.ID, Name: faker.Name(), Email: faker.Email(), Bio: faker.Sentence()}
+	updatedAuthor, err := testQueries.UpdateAuthor(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.Equal(t, arg.Name, updatedAuthor.Name)
+	require.Equal(t, arg.Email, updatedAuthor.Email)
+	require.Equal(t, arg.Bio, updatedAuthor.Bio)
+	require.True(t, updatedAuthor.UpdatedAt.After(author.UpdatedAt))
+}
+
+func TestDeleteAuthor_HasPosts(t *testing.T) {
+	author := populateDBWithValidRandomAuthor(t)
+	_, err := testQueries.CreatePost(context.Background(), CreatePostParams{
+		Title:         faker.Sentence(),
+		Content:       faker.Paragraph(),
+		ContentFormat: ContentFormatPlain,
+		AuthorID:      sql.NullInt32{Int32: author.ID, Valid: true},
+		Slug:          faker.UUIDDigit(),
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.DeleteAuthor(context.Background(), author.ID)
+
+	var pqError *pq.Error
+	require.ErrorAs(t, err, &pqError)
+	require.Equal(t, "foreign_key_violation", pqError.Code.Name())
+}
+
+func TestListPosts_ByAuthor(t *testing.T) {
+	author := populateDBWithValidRandomAuthor(t)
+	populateDBWithValidRandomPost(t)
+	post, err := testQueries.CreatePost(context.Background(), CreatePostParams{
+		Title:         faker.Sentence(),
+		Content:       faker.Paragraph(),
+		ContentFormat: ContentFormatPlain,
+		AuthorID:      sql.NullInt32{Int32: author.ID, Valid: true},
+		Slug:          faker.UUIDDigit(),
+	})
+	require.NoError(t, err)
+
+	posts, err := testQueries.ListPostsByID(context.Background(), ListPostsByIDParams{
+		Status:   PostStatusDraft,
+		AuthorID: sql.NullInt32{Int32: author.ID, Valid: true},
+		PageSize: 10,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	require.Equal(t, post.ID, posts[0].ID)
+}
+
+func populateDBWithValidRandomAuthor(t *testing.T) Author {
+	arg := CreateAuthorParams{Name: faker.Name(), Email: faker.Email(), Bio: faker.Sentence()}
+
+	author, err := testQueries.CreateAuthor(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.NotZero(t, author.ID)
+	require.Equal(t, arg.Name, author.Name)
+	require.Equal(t, arg.Email, author.Email)
+	require.Equal(t, arg.Bio, author.Bio)
+	return author
+}
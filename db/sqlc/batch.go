@@ -0,0 +1,277 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"promova-test-task/util"
+	"sort"
+)
+
+// ErrBatchAborted is reported for the operations of an atomic batch that was rolled back because another one failed.
+var ErrBatchAborted = errors.New("batch was aborted by a failed operation")
+
+// PostOperation is a change of a post within a batch. Create is set for a new post, otherwise the post with ID
+// gets updated by Update or, when there is none, deleted after Delete let it, see UpdatePostTx and DeletePostTx.
+type PostOperation struct {
+	Create *CreatePostTxParams
+	ID     int32
+	Update func(post Post) (UpdatePostTxParams, error)
+	Delete func(post Post) error
+}
+
+// PostOperationResult is the post an operation created or updated, or the error it failed with
+type PostOperationResult struct {
+	Post Post
+	Err  error
+}
+
+// BatchPostsTx runs the operations, the creates first with a single multi-row insert and then the updates and deletes
+// in order. A create that cannot be inserted fails with its own error, see insertPosts. An atomic batch runs
+// in a single transaction rolled back as a whole by the first failing operation, and the other operations fail
+// with ErrBatchAborted. Otherwise the creates share a transaction and every update and delete runs in one of its own,
+// so a failing operation only takes itself down. The returned error is for an atomic batch that could not be committed.
+func (store *SQLStore) BatchPostsTx(ctx context.Context, operations []PostOperation, atomic bool) ([]PostOperationResult, error) {
+	if !atomic {
+		return store.batchPosts(ctx, operations), nil
+	}
+
+	var results []PostOperationResult
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		results = make([]PostOperationResult, len(operations))
+		if err := insertPosts(ctx, q, operations, results); err != nil {
+			return err
+		}
+
+		for i, operation := range operations {
+			if operation.Create == nil {
+				results[i].Post, results[i].Err = runPostOperation(ctx, q, operation)
+			}
+			if results[i].Err != nil {
+				return results[i].Err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		return results, nil
+	}
+
+	failed := false
+	for i := range results {
+		if results[i].Err != nil {
+			failed = true
+			continue
+		}
+		results[i] = PostOperationResult{Err: ErrBatchAborted}
+	}
+	if !failed {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (store *SQLStore) batchPosts(ctx context.Context, operations []PostOperation) []PostOperationResult {
+	results := make([]PostOperationResult, len(operations))
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		return insertPosts(ctx, q, operations, results)
+	})
+	if err != nil {
+		for i, operation := range operations {
+			if operation.Create != nil {
+				results[i] = PostOperationResult{Err: err}
+			}
+		}
+	}
+
+	for i, operation := range operations {
+		if operation.Create != nil {
+			continue
+		}
+		var post Post
+		err := store.ExecTx(ctx, func(q *Queries) error {
+			var err error
+			post, err = runPostOperation(ctx, q, operation)
+			return err
+		})
+		results[i] = PostOperationResult{Post: post, Err: err}
+	}
+	return results
+}
+
+func runPostOperation(ctx context.Context, q *Queries, operation PostOperation) (Post, error) {
+	if operation.Update != nil {
+		return updatePostInTx(ctx, q, operation.ID, operation.Update)
+	}
+	return Post{}, deletePostInTx(ctx, q, operation.ID, operation.Delete)
+}
+
+// insertPosts inserts the posts of the create operations and tags them, setting their results. The posts are
+// inserted at once, and when that fails one at a time under savepoints of their own, so that a create that cannot
+// be inserted fails with its own error while the others are inserted. Posts with the same title in the batch
+// get different slugs. The returned error is for a failure the creates cannot be told apart by.
+func insertPosts(ctx context.Context, q *Queries, operations []PostOperation, results []PostOperationResult) error {
+	var indexes []int
+	var titles []string
+	for i, operation := range operations {
+		if operation.Create != nil {
+			indexes = append(indexes, i)
+			titles = append(titles, operation.Create.Title)
+		}
+	}
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	slugs, err := allocateSlugs(ctx, q, titles)
+	if err != nil {
+		return err
+	}
+
+	insertErr, err := inSavepoint(ctx, q, func() error {
+		return insertPostsAtOnce(ctx, q, operations, indexes, slugs, results)
+	})
+	if err != nil {
+		return err
+	}
+	if insertErr != nil {
+		for n, i := range indexes {
+			arg := operations[i].Create.CreatePostParams
+			arg.Slug = slugs[n]
+			results[i] = PostOperationResult{}
+			results[i].Err, err = inSavepoint(ctx, q, func() error {
+				var err error
+				results[i].Post, err = q.CreatePost(ctx, arg)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	tags := make(map[int32][]string, len(indexes))
+	for _, i := range indexes {
+		if names := operations[i].Create.Tags; results[i].Err == nil && len(names) > 0 {
+			tags[results[i].Post.ID] = names
+		}
+	}
+	return tagNewPosts(ctx, q, tags)
+}
+
+// insertPostsAtOnce inserts the posts of the create operations at the indexes with a single multi-row insert.
+func insertPostsAtOnce(ctx context.Context, q *Queries, operations []PostOperation, indexes []int, slugs []string, results []PostOperationResult) error {
+	arg := CreatePostsParams{}
+	for n, i := range indexes {
+		create := operations[i].Create
+		arg.Titles = append(arg.Titles, create.Title)
+		arg.Contents = append(arg.Contents, create.Content)
+		arg.PublishAts = append(arg.PublishAts, create.PublishAt)
+		arg.AuthorIds = append(arg.AuthorIds, create.AuthorID)
+		arg.CategoryIds = append(arg.CategoryIds, create.CategoryID)
+		arg.Slugs = append(arg.Slugs, slugs[n])
+		arg.ContentFormats = append(arg.ContentFormats, create.ContentFormat)
+		arg.ContentHtmls = append(arg.ContentHtmls, create.ContentHtml)
+	}
+	posts, err := q.CreatePosts(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	// slugs are unique, unlike the order of the returned rows they are sure to match the posts to their operations
+	postsBySlug := make(map[string]Post, len(posts))
+	for _, post := range posts {
+		postsBySlug[post.Slug] = post
+	}
+	for n, i := range indexes {
+		results[i] = PostOperationResult{Post: postsBySlug[slugs[n]]}
+	}
+	return nil
+}
+
+// inSavepoint runs fn under a savepoint and rolls back to it when fn fails, so that the transaction can go on.
+// The error of fn is returned apart from the one of the savepoint, after which the transaction is unusable.
+func inSavepoint(ctx context.Context, q *Queries, fn func() error) (fnErr error, err error) {
+	if _, err := q.db.ExecContext(ctx, "SAVEPOINT batch_insert"); err != nil {
+		return nil, err
+	}
+	if fnErr := fn(); fnErr != nil {
+		_, err := q.db.ExecContext(ctx, "ROLLBACK TO SAVEPOINT batch_insert")
+		return fnErr, err
+	}
+	_, err = q.db.ExecContext(ctx, "RELEASE SAVEPOINT batch_insert")
+	return nil, err
+}
+
+// tagNewPosts tags new posts with the named tags, creating the ones that do not exist yet.
+func tagNewPosts(ctx context.Context, q *Queries, tags map[int32][]string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, postTags := range tags {
+		for _, name := range postTags {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	upserted, err := q.UpsertTags(ctx, names)
+	if err != nil {
+		return err
+	}
+	tagIDs := make(map[string]int32, len(upserted))
+	for _, tag := range upserted {
+		tagIDs[tag.Name] = tag.ID
+	}
+
+	arg := AddTagsToPostsParams{}
+	for postID, postTags := range tags {
+		for _, name := range postTags {
+			arg.PostIds = append(arg.PostIds, postID)
+			arg.TagIds = append(arg.TagIds, tagIDs[name])
+		}
+	}
+	return q.AddTagsToPosts(ctx, arg)
+}
+
+// allocateSlugs is allocateSlug for new posts with the titles, returning their slugs in the same order.
+// The slugs are locked in sorted order, so that concurrent batches sharing some of them do not deadlock.
+func allocateSlugs(ctx context.Context, q *Queries, titles []string) ([]string, error) {
+	bases := make([]string, 0, len(titles))
+	seen := make(map[string]bool, len(titles))
+	var distinct []string
+	for _, title := range titles {
+		base := util.Slugify(title)
+		bases = append(bases, base)
+		if !seen[base] {
+			seen[base] = true
+			distinct = append(distinct, base)
+		}
+	}
+	sort.Strings(distinct)
+
+	// a slug given to a post of the batch may have the base of another one, so all of them count as taken for each
+	var taken []string
+	for _, base := range distinct {
+		if err := q.LockSlug(ctx, base); err != nil {
+			return nil, err
+		}
+		slugs, err := q.ListTakenSlugs(ctx, ListTakenSlugsParams{Base: base})
+		if err != nil {
+			return nil, err
+		}
+		taken = append(taken, slugs...)
+	}
+
+	slugs := make([]string, 0, len(bases))
+	for _, base := range bases {
+		slug := util.UniqueSlug(base, taken)
+		taken = append(taken, slug)
+		slugs = append(slugs, slug)
+	}
+	return slugs, nil
+}
@@ -0,0 +1,173 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-faker/faker/v4"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"promova-test-task/util"
+	"testing"
+)
+
+func TestBatchPostsTx_Atomic(t *testing.T) {
+	store := NewStore(testDB)
+	updatedPost := populateDBWithValidRandomPost(t)
+	deletedPost := populateDBWithValidRandomPost(t)
+	title := "Batch " + faker.UUIDDigit()
+	newTitle := "Batch update " + faker.UUIDDigit()
+
+	create := &CreatePostTxParams{
+		CreatePostParams: CreatePostParams{Title: title, Content: faker.Paragraph(), ContentFormat: ContentFormatPlain},
+		Tags:             []string{"batch", "go"},
+	}
+	results, err := store.BatchPostsTx(context.Background(), []PostOperation{
+		{Create: create},
+		{ID: updatedPost.ID, Update: func(post Post) (UpdatePostTxParams, error) {
+			return UpdatePostTxParams{UpdatePostByIdParams: UpdatePostByIdParams{Title: newTitle, Content: post.Content, ContentFormat: ContentFormatPlain}}, nil
+		}},
+		{Create: create},
+		{ID: deletedPost.ID, Delete: func(post Post) error {
+			return nil
+		}},
+	}, true)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	for _, result := range results {
+		require.NoError(t, result.Err)
+	}
+
+	base := util.Slugify(title)
+	require.Equal(t, title, results[0].Post.Title)
+	require.Equal(t, base, results[0].Post.Slug)
+	require.Equal(t, base+"-2", results[2].Post.Slug)
+	require.Equal(t, newTitle, results[1].Post.Title)
+	require.Equal(t, util.Slugify(newTitle), results[1].Post.Slug)
+
+	tags, err := testQueries.GetTagsByPostIds(context.Background(), []int32{results[0].Post.ID, results[2].Post.ID})
+	require.NoError(t, err)
+	require.Len(t, tags, 4)
+
+	_, err = testQueries.GetPostById(context.Background(), deletedPost.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+func TestBatchPostsTx_AtomicRollback(t *testing.T) {
+	store := NewStore(testDB)
+	deletedPost := populateDBWithValidRandomPost(t)
+	title := "Batch rollback " + faker.UUIDDigit()
+
+	results, err := store.BatchPostsTx(context.Background(), []PostOperation{
+		{Create: &CreatePostTxParams{
+			CreatePostParams: CreatePostParams{Title: title, Content: faker.Paragraph(), ContentFormat: ContentFormatPlain},
+		}},
+		{ID: deletedPost.ID, Delete: func(post Post) error {
+			return nil
+		}},
+		{ID: -1, Delete: func(post Post) error {
+			return nil
+		}},
+	}, true)
+	require.NoError(t, err)
+	require.ErrorIs(t, results[0].Err, ErrBatchAborted)
+	require.ErrorIs(t, results[1].Err, ErrBatchAborted)
+	require.ErrorIs(t, results[2].Err, sql.ErrNoRows)
+
+	_, err = testQueries.GetPostBySlug(context.Background(), util.Slugify(title))
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	post, err := testQueries.GetPostById(context.Background(), deletedPost.ID)
+	checkFetchedPostIsValid(t, err, post, deletedPost)
+}
+
+func TestBatchPostsTx_AtomicCreateFails(t *testing.T) {
+	store := NewStore(testDB)
+	title := "Batch atomic create " + faker.UUIDDigit()
+
+	results, err := store.BatchPostsTx(context.Background(), []PostOperation{
+		{Create: &CreatePostTxParams{
+			CreatePostParams: CreatePostParams{Title: title, Content: faker.Paragraph(), ContentFormat: ContentFormatPlain},
+		}},
+		{Create: &CreatePostTxParams{
+			CreatePostParams: CreatePostParams{
+				Title:         title,
+				Content:       faker.Paragraph(),
+				ContentFormat: ContentFormatPlain,
+				CategoryID:    sql.NullInt32{Int32: -1, Valid: true},
+			},
+		}},
+	}, true)
+	require.NoError(t, err)
+	require.ErrorIs(t, results[0].Err, ErrBatchAborted)
+
+	var pqError *pq.Error
+	require.ErrorAs(t, results[1].Err, &pqError)
+	require.Equal(t, "foreign_key_violation", pqError.Code.Name())
+
+	_, err = testQueries.GetPostBySlug(context.Background(), util.Slugify(title))
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestBatchPostsTx_BestEffort(t *testing.T) {
+	store := NewStore(testDB)
+	deletedPost := populateDBWithValidRandomPost(t)
+	title := "Batch best effort " + faker.UUIDDigit()
+
+	results, err := store.BatchPostsTx(context.Background(), []PostOperation{
+		{ID: -1, Delete: func(post Post) error {
+			return nil
+		}},
+		{Create: &CreatePostTxParams{
+			CreatePostParams: CreatePostParams{Title: title, Content: faker.Paragraph(), ContentFormat: ContentFormatPlain},
+		}},
+		{ID: deletedPost.ID, Delete: func(post Post) error {
+			return nil
+		}},
+	}, false)
+	require.NoError(t, err)
+	require.ErrorIs(t, results[0].Err, sql.ErrNoRows)
+	require.NoError(t, results[1].Err)
+	require.NoError(t, results[2].Err)
+
+	post, err := testQueries.GetPostBySlug(context.Background(), util.Slugify(title))
+	require.NoError(t, err)
+	require.Equal(t, results[1].Post.ID, post.ID)
+	_, err = testQueries.GetPostById(context.Background(), deletedPost.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestBatchPostsTx_BestEffortCreateFails(t *testing.T) {
+	store := NewStore(testDB)
+	title := "Batch best effort create " + faker.UUIDDigit()
+
+	create := &CreatePostTxParams{
+		CreatePostParams: CreatePostParams{Title: title, Content: faker.Paragraph(), ContentFormat: ContentFormatPlain},
+		Tags:             []string{"batch"},
+	}
+	results, err := store.BatchPostsTx(context.Background(), []PostOperation{
+		{Create: create},
+		{Create: &CreatePostTxParams{
+			CreatePostParams: CreatePostParams{
+				Title:         title,
+				Content:       faker.Paragraph(),
+				ContentFormat: ContentFormatPlain,
+				AuthorID:      sql.NullInt32{Int32: -1, Valid: true},
+			},
+		}},
+		{Create: create},
+	}, false)
+	require.NoError(t, err)
+	require.NoError(t, results[0].Err)
+	require.NoError(t, results[2].Err)
+
+	var pqError *pq.Error
+	require.ErrorAs(t, results[1].Err, &pqError)
+	require.Equal(t, "posts_author_id_fkey", pqError.Constraint)
+
+	// the failed create leaves its slug unused
+	base := util.Slugify(title)
+	require.Equal(t, base, results[0].Post.Slug)
+	require.Equal(t, base+"-3", results[2].Post.Slug)
+
+	tags, err := testQueries.GetTagsByPostIds(context.Background(), []int32{results[0].Post.ID, results[2].Post.ID})
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestGetCategoryPaths(t *testing.T) {
+	news := populateDBWithValidRandomCategory(t, sql.NullInt32{})
+	product := populateDBWithValidRandomCategory(t, sql.NullInt32{Int32: news.ID, Valid: true})
+	releases := populateDBWithValidRandomCategory(t, sql.NullInt32{Int32: product.ID, Valid: true})
+
+	rows, err := testQueries.GetCategoryPaths(context.Background(), []int32{releases.ID, news.ID})
+
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	require.Equal(t, GetCategoryPathsRow{CategoryID: news.ID, ID: news.ID, Name: news.Name}, rows[0])
+	require.Equal(t, GetCategoryPathsRow{CategoryID: releases.ID, ID: news.ID, Name: news.Name}, rows[1])
+	require.Equal(t, GetCategoryPathsRow{CategoryID: releases.ID, ID: product.ID, Name: product.Name}, rows[2])
+	require.Equal(t, GetCategoryPathsRow{CategoryID: releases.ID, ID: releases.ID, Name: releases.Name}, rows[3])
+}
+
+func TestListPosts_ByCategoryIncludesDescendants(t *testing.T) {
+	news := populateDBWithValidRandomCategory(t, sql.NullInt32{})
+	product := populateDBWithValidRandomCategory(t, sql.NullInt32{Int32: news.ID, Valid: true})
+	other := populateDBWithValidRandomCategory(t, sql.NullInt32{})
+
+	createPost := func(category Category) Post {
+		post, err := testQueries.CreatePost(context.Background(), CreatePostParams{
+			Title:         faker.Sentence(),
+			Content:       faker.Paragraph(),
+			ContentFormat: ContentFormatPlain,
+			CategoryID:    sql.NullInt32{Int32: category.ID, Valid: true},
+			Slug:          faker.UUIDDigit(),
+		})
+		require.NoError(t, err)
+		return post
+	}
+	inNews := createPost(news)
+	inProduct := createPost(product)
+	createPost(other)
+
+	posts, err := testQueries.ListPostsByID(context.Background(), ListPostsByIDParams{
+		Status:     PostStatusDraft,
+		CategoryID: sql.NullInt32{Int32: news.ID, Valid: true},
+		PageSize:   10,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	require.Equal(t, inNews.ID, posts[0].ID)
+	require.Equal(t, inProduct.ID, posts[1].ID)
+}
+
+func populateDBWithValidRandomCategory(t *testing.T, parentID sql.NullInt32) Category {
+	arg := CreateCategoryParams{ParentID: parentID, Name: faker.Word() + faker.Word()}
+
+	category, err := testQueries.CreateCategory(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.NotZero(t, category.ID)
+	require.Equal(t, arg.ParentID, category.ParentID)
+	require.Equal(t, arg.Name, category.Name)
+	return category
+}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-faker/faker/v4"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestListCommentThreads(t *testing.T) {
+	post := populateDBWithValidRandomPost(t)
+	first := populateDBWithValidRandomComment(t, post.ID, sql.NullInt32{}, CommentStatusApproved)
+	reply := populateDBWithValidRandomComment(t, post.ID, sql.NullInt32{Int32: first.ID, Valid: true}, CommentStatusApproved)
+	second := populateDBWithValidRandomComment(t, post.ID, sql.NullInt32{}, CommentStatusApproved)
+	nested := populateDBWithValidRandomComment(t, post.ID, sql.NullInt32{Int32: reply.ID, Valid: true}, CommentStatusApproved)
+	populateDBWithValidRandomComment(t, post.ID, sql.NullInt32{Int32: first.ID, Valid: true}, CommentStatusPending)
+	populateDBWithValidRandomComment(t, post.ID, sql.NullInt32{}, CommentStatusApproved)
+
+	rows, err := testQueries.ListCommentThreads(context.Background(), ListCommentThreadsParams{
+		PostID:   post.ID,
+		Status:   NullCommentStatus{CommentStatus: CommentStatusApproved, Valid: true},
+		PageSize: 2,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	require.Equal(t, []int32{first.ID, reply.ID, nested.ID, second.ID}, []int32{rows[0].ID, rows[1].ID, rows[2].ID, rows[3].ID})
+	require.Equal(t, []int32{0, 1, 2, 0}, []int32{rows[0].Depth, rows[1].Depth, rows[2].Depth, rows[3].Depth})
+}
+
+func TestListCommentsByStatus(t *testing.T) {
+	post := populateDBWithValidRandomPost(t)
+	approved := populateDBWithValidRandomComment(t, post.ID, sql.NullInt32{}, CommentStatusApproved)
+	pendingReply := populateDBWithValidRandomComment(t, post.ID, sql.NullInt32{Int32: approved.ID, Valid: true}, CommentStatusPending)
+	pending := populateDBWithValidRandomComment(t, post.ID, sql.NullInt32{}, CommentStatusPending)
+	populateDBWithValidRandomComment(t, post.ID, sql.NullInt32{Int32: approved.ID, Valid: true}, CommentStatusRejected)
+
+	comments, err := testQueries.ListCommentsByStatus(context.Background(), ListCommentsByStatusParams{
+		PostID:   post.ID,
+		Status:   CommentStatusPending,
+		PageSize: 10,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	require.Equal(t, []int32{pendingReply.ID, pending.ID}, []int32{comments[0].ID, comments[1].ID})
+}
+
+func TestCreateComment_ParentOnAnotherPost(t *testing.T) {
+	post := populateDBWithValidRandomPost(t)
+	otherPost := populateDBWithValidRandomPost(t)
+	parent := populateDBWithValidRandomComment(t, otherPost.ID, sql.NullInt32{}, CommentStatusApproved)
+
+	_, err := testQueries.CreateComment(context.Background(), CreateCommentParams{
+		PostID:     post.ID,
+		ParentID:   sql.NullInt32{Int32: parent.ID, Valid: true},
+		AuthorName: faker.Name(),
+		Body:       faker.Sentence(),
+	})
+
+	var pqError *pq.Error
+	require.ErrorAs(t, err, &pqError)
+	require.Equal(t, "foreign_key_violation", pqError.Code.Name())
+}
+
+func TestPurgePost_DeletesComments(t *testing.T) {
+	post := populateDBWithValidRandomPost(t)
+	comment := populateDBWithValidRandomComment(t, post.ID, sql.NullInt32{}, CommentStatusApproved)
+	populateDBWithValidRandomComment(t, post.ID, sql.NullInt32{Int32: comment.ID, Valid: true}, CommentStatusApproved)
+
+	_, err := testQueries.PurgePost(context.Background(), post.ID)
+	require.NoError(t, err)
+
+	deleted, err := testQueries.DeleteComment(context.Background(), comment.ID)
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+}
+
+func TestGetCommentCountsByPostIds(t *testing.T) {
+	post := populateDBWithValidRandomPost(t)
+	approved := populateDBWithValidRandomComment(t, post.ID, sql.NullInt32{}, CommentStatusApproved)
+	populateDBWithValidRandomComment(t, post.ID, sql.NullInt32{Int32: approved.ID, Valid: true}, CommentStatusApproved)
+	rejected := populateDBWithValidRandomComment(t, post.ID, sql.NullInt32{}, CommentStatusRejected)
+	pending := populateDBWithValidRandomComment(t, post.ID, sql.NullInt32{Int32: approved.ID, Valid: true}, CommentStatusPending)
+
+	// approved replies below a comment that is not shown are not shown either
+	populateDBWithValidRandomComment(t, post.ID, sql.NullInt32{Int32: rejected.ID, Valid: true}, CommentStatusApproved)
+	populateDBWithValidRandomComment(t, post.ID, sql.NullInt32{Int32: pending.ID, Valid: true}, CommentStatusApproved)
+
+	rows, err := testQueries.GetCommentCountsByPostIds(context.Background(), []int32{post.ID})
+
+	require.NoError(t, err)
+	require.Equal(t, []GetCommentCountsByPostIdsRow{{PostID: post.ID, CommentCount: 2}}, rows)
+}
+
+func populateDBWithValidRandomComment(t *testing.T, postID int32, parentID sql.NullInt32, status CommentStatus) Comment {
+	arg := CreateCommentParams{PostID: postID, ParentID: parentID, AuthorName: faker.Name(), Body: faker.Sentence()}
+
+	comment, err := testQueries.CreateComment(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, CommentStatusPending, comment.Status)
+	require.Equal(t, arg.ParentID, comment.ParentID)
+
+	if status == CommentStatusPending {
+		return comment
+	}
+	comment, err = testQueries.UpdateCommentStatus(context.Background(), UpdateCommentStatusParams{ID: comment.ID, Status: status})
+	require.NoError(t, err)
+	require.Equal(t, status, comment.Status)
+	return comment
+}
@@ -0,0 +1,162 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func createRandomPostCover(t *testing.T, postID int32) PostCover {
+	cover, previous, err := NewStore(testDB).ReplacePostCoverTx(context.Background(), UpsertPostCoverParams{
+		PostID:      postID,
+		BlobKey:     "covers/" + faker.UUIDDigit() + "/original.png",
+		ContentType: "image/png",
+		Width:       640,
+		Height:      480,
+	})
+	require.NoError(t, err)
+	require.Nil(t, previous)
+	require.Equal(t, CoverStatusPending, cover.Status)
+	return cover
+}
+
+// claimCoverOf claims pending covers until it gets the one of the post, other tests may have left theirs pending.
+func claimCoverOf(t *testing.T, postID int32, now time.Time) PostCover {
+	for {
+		cover, err := testQueries.ClaimPostCover(context.Background(), ClaimPostCoverParams{
+			Now:         now,
+			StaleBefore: now.Add(-time.Minute),
+		})
+		require.NoError(t, err)
+		if cover.PostID == postID {
+			return cover
+		}
+	}
+}
+
+func TestReplacePostCoverTx(t *testing.T) {
+	createdPost := populateDBWithValidRandomPost(t)
+	first := createRandomPostCover(t, createdPost.ID)
+
+	arg := UpsertPostCoverParams{
+		PostID:      createdPost.ID,
+		BlobKey:     "covers/" + faker.UUIDDigit() + "/original.jpg",
+		ContentType: "image/jpeg",
+		Width:       1024,
+		Height:      768,
+	}
+	second, previous, err := NewStore(testDB).ReplacePostCoverTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotNil(t, previous)
+	require.Equal(t, first.BlobKey, previous.BlobKey)
+	require.Equal(t, arg.BlobKey, second.BlobKey)
+	require.Equal(t, arg.Width, second.Width)
+	require.Equal(t, CoverStatusPending, second.Status)
+
+	covers, err := testQueries.GetPostCoversByPostIds(context.Background(), []int32{createdPost.ID})
+	require.NoError(t, err)
+	require.Len(t, covers, 1)
+	require.Equal(t, arg.BlobKey, covers[0].BlobKey)
+}
+
+func TestReplacePostCoverTx_MissingPost(t *testing.T) {
+	_, _, err := NewStore(testDB).ReplacePostCoverTx(context.Background(), UpsertPostCoverParams{
+		PostID:      -1,
+		BlobKey:     "covers/" + faker.UUIDDigit() + "/original.png",
+		ContentType: "image/png",
+		Width:       1,
+		Height:      1,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestCompletePostCover(t *testing.T) {
+	createdPost := populateDBWithValidRandomPost(t)
+	cover := createRandomPostCover(t, createdPost.ID)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	claimed := claimCoverOf(t, createdPost.ID, now)
+	require.Equal(t, CoverStatusProcessing, claimed.Status)
+	require.True(t, claimed.ClaimedAt.Valid)
+
+	arg := CompletePostCoverParams{
+		PostID:       createdPost.ID,
+		BlobKey:      cover.BlobKey,
+		Width:        480,
+		Height:       640,
+		ThumbnailKey: sql.NullString{String: "covers/thumbnail.jpg", Valid: true},
+		MediumKey:    sql.NullString{String: "covers/medium.jpg", Valid: true},
+		LargeKey:     sql.NullString{String: "covers/large.jpg", Valid: true},
+	}
+	completed, err := testQueries.CompletePostCover(context.Background(), arg)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, completed)
+
+	// a cover is only completed once
+	completed, err = testQueries.CompletePostCover(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, completed)
+
+	covers, err := testQueries.GetPostCoversByPostIds(context.Background(), []int32{createdPost.ID})
+	require.NoError(t, err)
+	require.Len(t, covers, 1)
+	require.Equal(t, CoverStatusReady, covers[0].Status)
+	require.Equal(t, arg.Width, covers[0].Width)
+	require.Equal(t, arg.ThumbnailKey, covers[0].ThumbnailKey)
+	require.False(t, covers[0].ClaimedAt.Valid)
+}
+
+func TestCompletePostCover_Replaced(t *testing.T) {
+	createdPost := populateDBWithValidRandomPost(t)
+	cover := createRandomPostCover(t, createdPost.ID)
+	claimCoverOf(t, createdPost.ID, time.Now().UTC())
+
+	_, _, err := NewStore(testDB).ReplacePostCoverTx(context.Background(), UpsertPostCoverParams{
+		PostID:      createdPost.ID,
+		BlobKey:     "covers/" + faker.UUIDDigit() + "/original.png",
+		ContentType: "image/png",
+		Width:       1,
+		Height:      1,
+	})
+	require.NoError(t, err)
+
+	// the variants of the replaced image are not attached to the new one
+	completed, err := testQueries.CompletePostCover(context.Background(), CompletePostCoverParams{
+		PostID:       createdPost.ID,
+		BlobKey:      cover.BlobKey,
+		Width:        1,
+		Height:       1,
+		ThumbnailKey: sql.NullString{String: "covers/thumbnail.jpg", Valid: true},
+	})
+	require.NoError(t, err)
+	require.Zero(t, completed)
+}
+
+func TestFailPostCover(t *testing.T) {
+	createdPost := populateDBWithValidRandomPost(t)
+	cover := createRandomPostCover(t, createdPost.ID)
+	claimCoverOf(t, createdPost.ID, time.Now().UTC())
+
+	failed, err := testQueries.FailPostCover(context.Background(), FailPostCoverParams{PostID: createdPost.ID, BlobKey: cover.BlobKey})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, failed)
+
+	covers, err := testQueries.GetPostCoversByPostIds(context.Background(), []int32{createdPost.ID})
+	require.NoError(t, err)
+	require.Equal(t, CoverStatusFailed, covers[0].Status)
+}
+
+func TestClaimPostCover_ReclaimsStale(t *testing.T) {
+	createdPost := populateDBWithValidRandomPost(t)
+	createRandomPostCover(t, createdPost.ID)
+	claimedAt := time.Now().UTC().Add(-time.Hour)
+	claimCoverOf(t, createdPost.ID, claimedAt)
+
+	// a cover claimed by a processor that died is claimed again once stale
+	reclaimed := claimCoverOf(t, createdPost.ID, time.Now().UTC())
+	require.Equal(t, CoverStatusProcessing, reclaimed.Status)
+	require.True(t, reclaimed.ClaimedAt.Time.After(claimedAt))
+}
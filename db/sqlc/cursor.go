@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+// cursorFetchSize is how many rows are fetched from a server-side cursor at a time
+const cursorFetchSize = 1000
+
+// StreamSitemapPosts calls fn with the posts ListSitemapPosts would return, one at a time as they are fetched.
+func (store *SQLStore) StreamSitemapPosts(ctx context.Context, arg ListSitemapPostsParams, fn func(ListSitemapPostsRow) error) error {
+	return store.streamCursor(ctx, "sitemap_posts", listSitemapPosts, []interface{}{arg.FirstID, arg.ShardSize}, func(rows *sql.Rows) error {
+		var i ListSitemapPostsRow
+		if err := rows.Scan(&i.ID, &i.Slug, &i.UpdatedAt); err != nil {
+			return err
+		}
+		return fn(i)
+	})
+}
+
+// StreamExportPosts calls fn with the posts ListExportPosts would return, one at a time as they are fetched.
+func (store *SQLStore) StreamExportPosts(ctx context.Context, arg ListExportPostsParams, fn func(ListExportPostsRow) error) error {
+	args := []interface{}{
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedSince,
+		arg.TitleContains,
+		arg.Status,
+		pq.Array(arg.Tags),
+		arg.TagMatch,
+	}
+	return store.streamCursor(ctx, "export_posts", listExportPosts, args, func(rows *sql.Rows) error {
+		var i ListExportPostsRow
+		err := rows.Scan(
+			&i.ID,
+			&i.Slug,
+			&i.Title,
+			&i.Content,
+			&i.ContentFormat,
+			&i.Status,
+			&i.AuthorID,
+			&i.CategoryID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PublishedAt,
+			&i.Version,
+			pq.Array(&i.Tags),
+		)
+		if err != nil {
+			return err
+		}
+		return fn(i)
+	})
+}
+
+// streamCursor runs the query through a server-side cursor and scans its rows in batches,
+// so that neither the database driver nor the caller holds the whole result at once.
+// The cursor lives in a read-only transaction which sees a single snapshot of the data.
+func (store *SQLStore) streamCursor(ctx context.Context, name string, query string, args []interface{}, scan func(rows *sql.Rows) error) error {
+	tx, err := store.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", name, query), args...); err != nil {
+		return err
+	}
+
+	fetch := fmt.Sprintf("FETCH %d FROM %s", cursorFetchSize, name)
+	for {
+		fetched, err := fetchCursor(ctx, tx, fetch, scan)
+		if err != nil {
+			return err
+		}
+		if fetched < cursorFetchSize {
+			return tx.Commit()
+		}
+	}
+}
+
+func fetchCursor(ctx context.Context, tx *sql.Tx, fetch string, scan func(rows *sql.Rows) error) (int, error) {
+	rows, err := tx.QueryContext(ctx, fetch)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	fetched := 0
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return fetched, err
+		}
+		fetched++
+	}
+	return fetched, rows.Err()
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestStreamExportPosts(t *testing.T) {
+	store := NewStore(testDB)
+	marker := faker.UUIDDigit()
+	var created []Post
+	for i := 0; i < 2; i++ {
+		post, err := store.CreatePostTx(context.Background(), CreatePostTxParams{
+			CreatePostParams: CreatePostParams{Title: "Export " + marker, Content: faker.Paragraph(), ContentFormat: ContentFormatPlain},
+			Tags:             []string{"export", "go"},
+		})
+		require.NoError(t, err)
+		created = append(created, post)
+	}
+	populateDBWithValidRandomPost(t)
+
+	arg := ListExportPostsParams{
+		TitleContains: sql.NullString{String: marker, Valid: true},
+		Status:        PostStatusDraft,
+		Tags:          []string{"export"},
+		TagMatch:      "any",
+	}
+	var streamed []ListExportPostsRow
+	err := store.StreamExportPosts(context.Background(), arg, func(row ListExportPostsRow) error {
+		streamed = append(streamed, row)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, streamed, 2)
+	for i, row := range streamed {
+		require.Equal(t, created[i].ID, row.ID)
+		require.Equal(t, created[i].Slug, row.Slug)
+		require.Equal(t, created[i].Content, row.Content)
+		require.Equal(t, []string{"export", "go"}, row.Tags)
+	}
+
+	listed, err := testQueries.ListExportPosts(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, streamed, listed)
+
+	arg.Status = PostStatusPublished
+	listed, err = testQueries.ListExportPosts(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, listed)
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/lib/pq"
+)
+
+// importColumns are the columns of posts filled by an import, in the order their values are copied
+var importColumns = []string{
+	"title", "content", "publish_at", "author_id", "category_id", "slug", "content_format", "content_html",
+}
+
+// ImportPostResult is the id an imported post got, or Duplicate when a post with its title and content already existed
+type ImportPostResult struct {
+	ID        int32
+	Duplicate bool
+}
+
+// ImportPostsTx inserts the posts with COPY FROM and tags them in a single transaction, returning their results
+// in the same order. Posts with the title and content of an existing post are left out as duplicates, so that
+// an import that failed midway can be run again. Posts duplicating each other are the caller's to leave out.
+// The transaction is serializable, so that of two concurrent imports of the same posts one is retried
+// and finds the posts of the other as duplicates instead of inserting them again.
+func (store *SQLStore) ImportPostsTx(ctx context.Context, posts []CreatePostTxParams) ([]ImportPostResult, error) {
+	var results []ImportPostResult
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		results = make([]ImportPostResult, len(posts))
+		if len(posts) == 0 {
+			return nil
+		}
+
+		arg := ListDuplicatePostsParams{}
+		for _, post := range posts {
+			arg.Titles = append(arg.Titles, post.Title)
+			arg.Contents = append(arg.Contents, post.Content)
+		}
+		duplicates, err := q.ListDuplicatePosts(ctx, arg)
+		if err != nil {
+			return err
+		}
+		for _, position := range duplicates {
+			results[position-1].Duplicate = true
+		}
+
+		var indexes []int
+		var titles []string
+		for i, post := range posts {
+			if !results[i].Duplicate {
+				indexes = append(indexes, i)
+				titles = append(titles, post.Title)
+			}
+		}
+		if len(indexes) == 0 {
+			return nil
+		}
+		slugs, err := allocateSlugs(ctx, q, titles)
+		if err != nil {
+			return err
+		}
+
+		if err := copyPosts(ctx, q, posts, indexes, slugs); err != nil {
+			return err
+		}
+
+		// COPY returns no rows, the slugs are unique and tell the ids of the new posts
+		rows, err := q.ListPostIDsBySlugs(ctx, slugs)
+		if err != nil {
+			return err
+		}
+		ids := make(map[string]int32, len(rows))
+		for _, row := range rows {
+			ids[row.Slug] = row.ID
+		}
+		tags := make(map[int32][]string, len(indexes))
+		for n, i := range indexes {
+			results[i].ID = ids[slugs[n]]
+			if names := posts[i].Tags; len(names) > 0 {
+				tags[results[i].ID] = names
+			}
+		}
+		return tagNewPosts(ctx, q, tags)
+	}, WithIsolation(sql.LevelSerializable))
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// copyPosts streams the posts at the indexes into the posts table with COPY FROM, with the slugs in the same order.
+func copyPosts(ctx context.Context, q *Queries, posts []CreatePostTxParams, indexes []int, slugs []string) error {
+	stmt, err := q.db.PrepareContext(ctx, pq.CopyIn("posts", importColumns...))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for n, i := range indexes {
+		post := posts[i]
+		_, err := stmt.ExecContext(ctx,
+			post.Title,
+			post.Content,
+			post.PublishAt,
+			post.AuthorID,
+			post.CategoryID,
+			slugs[n],
+			post.ContentFormat,
+			post.ContentHtml,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the final exec without values flushes the copied rows and reports whether they were inserted
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return err
+	}
+	return stmt.Close()
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/require"
+	"promova-test-task/util"
+	"testing"
+)
+
+func TestImportPostsTx(t *testing.T) {
+	store := NewStore(testDB)
+	existingPost := populateDBWithValidRandomPost(t)
+	title := "Import " + faker.UUIDDigit()
+
+	posts := []CreatePostTxParams{
+		{
+			CreatePostParams: CreatePostParams{Title: title, Content: faker.Paragraph(), ContentFormat: ContentFormatPlain},
+			Tags:             []string{"import", "go"},
+		},
+		{
+			CreatePostParams: CreatePostParams{Title: existingPost.Title, Content: existingPost.Content, ContentFormat: ContentFormatPlain},
+		},
+		{
+			CreatePostParams: CreatePostParams{Title: title, Content: faker.Paragraph(), ContentFormat: ContentFormatMarkdown},
+		},
+	}
+	results, err := store.ImportPostsTx(context.Background(), posts)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.True(t, results[1].Duplicate)
+	require.Zero(t, results[1].ID)
+
+	base := util.Slugify(title)
+	for i, slug := range map[int]string{0: base, 2: base + "-2"} {
+		require.False(t, results[i].Duplicate)
+		post, err := testQueries.GetPostById(context.Background(), results[i].ID)
+		require.NoError(t, err)
+		require.Equal(t, slug, post.Slug)
+		require.Equal(t, posts[i].Content, post.Content)
+		require.Equal(t, posts[i].ContentFormat, post.ContentFormat)
+	}
+
+	tags, err := testQueries.GetTagsByPostIds(context.Background(), []int32{results[0].ID, results[2].ID})
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+
+	// importing the same posts again only finds duplicates
+	results, err = store.ImportPostsTx(context.Background(), posts)
+	require.NoError(t, err)
+	for _, result := range results {
+		require.True(t, result.Duplicate)
+	}
+}
+
+func TestImportPostsTx_Concurrent(t *testing.T) {
+	store := NewStore(testDB)
+	posts := []CreatePostTxParams{
+		{CreatePostParams: CreatePostParams{Title: "Import " + faker.UUIDDigit(), Content: faker.Paragraph(), ContentFormat: ContentFormatPlain}},
+	}
+
+	// the imports both look for duplicates before either inserts, the one retried has to find the post of the other
+	n := 3
+	results := make(chan []ImportPostResult)
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			result, err := store.ImportPostsTx(context.Background(), posts)
+			errs <- err
+			results <- result
+		}()
+	}
+
+	inserted := 0
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+		result := <-results
+		require.Len(t, result, 1)
+		if !result[0].Duplicate {
+			inserted++
+		}
+	}
+	require.Equal(t, 1, inserted)
+}
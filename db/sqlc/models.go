@@ -5,13 +5,346 @@
 package db
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
 	"time"
 )
 
-type Post struct {
+type CommentStatus string
+
+const (
+	CommentStatusPending  CommentStatus = "pending"
+	CommentStatusApproved CommentStatus = "approved"
+	CommentStatusRejected CommentStatus = "rejected"
+)
+
+func (e *CommentStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CommentStatus(s)
+	case string:
+		*e = CommentStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CommentStatus: %T", src)
+	}
+	return nil
+}
+
+type NullCommentStatus struct {
+	CommentStatus CommentStatus
+	Valid         bool // Valid is true if CommentStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCommentStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.CommentStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CommentStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCommentStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CommentStatus), nil
+}
+
+type ContentFormat string
+
+const (
+	ContentFormatPlain    ContentFormat = "plain"
+	ContentFormatMarkdown ContentFormat = "markdown"
+	ContentFormatHtml     ContentFormat = "html"
+)
+
+func (e *ContentFormat) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ContentFormat(s)
+	case string:
+		*e = ContentFormat(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ContentFormat: %T", src)
+	}
+	return nil
+}
+
+type NullContentFormat struct {
+	ContentFormat ContentFormat
+	Valid         bool // Valid is true if ContentFormat is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullContentFormat) Scan(value interface{}) error {
+	if value == nil {
+		ns.ContentFormat, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ContentFormat.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullContentFormat) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ContentFormat), nil
+}
+
+type CoverStatus string
+
+const (
+	CoverStatusPending    CoverStatus = "pending"
+	CoverStatusProcessing CoverStatus = "processing"
+	CoverStatusReady      CoverStatus = "ready"
+	CoverStatusFailed     CoverStatus = "failed"
+)
+
+func (e *CoverStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CoverStatus(s)
+	case string:
+		*e = CoverStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CoverStatus: %T", src)
+	}
+	return nil
+}
+
+type NullCoverStatus struct {
+	CoverStatus CoverStatus
+	Valid       bool // Valid is true if CoverStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCoverStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.CoverStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CoverStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCoverStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CoverStatus), nil
+}
+
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "draft"
+	PostStatusPublished PostStatus = "published"
+	PostStatusArchived  PostStatus = "archived"
+)
+
+func (e *PostStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PostStatus(s)
+	case string:
+		*e = PostStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PostStatus: %T", src)
+	}
+	return nil
+}
+
+type NullPostStatus struct {
+	PostStatus PostStatus
+	Valid      bool // Valid is true if PostStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPostStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.PostStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PostStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPostStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PostStatus), nil
+}
+
+type ReactionType string
+
+const (
+	ReactionTypeLike  ReactionType = "like"
+	ReactionTypeLove  ReactionType = "love"
+	ReactionTypeLaugh ReactionType = "laugh"
+	ReactionTypeWow   ReactionType = "wow"
+	ReactionTypeSad   ReactionType = "sad"
+	ReactionTypeAngry ReactionType = "angry"
+)
+
+func (e *ReactionType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ReactionType(s)
+	case string:
+		*e = ReactionType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ReactionType: %T", src)
+	}
+	return nil
+}
+
+type NullReactionType struct {
+	ReactionType ReactionType
+	Valid        bool // Valid is true if ReactionType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullReactionType) Scan(value interface{}) error {
+	if value == nil {
+		ns.ReactionType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ReactionType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullReactionType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ReactionType), nil
+}
+
+type Attachment struct {
+	ID          int32     `json:"id"`
+	PostID      int32     `json:"post_id"`
+	BlobKey     string    `json:"blob_key"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Author struct {
 	ID        int32     `json:"id"`
-	Title     string    `json:"title"`
-	Content   string    `json:"content"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Bio       string    `json:"bio"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+type Category struct {
+	ID        int32         `json:"id"`
+	ParentID  sql.NullInt32 `json:"parent_id"`
+	Name      string        `json:"name"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type Comment struct {
+	ID         int32         `json:"id"`
+	PostID     int32         `json:"post_id"`
+	ParentID   sql.NullInt32 `json:"parent_id"`
+	AuthorName string        `json:"author_name"`
+	Body       string        `json:"body"`
+	Status     CommentStatus `json:"status"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type Post struct {
+	ID            int32         `json:"id"`
+	Title         string        `json:"title"`
+	Content       string        `json:"content"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	SearchVector  interface{}   `json:"search_vector"`
+	Status        PostStatus    `json:"status"`
+	PublishedAt   sql.NullTime  `json:"published_at"`
+	PublishAt     sql.NullTime  `json:"publish_at"`
+	DeletedAt     sql.NullTime  `json:"deleted_at"`
+	Version       int32         `json:"version"`
+	AuthorID      sql.NullInt32 `json:"author_id"`
+	CategoryID    sql.NullInt32 `json:"category_id"`
+	Slug          string        `json:"slug"`
+	ContentFormat ContentFormat `json:"content_format"`
+	ContentHtml   string        `json:"content_html"`
+}
+
+type PostCover struct {
+	PostID       int32          `json:"post_id"`
+	BlobKey      string         `json:"blob_key"`
+	ContentType  string         `json:"content_type"`
+	Width        int32          `json:"width"`
+	Height       int32          `json:"height"`
+	Status       CoverStatus    `json:"status"`
+	ThumbnailKey sql.NullString `json:"thumbnail_key"`
+	MediumKey    sql.NullString `json:"medium_key"`
+	LargeKey     sql.NullString `json:"large_key"`
+	ClaimedAt    sql.NullTime   `json:"claimed_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+type PostReaction struct {
+	PostID    int32        `json:"post_id"`
+	ClientID  string       `json:"client_id"`
+	Type      ReactionType `json:"type"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type PostReactionCounter struct {
+	PostID int32        `json:"post_id"`
+	Type   ReactionType `json:"type"`
+	Count  int32        `json:"count"`
+}
+
+type PostRevision struct {
+	ID            int32         `json:"id"`
+	PostID        int32         `json:"post_id"`
+	Revision      int32         `json:"revision"`
+	Title         string        `json:"title"`
+	Content       string        `json:"content"`
+	CreatedAt     time.Time     `json:"created_at"`
+	ContentFormat ContentFormat `json:"content_format"`
+}
+
+type PostSlugAlias struct {
+	Slug      string    `json:"slug"`
+	PostID    int32     `json:"post_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PostTag struct {
+	PostID int32 `json:"post_id"`
+	TagID  int32 `json:"tag_id"`
+}
+
+type PostViewBucket struct {
+	Bucket time.Time `json:"bucket"`
+	PostID int32     `json:"post_id"`
+	Count  int64     `json:"count"`
+}
+
+type PostViewCount struct {
+	PostID int32 `json:"post_id"`
+	Count  int64 `json:"count"`
+}
+
+type Tag struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+}
@@ -7,23 +7,42 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 const createPost = `-- name: CreatePost :one
 INSERT INTO posts (
-    title, content
+    title, content, publish_at, author_id, category_id, slug, content_format, content_html
 ) VALUES (
-    $1, $2
-) RETURNING id, title, content, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, title, content, created_at, updated_at, search_vector, status, published_at, publish_at, deleted_at, version, author_id, category_id, slug, content_format, content_html
 `
 
 type CreatePostParams struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	Title         string        `json:"title"`
+	Content       string        `json:"content"`
+	PublishAt     sql.NullTime  `json:"publish_at"`
+	AuthorID      sql.NullInt32 `json:"author_id"`
+	CategoryID    sql.NullInt32 `json:"category_id"`
+	Slug          string        `json:"slug"`
+	ContentFormat ContentFormat `json:"content_format"`
+	ContentHtml   string        `json:"content_html"`
 }
 
 func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
-	row := q.db.QueryRowContext(ctx, createPost, arg.Title, arg.Content)
+	row := q.db.QueryRowContext(ctx, createPost,
+		arg.Title,
+		arg.Content,
+		arg.PublishAt,
+		arg.AuthorID,
+		arg.CategoryID,
+		arg.Slug,
+		arg.ContentFormat,
+		arg.ContentHtml,
+	)
 	var i Post
 	err := row.Scan(
 		&i.ID,
@@ -31,23 +50,119 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		&i.Content,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Status,
+		&i.PublishedAt,
+		&i.PublishAt,
+		&i.DeletedAt,
+		&i.Version,
+		&i.AuthorID,
+		&i.CategoryID,
+		&i.Slug,
+		&i.ContentFormat,
+		&i.ContentHtml,
 	)
 	return i, err
 }
 
-const deletePost = `-- name: DeletePost :exec
-DELETE FROM posts
-WHERE id = $1
+const createPosts = `-- name: CreatePosts :many
+INSERT INTO posts (
+    title, content, publish_at, author_id, category_id, slug, content_format, content_html
+)
+SELECT unnest($1::text[]),
+       unnest($2::text[]),
+       unnest($3::timestamptz[]),
+       unnest($4::int[]),
+       unnest($5::int[]),
+       unnest($6::text[]),
+       unnest($7::content_format[]),
+       unnest($8::text[])
+RETURNING id, title, content, created_at, updated_at, search_vector, status, published_at, publish_at, deleted_at, version, author_id, category_id, slug, content_format, content_html
+`
+
+type CreatePostsParams struct {
+	Titles         []string        `json:"titles"`
+	Contents       []string        `json:"contents"`
+	PublishAts     []sql.NullTime  `json:"publish_ats"`
+	AuthorIds      []sql.NullInt32 `json:"author_ids"`
+	CategoryIds    []sql.NullInt32 `json:"category_ids"`
+	Slugs          []string        `json:"slugs"`
+	ContentFormats []ContentFormat `json:"content_formats"`
+	ContentHtmls   []string        `json:"content_htmls"`
+}
+
+func (q *Queries) CreatePosts(ctx context.Context, arg CreatePostsParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, createPosts,
+		pq.Array(arg.Titles),
+		pq.Array(arg.Contents),
+		pq.Array(arg.PublishAts),
+		pq.Array(arg.AuthorIds),
+		pq.Array(arg.CategoryIds),
+		pq.Array(arg.Slugs),
+		pq.Array(arg.ContentFormats),
+		pq.Array(arg.ContentHtmls),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Post{}
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Status,
+			&i.PublishedAt,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.AuthorID,
+			&i.CategoryID,
+			&i.Slug,
+			&i.ContentFormat,
+			&i.ContentHtml,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deletePost = `-- name: DeletePost :execrows
+UPDATE posts
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+  AND ($2::integer IS NULL OR version = $2)
 `
 
-func (q *Queries) DeletePost(ctx context.Context, id int32) error {
-	_, err := q.db.ExecContext(ctx, deletePost, id)
-	return err
+type DeletePostParams struct {
+	ID              int32         `json:"id"`
+	ExpectedVersion sql.NullInt32 `json:"expected_version"`
+}
+
+func (q *Queries) DeletePost(ctx context.Context, arg DeletePostParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePost, arg.ID, arg.ExpectedVersion)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const getPostById = `-- name: GetPostById :one
-SELECT id, title, content, created_at, updated_at FROM posts
-WHERE id = $1
+SELECT id, title, content, created_at, updated_at, search_vector, status, published_at, publish_at, deleted_at, version, author_id, category_id, slug, content_format, content_html FROM posts
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetPostById(ctx context.Context, id int32) (Post, error) {
@@ -59,12 +174,83 @@ func (q *Queries) GetPostById(ctx context.Context, id int32) (Post, error) {
 		&i.Content,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Status,
+		&i.PublishedAt,
+		&i.PublishAt,
+		&i.DeletedAt,
+		&i.Version,
+		&i.AuthorID,
+		&i.CategoryID,
+		&i.Slug,
+		&i.ContentFormat,
+		&i.ContentHtml,
+	)
+	return i, err
+}
+
+const getPostByIdForUpdate = `-- name: GetPostByIdForUpdate :one
+SELECT id, title, content, created_at, updated_at, search_vector, status, published_at, publish_at, deleted_at, version, author_id, category_id, slug, content_format, content_html FROM posts
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE
+`
+
+func (q *Queries) GetPostByIdForUpdate(ctx context.Context, id int32) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostByIdForUpdate, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Content,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Status,
+		&i.PublishedAt,
+		&i.PublishAt,
+		&i.DeletedAt,
+		&i.Version,
+		&i.AuthorID,
+		&i.CategoryID,
+		&i.Slug,
+		&i.ContentFormat,
+		&i.ContentHtml,
+	)
+	return i, err
+}
+
+const getPostBySlug = `-- name: GetPostBySlug :one
+SELECT id, title, content, created_at, updated_at, search_vector, status, published_at, publish_at, deleted_at, version, author_id, category_id, slug, content_format, content_html FROM posts
+WHERE slug = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) GetPostBySlug(ctx context.Context, slug string) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostBySlug, slug)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Content,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Status,
+		&i.PublishedAt,
+		&i.PublishAt,
+		&i.DeletedAt,
+		&i.Version,
+		&i.AuthorID,
+		&i.CategoryID,
+		&i.Slug,
+		&i.ContentFormat,
+		&i.ContentHtml,
 	)
 	return i, err
 }
 
 const getPosts = `-- name: GetPosts :many
-SELECT id, title, content, created_at, updated_at FROM posts
+SELECT id, title, content, created_at, updated_at, search_vector, status, published_at, publish_at, deleted_at, version, author_id, category_id, slug, content_format, content_html FROM posts
+WHERE deleted_at IS NULL
 ORDER BY id
 `
 
@@ -83,6 +269,17 @@ func (q *Queries) GetPosts(ctx context.Context) ([]Post, error) {
 			&i.Content,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Status,
+			&i.PublishedAt,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.AuthorID,
+			&i.CategoryID,
+			&i.Slug,
+			&i.ContentFormat,
+			&i.ContentHtml,
 		); err != nil {
 			return nil, err
 		}
@@ -97,21 +294,1340 @@ func (q *Queries) GetPosts(ctx context.Context) ([]Post, error) {
 	return items, nil
 }
 
-const updatePostById = `-- name: UpdatePostById :one
-UPDATE posts
-SET title = $1, content = $2
-WHERE id = $3
-RETURNING id, title, content, created_at, updated_at
+const listDeletedPosts = `-- name: ListDeletedPosts :many
+SELECT id, title, content, created_at, updated_at, search_vector, status, published_at, publish_at, deleted_at, version, author_id, category_id, slug, content_format, content_html FROM posts
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id DESC
+LIMIT $1
+OFFSET $2
 `
 
-type UpdatePostByIdParams struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	ID      int32  `json:"id"`
+type ListDeletedPostsParams struct {
+	PageSize   int32 `json:"page_size"`
+	PageOffset int32 `json:"page_offset"`
 }
 
-func (q *Queries) UpdatePostById(ctx context.Context, arg UpdatePostByIdParams) (Post, error) {
-	row := q.db.QueryRowContext(ctx, updatePostById, arg.Title, arg.Content, arg.ID)
+func (q *Queries) ListDeletedPosts(ctx context.Context, arg ListDeletedPostsParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listDeletedPosts, arg.PageSize, arg.PageOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Post{}
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Status,
+			&i.PublishedAt,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.AuthorID,
+			&i.CategoryID,
+			&i.Slug,
+			&i.ContentFormat,
+			&i.ContentHtml,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostsByID = `-- name: ListPostsByID :many
+SELECT posts.id, posts.title, posts.content, posts.created_at, posts.updated_at, posts.search_vector, posts.status, posts.published_at, posts.publish_at, posts.deleted_at, posts.version, posts.author_id, posts.category_id, posts.slug, posts.content_format, posts.content_html FROM posts
+WHERE ($1::timestamptz IS NULL OR created_at > $1)
+  AND ($2::timestamptz IS NULL OR created_at < $2)
+  AND ($3::timestamptz IS NULL OR updated_at >= $3)
+  AND ($4::text IS NULL OR title ILIKE '%' || $4 || '%')
+  AND status = $5
+  AND ($6::int IS NULL OR author_id = $6)
+  AND (
+    coalesce(cardinality($7::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY($7)
+    ) >= CASE WHEN $8::text = 'all' THEN cardinality($7) ELSE 1 END
+  )
+  AND (
+    $9::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = $9
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND ($10::int IS NULL OR id > $10)
+ORDER BY id
+LIMIT $11
+`
+
+type ListPostsByIDParams struct {
+	CreatedAfter  sql.NullTime   `json:"created_after"`
+	CreatedBefore sql.NullTime   `json:"created_before"`
+	UpdatedSince  sql.NullTime   `json:"updated_since"`
+	TitleContains sql.NullString `json:"title_contains"`
+	Status        PostStatus     `json:"status"`
+	AuthorID      sql.NullInt32  `json:"author_id"`
+	Tags          []string       `json:"tags"`
+	TagMatch      string         `json:"tag_match"`
+	CategoryID    sql.NullInt32  `json:"category_id"`
+	CursorID      sql.NullInt32  `json:"cursor_id"`
+	PageSize      int32          `json:"page_size"`
+}
+
+func (q *Queries) ListPostsByID(ctx context.Context, arg ListPostsByIDParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listPostsByID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedSince,
+		arg.TitleContains,
+		arg.Status,
+		arg.AuthorID,
+		pq.Array(arg.Tags),
+		arg.TagMatch,
+		arg.CategoryID,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Post{}
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Status,
+			&i.PublishedAt,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.AuthorID,
+			&i.CategoryID,
+			&i.Slug,
+			&i.ContentFormat,
+			&i.ContentHtml,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostsByIDDesc = `-- name: ListPostsByIDDesc :many
+SELECT posts.id, posts.title, posts.content, posts.created_at, posts.updated_at, posts.search_vector, posts.status, posts.published_at, posts.publish_at, posts.deleted_at, posts.version, posts.author_id, posts.category_id, posts.slug, posts.content_format, posts.content_html FROM posts
+WHERE ($1::timestamptz IS NULL OR created_at > $1)
+  AND ($2::timestamptz IS NULL OR created_at < $2)
+  AND ($3::timestamptz IS NULL OR updated_at >= $3)
+  AND ($4::text IS NULL OR title ILIKE '%' || $4 || '%')
+  AND status = $5
+  AND ($6::int IS NULL OR author_id = $6)
+  AND (
+    coalesce(cardinality($7::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY($7)
+    ) >= CASE WHEN $8::text = 'all' THEN cardinality($7) ELSE 1 END
+  )
+  AND (
+    $9::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = $9
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND ($10::int IS NULL OR id < $10)
+ORDER BY id DESC
+LIMIT $11
+`
+
+type ListPostsByIDDescParams struct {
+	CreatedAfter  sql.NullTime   `json:"created_after"`
+	CreatedBefore sql.NullTime   `json:"created_before"`
+	UpdatedSince  sql.NullTime   `json:"updated_since"`
+	TitleContains sql.NullString `json:"title_contains"`
+	Status        PostStatus     `json:"status"`
+	AuthorID      sql.NullInt32  `json:"author_id"`
+	Tags          []string       `json:"tags"`
+	TagMatch      string         `json:"tag_match"`
+	CategoryID    sql.NullInt32  `json:"category_id"`
+	CursorID      sql.NullInt32  `json:"cursor_id"`
+	PageSize      int32          `json:"page_size"`
+}
+
+func (q *Queries) ListPostsByIDDesc(ctx context.Context, arg ListPostsByIDDescParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listPostsByIDDesc,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedSince,
+		arg.TitleContains,
+		arg.Status,
+		arg.AuthorID,
+		pq.Array(arg.Tags),
+		arg.TagMatch,
+		arg.CategoryID,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Post{}
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Status,
+			&i.PublishedAt,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.AuthorID,
+			&i.CategoryID,
+			&i.Slug,
+			&i.ContentFormat,
+			&i.ContentHtml,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostsByCreatedAt = `-- name: ListPostsByCreatedAt :many
+SELECT posts.id, posts.title, posts.content, posts.created_at, posts.updated_at, posts.search_vector, posts.status, posts.published_at, posts.publish_at, posts.deleted_at, posts.version, posts.author_id, posts.category_id, posts.slug, posts.content_format, posts.content_html FROM posts
+WHERE ($1::timestamptz IS NULL OR created_at > $1)
+  AND ($2::timestamptz IS NULL OR created_at < $2)
+  AND ($3::timestamptz IS NULL OR updated_at >= $3)
+  AND ($4::text IS NULL OR title ILIKE '%' || $4 || '%')
+  AND status = $5
+  AND ($6::int IS NULL OR author_id = $6)
+  AND (
+    coalesce(cardinality($7::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY($7)
+    ) >= CASE WHEN $8::text = 'all' THEN cardinality($7) ELSE 1 END
+  )
+  AND (
+    $9::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = $9
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND ($10::int IS NULL OR (created_at, id) > ($11::timestamptz, $10))
+ORDER BY created_at, id
+LIMIT $12
+`
+
+type ListPostsByCreatedAtParams struct {
+	CreatedAfter  sql.NullTime   `json:"created_after"`
+	CreatedBefore sql.NullTime   `json:"created_before"`
+	UpdatedSince  sql.NullTime   `json:"updated_since"`
+	TitleContains sql.NullString `json:"title_contains"`
+	Status        PostStatus     `json:"status"`
+	AuthorID      sql.NullInt32  `json:"author_id"`
+	Tags          []string       `json:"tags"`
+	TagMatch      string         `json:"tag_match"`
+	CategoryID    sql.NullInt32  `json:"category_id"`
+	CursorID      sql.NullInt32  `json:"cursor_id"`
+	CursorTime    sql.NullTime   `json:"cursor_time"`
+	PageSize      int32          `json:"page_size"`
+}
+
+func (q *Queries) ListPostsByCreatedAt(ctx context.Context, arg ListPostsByCreatedAtParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listPostsByCreatedAt,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedSince,
+		arg.TitleContains,
+		arg.Status,
+		arg.AuthorID,
+		pq.Array(arg.Tags),
+		arg.TagMatch,
+		arg.CategoryID,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Post{}
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Status,
+			&i.PublishedAt,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.AuthorID,
+			&i.CategoryID,
+			&i.Slug,
+			&i.ContentFormat,
+			&i.ContentHtml,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostsByCreatedAtDesc = `-- name: ListPostsByCreatedAtDesc :many
+SELECT posts.id, posts.title, posts.content, posts.created_at, posts.updated_at, posts.search_vector, posts.status, posts.published_at, posts.publish_at, posts.deleted_at, posts.version, posts.author_id, posts.category_id, posts.slug, posts.content_format, posts.content_html FROM posts
+WHERE ($1::timestamptz IS NULL OR created_at > $1)
+  AND ($2::timestamptz IS NULL OR created_at < $2)
+  AND ($3::timestamptz IS NULL OR updated_at >= $3)
+  AND ($4::text IS NULL OR title ILIKE '%' || $4 || '%')
+  AND status = $5
+  AND ($6::int IS NULL OR author_id = $6)
+  AND (
+    coalesce(cardinality($7::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY($7)
+    ) >= CASE WHEN $8::text = 'all' THEN cardinality($7) ELSE 1 END
+  )
+  AND (
+    $9::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = $9
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND ($10::int IS NULL OR (created_at, id) < ($11::timestamptz, $10))
+ORDER BY created_at DESC, id DESC
+LIMIT $12
+`
+
+type ListPostsByCreatedAtDescParams struct {
+	CreatedAfter  sql.NullTime   `json:"created_after"`
+	CreatedBefore sql.NullTime   `json:"created_before"`
+	UpdatedSince  sql.NullTime   `json:"updated_since"`
+	TitleContains sql.NullString `json:"title_contains"`
+	Status        PostStatus     `json:"status"`
+	AuthorID      sql.NullInt32  `json:"author_id"`
+	Tags          []string       `json:"tags"`
+	TagMatch      string         `json:"tag_match"`
+	CategoryID    sql.NullInt32  `json:"category_id"`
+	CursorID      sql.NullInt32  `json:"cursor_id"`
+	CursorTime    sql.NullTime   `json:"cursor_time"`
+	PageSize      int32          `json:"page_size"`
+}
+
+func (q *Queries) ListPostsByCreatedAtDesc(ctx context.Context, arg ListPostsByCreatedAtDescParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listPostsByCreatedAtDesc,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedSince,
+		arg.TitleContains,
+		arg.Status,
+		arg.AuthorID,
+		pq.Array(arg.Tags),
+		arg.TagMatch,
+		arg.CategoryID,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Post{}
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Status,
+			&i.PublishedAt,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.AuthorID,
+			&i.CategoryID,
+			&i.Slug,
+			&i.ContentFormat,
+			&i.ContentHtml,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostsByUpdatedAt = `-- name: ListPostsByUpdatedAt :many
+SELECT posts.id, posts.title, posts.content, posts.created_at, posts.updated_at, posts.search_vector, posts.status, posts.published_at, posts.publish_at, posts.deleted_at, posts.version, posts.author_id, posts.category_id, posts.slug, posts.content_format, posts.content_html FROM posts
+WHERE ($1::timestamptz IS NULL OR created_at > $1)
+  AND ($2::timestamptz IS NULL OR created_at < $2)
+  AND ($3::timestamptz IS NULL OR updated_at >= $3)
+  AND ($4::text IS NULL OR title ILIKE '%' || $4 || '%')
+  AND status = $5
+  AND ($6::int IS NULL OR author_id = $6)
+  AND (
+    coalesce(cardinality($7::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY($7)
+    ) >= CASE WHEN $8::text = 'all' THEN cardinality($7) ELSE 1 END
+  )
+  AND (
+    $9::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = $9
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND ($10::int IS NULL OR (updated_at, id) > ($11::timestamptz, $10))
+ORDER BY updated_at, id
+LIMIT $12
+`
+
+type ListPostsByUpdatedAtParams struct {
+	CreatedAfter  sql.NullTime   `json:"created_after"`
+	CreatedBefore sql.NullTime   `json:"created_before"`
+	UpdatedSince  sql.NullTime   `json:"updated_since"`
+	TitleContains sql.NullString `json:"title_contains"`
+	Status        PostStatus     `json:"status"`
+	AuthorID      sql.NullInt32  `json:"author_id"`
+	Tags          []string       `json:"tags"`
+	TagMatch      string         `json:"tag_match"`
+	CategoryID    sql.NullInt32  `json:"category_id"`
+	CursorID      sql.NullInt32  `json:"cursor_id"`
+	CursorTime    sql.NullTime   `json:"cursor_time"`
+	PageSize      int32          `json:"page_size"`
+}
+
+func (q *Queries) ListPostsByUpdatedAt(ctx context.Context, arg ListPostsByUpdatedAtParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listPostsByUpdatedAt,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedSince,
+		arg.TitleContains,
+		arg.Status,
+		arg.AuthorID,
+		pq.Array(arg.Tags),
+		arg.TagMatch,
+		arg.CategoryID,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Post{}
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Status,
+			&i.PublishedAt,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.AuthorID,
+			&i.CategoryID,
+			&i.Slug,
+			&i.ContentFormat,
+			&i.ContentHtml,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostsByUpdatedAtDesc = `-- name: ListPostsByUpdatedAtDesc :many
+SELECT posts.id, posts.title, posts.content, posts.created_at, posts.updated_at, posts.search_vector, posts.status, posts.published_at, posts.publish_at, posts.deleted_at, posts.version, posts.author_id, posts.category_id, posts.slug, posts.content_format, posts.content_html FROM posts
+WHERE ($1::timestamptz IS NULL OR created_at > $1)
+  AND ($2::timestamptz IS NULL OR created_at < $2)
+  AND ($3::timestamptz IS NULL OR updated_at >= $3)
+  AND ($4::text IS NULL OR title ILIKE '%' || $4 || '%')
+  AND status = $5
+  AND ($6::int IS NULL OR author_id = $6)
+  AND (
+    coalesce(cardinality($7::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY($7)
+    ) >= CASE WHEN $8::text = 'all' THEN cardinality($7) ELSE 1 END
+  )
+  AND (
+    $9::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = $9
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND ($10::int IS NULL OR (updated_at, id) < ($11::timestamptz, $10))
+ORDER BY updated_at DESC, id DESC
+LIMIT $12
+`
+
+type ListPostsByUpdatedAtDescParams struct {
+	CreatedAfter  sql.NullTime   `json:"created_after"`
+	CreatedBefore sql.NullTime   `json:"created_before"`
+	UpdatedSince  sql.NullTime   `json:"updated_since"`
+	TitleContains sql.NullString `json:"title_contains"`
+	Status        PostStatus     `json:"status"`
+	AuthorID      sql.NullInt32  `json:"author_id"`
+	Tags          []string       `json:"tags"`
+	TagMatch      string         `json:"tag_match"`
+	CategoryID    sql.NullInt32  `json:"category_id"`
+	CursorID      sql.NullInt32  `json:"cursor_id"`
+	CursorTime    sql.NullTime   `json:"cursor_time"`
+	PageSize      int32          `json:"page_size"`
+}
+
+func (q *Queries) ListPostsByUpdatedAtDesc(ctx context.Context, arg ListPostsByUpdatedAtDescParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listPostsByUpdatedAtDesc,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedSince,
+		arg.TitleContains,
+		arg.Status,
+		arg.AuthorID,
+		pq.Array(arg.Tags),
+		arg.TagMatch,
+		arg.CategoryID,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Post{}
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Status,
+			&i.PublishedAt,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.AuthorID,
+			&i.CategoryID,
+			&i.Slug,
+			&i.ContentFormat,
+			&i.ContentHtml,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostsByTitle = `-- name: ListPostsByTitle :many
+SELECT posts.id, posts.title, posts.content, posts.created_at, posts.updated_at, posts.search_vector, posts.status, posts.published_at, posts.publish_at, posts.deleted_at, posts.version, posts.author_id, posts.category_id, posts.slug, posts.content_format, posts.content_html FROM posts
+WHERE ($1::timestamptz IS NULL OR created_at > $1)
+  AND ($2::timestamptz IS NULL OR created_at < $2)
+  AND ($3::timestamptz IS NULL OR updated_at >= $3)
+  AND ($4::text IS NULL OR title ILIKE '%' || $4 || '%')
+  AND status = $5
+  AND ($6::int IS NULL OR author_id = $6)
+  AND (
+    coalesce(cardinality($7::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY($7)
+    ) >= CASE WHEN $8::text = 'all' THEN cardinality($7) ELSE 1 END
+  )
+  AND (
+    $9::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = $9
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND ($10::int IS NULL OR (title, id) > ($11::text, $10))
+ORDER BY title, id
+LIMIT $12
+`
+
+type ListPostsByTitleParams struct {
+	CreatedAfter  sql.NullTime   `json:"created_after"`
+	CreatedBefore sql.NullTime   `json:"created_before"`
+	UpdatedSince  sql.NullTime   `json:"updated_since"`
+	TitleContains sql.NullString `json:"title_contains"`
+	Status        PostStatus     `json:"status"`
+	AuthorID      sql.NullInt32  `json:"author_id"`
+	Tags          []string       `json:"tags"`
+	TagMatch      string         `json:"tag_match"`
+	CategoryID    sql.NullInt32  `json:"category_id"`
+	CursorID      sql.NullInt32  `json:"cursor_id"`
+	CursorTitle   sql.NullString `json:"cursor_title"`
+	PageSize      int32          `json:"page_size"`
+}
+
+func (q *Queries) ListPostsByTitle(ctx context.Context, arg ListPostsByTitleParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listPostsByTitle,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedSince,
+		arg.TitleContains,
+		arg.Status,
+		arg.AuthorID,
+		pq.Array(arg.Tags),
+		arg.TagMatch,
+		arg.CategoryID,
+		arg.CursorID,
+		arg.CursorTitle,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Post{}
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Status,
+			&i.PublishedAt,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.AuthorID,
+			&i.CategoryID,
+			&i.Slug,
+			&i.ContentFormat,
+			&i.ContentHtml,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostsByTitleDesc = `-- name: ListPostsByTitleDesc :many
+SELECT posts.id, posts.title, posts.content, posts.created_at, posts.updated_at, posts.search_vector, posts.status, posts.published_at, posts.publish_at, posts.deleted_at, posts.version, posts.author_id, posts.category_id, posts.slug, posts.content_format, posts.content_html FROM posts
+WHERE ($1::timestamptz IS NULL OR created_at > $1)
+  AND ($2::timestamptz IS NULL OR created_at < $2)
+  AND ($3::timestamptz IS NULL OR updated_at >= $3)
+  AND ($4::text IS NULL OR title ILIKE '%' || $4 || '%')
+  AND status = $5
+  AND ($6::int IS NULL OR author_id = $6)
+  AND (
+    coalesce(cardinality($7::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY($7)
+    ) >= CASE WHEN $8::text = 'all' THEN cardinality($7) ELSE 1 END
+  )
+  AND (
+    $9::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = $9
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND ($10::int IS NULL OR (title, id) < ($11::text, $10))
+ORDER BY title DESC, id DESC
+LIMIT $12
+`
+
+type ListPostsByTitleDescParams struct {
+	CreatedAfter  sql.NullTime   `json:"created_after"`
+	CreatedBefore sql.NullTime   `json:"created_before"`
+	UpdatedSince  sql.NullTime   `json:"updated_since"`
+	TitleContains sql.NullString `json:"title_contains"`
+	Status        PostStatus     `json:"status"`
+	AuthorID      sql.NullInt32  `json:"author_id"`
+	Tags          []string       `json:"tags"`
+	TagMatch      string         `json:"tag_match"`
+	CategoryID    sql.NullInt32  `json:"category_id"`
+	CursorID      sql.NullInt32  `json:"cursor_id"`
+	CursorTitle   sql.NullString `json:"cursor_title"`
+	PageSize      int32          `json:"page_size"`
+}
+
+func (q *Queries) ListPostsByTitleDesc(ctx context.Context, arg ListPostsByTitleDescParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listPostsByTitleDesc,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedSince,
+		arg.TitleContains,
+		arg.Status,
+		arg.AuthorID,
+		pq.Array(arg.Tags),
+		arg.TagMatch,
+		arg.CategoryID,
+		arg.CursorID,
+		arg.CursorTitle,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Post{}
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Status,
+			&i.PublishedAt,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.AuthorID,
+			&i.CategoryID,
+			&i.Slug,
+			&i.ContentFormat,
+			&i.ContentHtml,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostsByLikes = `-- name: ListPostsByLikes :many
+SELECT posts.id, posts.title, posts.content, posts.created_at, posts.updated_at, posts.search_vector, posts.status, posts.published_at, posts.publish_at, posts.deleted_at, posts.version, posts.author_id, posts.category_id, posts.slug, posts.content_format, posts.content_html FROM posts
+LEFT JOIN post_reaction_counters likes ON likes.post_id = posts.id AND likes.type = 'like'
+WHERE ($1::timestamptz IS NULL OR created_at > $1)
+  AND ($2::timestamptz IS NULL OR created_at < $2)
+  AND ($3::timestamptz IS NULL OR updated_at >= $3)
+  AND ($4::text IS NULL OR title ILIKE '%' || $4 || '%')
+  AND status = $5
+  AND ($6::int IS NULL OR author_id = $6)
+  AND (
+    coalesce(cardinality($7::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY($7)
+    ) >= CASE WHEN $8::text = 'all' THEN cardinality($7) ELSE 1 END
+  )
+  AND (
+    $9::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = $9
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND ($10::int IS NULL OR (coalesce(likes.count, 0), id) > ($11::int, $10))
+ORDER BY coalesce(likes.count, 0), id
+LIMIT $12
+`
+
+type ListPostsByLikesParams struct {
+	CreatedAfter  sql.NullTime   `json:"created_after"`
+	CreatedBefore sql.NullTime   `json:"created_before"`
+	UpdatedSince  sql.NullTime   `json:"updated_since"`
+	TitleContains sql.NullString `json:"title_contains"`
+	Status        PostStatus     `json:"status"`
+	AuthorID      sql.NullInt32  `json:"author_id"`
+	Tags          []string       `json:"tags"`
+	TagMatch      string         `json:"tag_match"`
+	CategoryID    sql.NullInt32  `json:"category_id"`
+	CursorID      sql.NullInt32  `json:"cursor_id"`
+	CursorLikes   sql.NullInt32  `json:"cursor_likes"`
+	PageSize      int32          `json:"page_size"`
+}
+
+func (q *Queries) ListPostsByLikes(ctx context.Context, arg ListPostsByLikesParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listPostsByLikes,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedSince,
+		arg.TitleContains,
+		arg.Status,
+		arg.AuthorID,
+		pq.Array(arg.Tags),
+		arg.TagMatch,
+		arg.CategoryID,
+		arg.CursorID,
+		arg.CursorLikes,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Post{}
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Status,
+			&i.PublishedAt,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.AuthorID,
+			&i.CategoryID,
+			&i.Slug,
+			&i.ContentFormat,
+			&i.ContentHtml,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostsByLikesDesc = `-- name: ListPostsByLikesDesc :many
+SELECT posts.id, posts.title, posts.content, posts.created_at, posts.updated_at, posts.search_vector, posts.status, posts.published_at, posts.publish_at, posts.deleted_at, posts.version, posts.author_id, posts.category_id, posts.slug, posts.content_format, posts.content_html FROM posts
+LEFT JOIN post_reaction_counters likes ON likes.post_id = posts.id AND likes.type = 'like'
+WHERE ($1::timestamptz IS NULL OR created_at > $1)
+  AND ($2::timestamptz IS NULL OR created_at < $2)
+  AND ($3::timestamptz IS NULL OR updated_at >= $3)
+  AND ($4::text IS NULL OR title ILIKE '%' || $4 || '%')
+  AND status = $5
+  AND ($6::int IS NULL OR author_id = $6)
+  AND (
+    coalesce(cardinality($7::text[]), 0) = 0
+    OR (
+      SELECT count(*) FROM post_tags
+      JOIN tags ON tags.id = post_tags.tag_id
+      WHERE post_tags.post_id = posts.id AND tags.name = ANY($7)
+    ) >= CASE WHEN $8::text = 'all' THEN cardinality($7) ELSE 1 END
+  )
+  AND (
+    $9::int IS NULL
+    OR category_id IN (
+      WITH RECURSIVE subtree AS (
+          SELECT categories.id FROM categories WHERE categories.id = $9
+        UNION ALL
+          SELECT child.id FROM categories child JOIN subtree ON child.parent_id = subtree.id
+      )
+      SELECT subtree.id FROM subtree
+    )
+  )
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+  AND ($10::int IS NULL OR (coalesce(likes.count, 0), id) < ($11::int, $10))
+ORDER BY coalesce(likes.count, 0) DESC, id DESC
+LIMIT $12
+`
+
+type ListPostsByLikesDescParams struct {
+	CreatedAfter  sql.NullTime   `json:"created_after"`
+	CreatedBefore sql.NullTime   `json:"created_before"`
+	UpdatedSince  sql.NullTime   `json:"updated_since"`
+	TitleContains sql.NullString `json:"title_contains"`
+	Status        PostStatus     `json:"status"`
+	AuthorID      sql.NullInt32  `json:"author_id"`
+	Tags          []string       `json:"tags"`
+	TagMatch      string         `json:"tag_match"`
+	CategoryID    sql.NullInt32  `json:"category_id"`
+	CursorID      sql.NullInt32  `json:"cursor_id"`
+	CursorLikes   sql.NullInt32  `json:"cursor_likes"`
+	PageSize      int32          `json:"page_size"`
+}
+
+func (q *Queries) ListPostsByLikesDesc(ctx context.Context, arg ListPostsByLikesDescParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, listPostsByLikesDesc,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedSince,
+		arg.TitleContains,
+		arg.Status,
+		arg.AuthorID,
+		pq.Array(arg.Tags),
+		arg.TagMatch,
+		arg.CategoryID,
+		arg.CursorID,
+		arg.CursorLikes,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Post{}
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Status,
+			&i.PublishedAt,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.AuthorID,
+			&i.CategoryID,
+			&i.Slug,
+			&i.ContentFormat,
+			&i.ContentHtml,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const publishDuePosts = `-- name: PublishDuePosts :many
+UPDATE posts
+SET status = 'published', published_at = publish_at, publish_at = NULL
+WHERE id IN (
+    SELECT id FROM posts
+    WHERE publish_at <= $1 AND status <> 'archived' AND deleted_at IS NULL
+    ORDER BY publish_at
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, title, content, created_at, updated_at, search_vector, status, published_at, publish_at, deleted_at, version, author_id, category_id, slug, content_format, content_html
+`
+
+type PublishDuePostsParams struct {
+	Now       time.Time `json:"now"`
+	BatchSize int32     `json:"batch_size"`
+}
+
+func (q *Queries) PublishDuePosts(ctx context.Context, arg PublishDuePostsParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, publishDuePosts, arg.Now, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Post{}
+	for rows.Next() {
+		var i Post
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Status,
+			&i.PublishedAt,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.AuthorID,
+			&i.CategoryID,
+			&i.Slug,
+			&i.ContentFormat,
+			&i.ContentHtml,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgePost = `-- name: PurgePost :execrows
+DELETE FROM posts
+WHERE id = $1
+`
+
+func (q *Queries) PurgePost(ctx context.Context, id int32) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgePost, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restorePost = `-- name: RestorePost :one
+UPDATE posts
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, title, content, created_at, updated_at, search_vector, status, published_at, publish_at, deleted_at, version, author_id, category_id, slug, content_format, content_html
+`
+
+func (q *Queries) RestorePost(ctx context.Context, id int32) (Post, error) {
+	row := q.db.QueryRowContext(ctx, restorePost, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Content,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Status,
+		&i.PublishedAt,
+		&i.PublishAt,
+		&i.DeletedAt,
+		&i.Version,
+		&i.AuthorID,
+		&i.CategoryID,
+		&i.Slug,
+		&i.ContentFormat,
+		&i.ContentHtml,
+	)
+	return i, err
+}
+
+const searchPosts = `-- name: SearchPosts :many
+SELECT posts.id, posts.title, posts.content, posts.created_at, posts.updated_at, posts.search_vector, posts.status, posts.published_at, posts.publish_at, posts.deleted_at, posts.version, posts.author_id, posts.category_id, posts.slug, posts.content_format, posts.content_html,
+    ts_rank(search_vector, websearch_to_tsquery('english', $1))::real AS score,
+    ts_headline('english', regexp_replace(content_html, '<[^>]*>', ' ', 'g'), websearch_to_tsquery('english', $1),
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10')::text AS snippet
+FROM posts
+WHERE search_vector @@ websearch_to_tsquery('english', $1)
+  AND status = 'published'
+  AND (publish_at IS NULL OR publish_at <= now())
+  AND deleted_at IS NULL
+ORDER BY score DESC, id
+LIMIT $2
+OFFSET $3
+`
+
+type SearchPostsParams struct {
+	Query      string `json:"query"`
+	PageSize   int32  `json:"page_size"`
+	PageOffset int32  `json:"page_offset"`
+}
+
+type SearchPostsRow struct {
+	ID            int32         `json:"id"`
+	Title         string        `json:"title"`
+	Content       string        `json:"content"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	SearchVector  interface{}   `json:"search_vector"`
+	Status        PostStatus    `json:"status"`
+	PublishedAt   sql.NullTime  `json:"published_at"`
+	PublishAt     sql.NullTime  `json:"publish_at"`
+	DeletedAt     sql.NullTime  `json:"deleted_at"`
+	Version       int32         `json:"version"`
+	AuthorID      sql.NullInt32 `json:"author_id"`
+	CategoryID    sql.NullInt32 `json:"category_id"`
+	Slug          string        `json:"slug"`
+	ContentFormat ContentFormat `json:"content_format"`
+	ContentHtml   string        `json:"content_html"`
+	Score         float32       `json:"score"`
+	Snippet       string        `json:"snippet"`
+}
+
+func (q *Queries) SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPosts, arg.Query, arg.PageSize, arg.PageOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchPostsRow{}
+	for rows.Next() {
+		var i SearchPostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.Status,
+			&i.PublishedAt,
+			&i.PublishAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.AuthorID,
+			&i.CategoryID,
+			&i.Slug,
+			&i.ContentFormat,
+			&i.ContentHtml,
+			&i.Score,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updatePostById = `-- name: UpdatePostById :one
+UPDATE posts
+SET title = $1,
+    content = $2,
+    content_format = $3,
+    content_html = $4,
+    publish_at = $5,
+    category_id = $6,
+    slug = coalesce($7, slug)
+WHERE id = $8 AND deleted_at IS NULL
+  AND ($9::integer IS NULL OR version = $9)
+RETURNING id, title, content, created_at, updated_at, search_vector, status, published_at, publish_at, deleted_at, version, author_id, category_id, slug, content_format, content_html
+`
+
+type UpdatePostByIdParams struct {
+	Title           string         `json:"title"`
+	Content         string         `json:"content"`
+	ContentFormat   ContentFormat  `json:"content_format"`
+	ContentHtml     string         `json:"content_html"`
+	PublishAt       sql.NullTime   `json:"publish_at"`
+	CategoryID      sql.NullInt32  `json:"category_id"`
+	Slug            sql.NullString `json:"slug"`
+	ID              int32          `json:"id"`
+	ExpectedVersion sql.NullInt32  `json:"expected_version"`
+}
+
+func (q *Queries) UpdatePostById(ctx context.Context, arg UpdatePostByIdParams) (Post, error) {
+	row := q.db.QueryRowContext(ctx, updatePostById,
+		arg.Title,
+		arg.Content,
+		arg.ContentFormat,
+		arg.ContentHtml,
+		arg.PublishAt,
+		arg.CategoryID,
+		arg.Slug,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Content,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Status,
+		&i.PublishedAt,
+		&i.PublishAt,
+		&i.DeletedAt,
+		&i.Version,
+		&i.AuthorID,
+		&i.CategoryID,
+		&i.Slug,
+		&i.ContentFormat,
+		&i.ContentHtml,
+	)
+	return i, err
+}
+
+const updatePostStatus = `-- name: UpdatePostStatus :one
+UPDATE posts
+SET status = $1,
+    published_at = CASE WHEN $1 = 'published' THEN now() ELSE published_at END,
+    publish_at = NULL
+WHERE id = $2 AND status = $3 AND deleted_at IS NULL
+RETURNING id, title, content, created_at, updated_at, search_vector, status, published_at, publish_at, deleted_at, version, author_id, category_id, slug, content_format, content_html
+`
+
+type UpdatePostStatusParams struct {
+	ToStatus   PostStatus `json:"to_status"`
+	ID         int32      `json:"id"`
+	FromStatus PostStatus `json:"from_status"`
+}
+
+func (q *Queries) UpdatePostStatus(ctx context.Context, arg UpdatePostStatusParams) (Post, error) {
+	row := q.db.QueryRowContext(ctx, updatePostStatus, arg.ToStatus, arg.ID, arg.FromStatus)
 	var i Post
 	err := row.Scan(
 		&i.ID,
@@ -119,6 +1635,17 @@ func (q *Queries) UpdatePostById(ctx context.Context, arg UpdatePostByIdParams)
 		&i.Content,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.Status,
+		&i.PublishedAt,
+		&i.PublishAt,
+		&i.DeletedAt,
+		&i.Version,
+		&i.AuthorID,
+		&i.CategoryID,
+		&i.Slug,
+		&i.ContentFormat,
+		&i.ContentHtml,
 	)
 	return i, err
 }
@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"github.com/go-faker/faker/v4"
 	"github.com/stretchr/testify/require"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestCreatePost(t *testing.T) {
@@ -25,6 +27,151 @@ func TestGetAllPosts(t *testing.T) {
 	require.True(t, postsTotalNumber <= len(posts))
 }
 
+func TestListPosts(t *testing.T) {
+	var createdPosts []Post
+	for i := 0; i < 3; i++ {
+		createdPosts = append(createdPosts, populateDBWithValidRandomPost(t))
+	}
+
+	arg := ListPostsByIDParams{
+		CursorID: sql.NullInt32{Int32: createdPosts[0].ID, Valid: true},
+		Status:   PostStatusDraft,
+		PageSize: 2,
+	}
+	posts, err := testQueries.ListPostsByID(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	require.Equal(t, createdPosts[1].ID, posts[0].ID)
+	require.Equal(t, createdPosts[2].ID, posts[1].ID)
+}
+
+func TestListPosts_SortedDescendingByCreatedAt(t *testing.T) {
+	var createdPosts []Post
+	for i := 0; i < 3; i++ {
+		createdPosts = append(createdPosts, populateDBWithValidRandomPost(t))
+	}
+	last := createdPosts[len(createdPosts)-1]
+
+	arg := ListPostsByCreatedAtDescParams{
+		CreatedAfter: sql.NullTime{Time: createdPosts[0].CreatedAt, Valid: true},
+		CursorID:     sql.NullInt32{Int32: last.ID, Valid: true},
+		CursorTime:   sql.NullTime{Time: last.CreatedAt, Valid: true},
+		Status:       PostStatusDraft,
+		PageSize:     10,
+	}
+	posts, err := testQueries.ListPostsByCreatedAtDesc(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	require.Equal(t, createdPosts[1].ID, posts[0].ID)
+}
+
+func TestListPosts_TitleContains(t *testing.T) {
+	createdPost := populateDBWithValidRandomPost(t)
+
+	arg := ListPostsByTitleParams{
+		TitleContains: sql.NullString{String: createdPost.Title[1 : len(createdPost.Title)-1], Valid: true},
+		Status:        PostStatusDraft,
+		PageSize:      10,
+	}
+	posts, err := testQueries.ListPostsByTitle(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, posts)
+	for _, post := range posts {
+		require.Contains(t, strings.ToLower(post.Title), strings.ToLower(arg.TitleContains.String))
+	}
+}
+
+func TestSearchPosts(t *testing.T) {
+	content := faker.Paragraph() + " The release ships today."
+	arg := CreatePostParams{Title: "Quarterly product release", Content: content, Slug: faker.UUIDDigit(), ContentFormat: ContentFormatPlain, ContentHtml: "<p>" + content + "</p>"}
+	createdPost, err := testQueries.CreatePost(context.Background(), arg)
+	checkInsertedPostIsValid(t, err, createdPost, arg)
+	_, err = testQueries.UpdatePostStatus(context.Background(), UpdatePostStatusParams{
+		ID: createdPost.ID, FromStatus: PostStatusDraft, ToStatus: PostStatusPublished,
+	})
+	require.NoError(t, err)
+
+	rows, err := testQueries.SearchPosts(context.Background(), SearchPostsParams{Query: "releases", PageSize: 10})
+
+	require.NoError(t, err)
+	require.NotEmpty(t, rows)
+	var found bool
+	for _, row := range rows {
+		require.Positive(t, row.Score)
+		if row.ID == createdPost.ID {
+			found = true
+			require.Contains(t, row.Snippet, "<mark>")
+		}
+	}
+	require.True(t, found)
+}
+
+func TestSearchPosts_EscapesHTMLSnippet(t *testing.T) {
+	word := "xylophone" + strings.ToLower(faker.Word())
+	arg := CreatePostParams{
+		Title:         "Markup " + faker.UUIDDigit(),
+		Content:       "<p>The " + word + " plays</p><script>alert(1)</script><img src=x onerror=alert(2)> 1 &lt; 2",
+		Slug:          faker.UUIDDigit(),
+		ContentFormat: ContentFormatHtml,
+		ContentHtml:   "<p>The " + word + " plays</p><img src=\"x\"> 1 &lt; 2",
+	}
+	createdPost, err := testQueries.CreatePost(context.Background(), arg)
+	checkInsertedPostIsValid(t, err, createdPost, arg)
+	_, err = testQueries.UpdatePostStatus(context.Background(), UpdatePostStatusParams{
+		ID: createdPost.ID, FromStatus: PostStatusDraft, ToStatus: PostStatusPublished,
+	})
+	require.NoError(t, err)
+
+	rows, err := testQueries.SearchPosts(context.Background(), SearchPostsParams{Query: word, PageSize: 10})
+
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	snippet := rows[0].Snippet
+	require.Contains(t, snippet, "<mark>"+word+"</mark>")
+	require.Contains(t, snippet, "1 &lt; 2")
+	require.NotContains(t, snippet, "script")
+	require.NotContains(t, snippet, "onerror")
+	require.NotContains(t, snippet, "<img")
+	require.NotContains(t, snippet, "<p>")
+}
+
+func TestSearchPosts_HidesScheduledPosts(t *testing.T) {
+	title := "Scheduled announcement " + faker.UUIDDigit()
+	arg := CreatePostParams{Title: title, Content: faker.Paragraph(), Slug: faker.UUIDDigit(), ContentFormat: ContentFormatPlain}
+	createdPost, err := testQueries.CreatePost(context.Background(), arg)
+	checkInsertedPostIsValid(t, err, createdPost, arg)
+	_, err = testQueries.UpdatePostStatus(context.Background(), UpdatePostStatusParams{
+		ID: createdPost.ID, FromStatus: PostStatusDraft, ToStatus: PostStatusPublished,
+	})
+	require.NoError(t, err)
+
+	search := func() bool {
+		rows, err := testQueries.SearchPosts(context.Background(), SearchPostsParams{Query: title, PageSize: 10})
+		require.NoError(t, err)
+		for _, row := range rows {
+			if row.ID == createdPost.ID {
+				return true
+			}
+		}
+		return false
+	}
+	require.True(t, search())
+
+	// a published post given a future publish time is hidden until then, like in the listing
+	_, err = testQueries.UpdatePostById(context.Background(), UpdatePostByIdParams{
+		ID:            createdPost.ID,
+		Title:         createdPost.Title,
+		Content:       createdPost.Content,
+		ContentFormat: ContentFormatPlain,
+		PublishAt:     sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+	require.False(t, search())
+}
+
 func TestGetPostById(t *testing.T) {
 	createdPost := populateDBWithValidRandomPost(t)
 
@@ -40,10 +187,18 @@ func TestGetPostById_NotFound(t *testing.T) {
 	require.Empty(t, post)
 }
 
+func TestGetPostBySlug(t *testing.T) {
+	createdPost := populateDBWithValidRandomPost(t)
+
+	post, err := testQueries.GetPostBySlug(context.Background(), createdPost.Slug)
+
+	checkFetchedPostIsValid(t, err, post, createdPost)
+}
+
 func TestUpdatePostById(t *testing.T) {
 	createdPost := populateDBWithValidRandomPost(t)
 
-	arg := UpdatePostByIdParams{ID: createdPost.ID, Title: faker.Sentence(), Content: faker.Paragraph()}
+	arg := UpdatePostByIdParams{ID: createdPost.ID, Title: faker.Sentence(), Content: faker.Paragraph(), ContentFormat: ContentFormatPlain}
 
 	alteredPost := createdPost
 	alteredPost.Title = arg.Title
@@ -55,18 +210,102 @@ func TestUpdatePostById(t *testing.T) {
 }
 
 func TestUpdatePostById_NotFound(t *testing.T) {
-	post, err := testQueries.UpdatePostById(context.Background(), UpdatePostByIdParams{ID: -1, Title: faker.Sentence(), Content: faker.Paragraph()})
+	post, err := testQueries.UpdatePostById(context.Background(), UpdatePostByIdParams{ID: -1, Title: faker.Sentence(), Content: faker.Paragraph(), ContentFormat: ContentFormatPlain})
+
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.Empty(t, post)
+}
+
+func TestUpdatePostById_VersionMismatch(t *testing.T) {
+	createdPost := populateDBWithValidRandomPost(t)
+	require.Equal(t, int32(1), createdPost.Version)
+
+	arg := UpdatePostByIdParams{
+		ID:              createdPost.ID,
+		Title:           faker.Sentence(),
+		Content:         faker.Paragraph(),
+		ContentFormat:   ContentFormatPlain,
+		ExpectedVersion: sql.NullInt32{Int32: createdPost.Version, Valid: true},
+	}
+	post, err := testQueries.UpdatePostById(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, createdPost.Version+1, post.Version)
+
+	post, err = testQueries.UpdatePostById(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.Empty(t, post)
+}
+
+func TestDeletePost_VersionMismatch(t *testing.T) {
+	createdPost := populateDBWithValidRandomPost(t)
+
+	deleted, err := testQueries.DeletePost(context.Background(), DeletePostParams{
+		ID:              createdPost.ID,
+		ExpectedVersion: sql.NullInt32{Int32: createdPost.Version + 1, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+
+	post, err := testQueries.GetPostById(context.Background(), createdPost.ID)
+	checkFetchedPostIsValid(t, err, post, createdPost)
+}
+
+func TestUpdatePostStatus(t *testing.T) {
+	createdPost := populateDBWithValidRandomPost(t)
+	require.Equal(t, PostStatusDraft, createdPost.Status)
+	require.False(t, createdPost.PublishedAt.Valid)
+
+	arg := UpdatePostStatusParams{ID: createdPost.ID, FromStatus: PostStatusDraft, ToStatus: PostStatusPublished}
+	post, err := testQueries.UpdatePostStatus(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.Equal(t, PostStatusPublished, post.Status)
+	require.True(t, post.PublishedAt.Valid)
+}
+
+func TestUpdatePostStatus_StatusMismatch(t *testing.T) {
+	createdPost := populateDBWithValidRandomPost(t)
+
+	arg := UpdatePostStatusParams{ID: createdPost.ID, FromStatus: PostStatusPublished, ToStatus: PostStatusArchived}
+	post, err := testQueries.UpdatePostStatus(context.Background(), arg)
 
 	require.ErrorIs(t, err, sql.ErrNoRows)
 	require.Empty(t, post)
 }
 
+func TestPublishDuePosts(t *testing.T) {
+	now := time.Now()
+	arg := CreatePostParams{Title: faker.Sentence(), Content: faker.Paragraph(), PublishAt: sql.NullTime{Time: now.Add(time.Hour), Valid: true}, Slug: faker.UUIDDigit(), ContentFormat: ContentFormatPlain}
+	scheduledPost, err := testQueries.CreatePost(context.Background(), arg)
+	checkInsertedPostIsValid(t, err, scheduledPost, arg)
+
+	posts, err := testQueries.PublishDuePosts(context.Background(), PublishDuePostsParams{Now: now, BatchSize: 100})
+	require.NoError(t, err)
+	for _, post := range posts {
+		require.NotEqual(t, scheduledPost.ID, post.ID)
+	}
+
+	posts, err = testQueries.PublishDuePosts(context.Background(), PublishDuePostsParams{Now: now.Add(2 * time.Hour), BatchSize: 100})
+	require.NoError(t, err)
+
+	var published Post
+	for _, post := range posts {
+		if post.ID == scheduledPost.ID {
+			published = post
+		}
+	}
+	require.Equal(t, PostStatusPublished, published.Status)
+	require.False(t, published.PublishAt.Valid)
+	require.WithinDuration(t, arg.PublishAt.Time, published.PublishedAt.Time, time.Millisecond)
+}
+
 func TestDeletePostById(t *testing.T) {
 	createdPost := populateDBWithValidRandomPost(t)
 
-	err := testQueries.DeletePost(context.Background(), createdPost.ID)
+	deleted, err := testQueries.DeletePost(context.Background(), DeletePostParams{ID: createdPost.ID})
 
 	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
 
 	post, err := testQueries.GetPostById(context.Background(), createdPost.ID)
 
@@ -74,10 +313,41 @@ func TestDeletePostById(t *testing.T) {
 	require.Empty(t, post)
 }
 
+func TestRestorePost(t *testing.T) {
+	createdPost := populateDBWithValidRandomPost(t)
+	_, err := testQueries.DeletePost(context.Background(), DeletePostParams{ID: createdPost.ID})
+	require.NoError(t, err)
+
+	deletedPosts, err := testQueries.ListDeletedPosts(context.Background(), ListDeletedPostsParams{PageSize: 100})
+	require.NoError(t, err)
+	require.NotEmpty(t, deletedPosts)
+	require.Equal(t, createdPost.ID, deletedPosts[0].ID)
+	require.True(t, deletedPosts[0].DeletedAt.Valid)
+
+	post, err := testQueries.RestorePost(context.Background(), createdPost.ID)
+	checkFetchedPostIsValid(t, err, post, createdPost)
+	require.False(t, post.DeletedAt.Valid)
+
+	_, err = testQueries.RestorePost(context.Background(), createdPost.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestPurgePost(t *testing.T) {
+	createdPost := populateDBWithValidRandomPost(t)
+
+	rows, err := testQueries.PurgePost(context.Background(), createdPost.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	rows, err = testQueries.PurgePost(context.Background(), createdPost.ID)
+	require.NoError(t, err)
+	require.Zero(t, rows)
+}
+
 func populateDBWithValidRandomPost(t *testing.T) Post {
 	title := faker.Sentence()
 	content := faker.Paragraph()
-	arg := CreatePostParams{Title: title, Content: content}
+	arg := CreatePostParams{Title: title, Content: content, Slug: faker.UUIDDigit(), ContentFormat: ContentFormatPlain}
 
 	subscription, err := testQueries.CreatePost(context.Background(), arg)
 
@@ -94,6 +364,9 @@ func checkInsertedPostIsValid(t *testing.T, err error, actual Post, expected Cre
 
 	require.Equal(t, actual.Title, expected.Title)
 	require.Equal(t, actual.Content, expected.Content)
+	require.Equal(t, actual.ContentFormat, expected.ContentFormat)
+	require.Equal(t, actual.ContentHtml, expected.ContentHtml)
+	require.Equal(t, actual.Slug, expected.Slug)
 }
 
 func checkFetchedPostIsValid(t *testing.T, err error, actual Post, expected Post) {
@@ -6,14 +6,87 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
 type Querier interface {
+	AddPostTags(ctx context.Context, arg AddPostTagsParams) error
+	AddPostViews(ctx context.Context, arg AddPostViewsParams) error
+	AddReactionCount(ctx context.Context, arg AddReactionCountParams) error
+	AddTagsToPosts(ctx context.Context, arg AddTagsToPostsParams) error
+	ClaimPostCover(ctx context.Context, arg ClaimPostCoverParams) (PostCover, error)
+	CompletePostCover(ctx context.Context, arg CompletePostCoverParams) (int64, error)
+	CreateAttachment(ctx context.Context, arg CreateAttachmentParams) (Attachment, error)
+	CreateAuthor(ctx context.Context, arg CreateAuthorParams) (Author, error)
+	CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error)
+	CreateComment(ctx context.Context, arg CreateCommentParams) (Comment, error)
 	CreatePost(ctx context.Context, arg CreatePostParams) (Post, error)
-	DeletePost(ctx context.Context, id int32) error
+	CreatePostReaction(ctx context.Context, arg CreatePostReactionParams) (int64, error)
+	CreatePostRevision(ctx context.Context, arg CreatePostRevisionParams) (PostRevision, error)
+	CreatePostSlugAlias(ctx context.Context, arg CreatePostSlugAliasParams) error
+	CreatePosts(ctx context.Context, arg CreatePostsParams) ([]Post, error)
+	DeleteAttachment(ctx context.Context, arg DeleteAttachmentParams) (Attachment, error)
+	DeleteAuthor(ctx context.Context, id int32) (int64, error)
+	DeleteComment(ctx context.Context, id int32) (int64, error)
+	DeletePost(ctx context.Context, arg DeletePostParams) (int64, error)
+	DeletePostReaction(ctx context.Context, arg DeletePostReactionParams) (int64, error)
+	DeletePostSlugAlias(ctx context.Context, arg DeletePostSlugAliasParams) error
+	DeletePostTags(ctx context.Context, postID int32) error
+	DeletePostViewBucketsBefore(ctx context.Context, before time.Time) (int64, error)
+	FailPostCover(ctx context.Context, arg FailPostCoverParams) (int64, error)
+	GetAttachmentsByPostIds(ctx context.Context, postIds []int32) ([]Attachment, error)
+	GetAuthorById(ctx context.Context, id int32) (Author, error)
+	GetAuthorsByIds(ctx context.Context, ids []int32) ([]Author, error)
+	GetCategoryById(ctx context.Context, id int32) (Category, error)
+	GetCategoryPaths(ctx context.Context, ids []int32) ([]GetCategoryPathsRow, error)
+	GetCommentCountsByPostIds(ctx context.Context, postIds []int32) ([]GetCommentCountsByPostIdsRow, error)
 	GetPostById(ctx context.Context, id int32) (Post, error)
+	GetPostByIdForUpdate(ctx context.Context, id int32) (Post, error)
+	GetPostBySlug(ctx context.Context, slug string) (Post, error)
+	GetPostBySlugAlias(ctx context.Context, slug string) (Post, error)
+	GetPostCoverForUpdate(ctx context.Context, postID int32) (PostCover, error)
+	GetPostCoversByPostIds(ctx context.Context, postIds []int32) ([]PostCover, error)
+	GetPostRevision(ctx context.Context, arg GetPostRevisionParams) (PostRevision, error)
 	GetPosts(ctx context.Context) ([]Post, error)
+	GetReactionCountsByPostIds(ctx context.Context, postIds []int32) ([]PostReactionCounter, error)
+	GetSitemapState(ctx context.Context) (GetSitemapStateRow, error)
+	GetTagsByPostIds(ctx context.Context, postIds []int32) ([]GetTagsByPostIdsRow, error)
+	GetViewCountsByPostIds(ctx context.Context, postIds []int32) ([]PostViewCount, error)
+	ListAuthors(ctx context.Context, arg ListAuthorsParams) ([]Author, error)
+	ListCategories(ctx context.Context) ([]Category, error)
+	ListCommentThreads(ctx context.Context, arg ListCommentThreadsParams) ([]ListCommentThreadsRow, error)
+	ListCommentsByStatus(ctx context.Context, arg ListCommentsByStatusParams) ([]Comment, error)
+	ListDeletedPosts(ctx context.Context, arg ListDeletedPostsParams) ([]Post, error)
+	ListDuplicatePosts(ctx context.Context, arg ListDuplicatePostsParams) ([]int32, error)
+	ListExportPosts(ctx context.Context, arg ListExportPostsParams) ([]ListExportPostsRow, error)
+	ListPopularPosts(ctx context.Context, arg ListPopularPostsParams) ([]ListPopularPostsRow, error)
+	ListPostIDsBySlugs(ctx context.Context, slugs []string) ([]ListPostIDsBySlugsRow, error)
+	ListPostRevisions(ctx context.Context, arg ListPostRevisionsParams) ([]PostRevision, error)
+	ListPostsByCreatedAt(ctx context.Context, arg ListPostsByCreatedAtParams) ([]Post, error)
+	ListPostsByCreatedAtDesc(ctx context.Context, arg ListPostsByCreatedAtDescParams) ([]Post, error)
+	ListPostsByID(ctx context.Context, arg ListPostsByIDParams) ([]Post, error)
+	ListPostsByIDDesc(ctx context.Context, arg ListPostsByIDDescParams) ([]Post, error)
+	ListPostsByLikes(ctx context.Context, arg ListPostsByLikesParams) ([]Post, error)
+	ListPostsByLikesDesc(ctx context.Context, arg ListPostsByLikesDescParams) ([]Post, error)
+	ListPostsByTitle(ctx context.Context, arg ListPostsByTitleParams) ([]Post, error)
+	ListPostsByTitleDesc(ctx context.Context, arg ListPostsByTitleDescParams) ([]Post, error)
+	ListPostsByUpdatedAt(ctx context.Context, arg ListPostsByUpdatedAtParams) ([]Post, error)
+	ListPostsByUpdatedAtDesc(ctx context.Context, arg ListPostsByUpdatedAtDescParams) ([]Post, error)
+	ListSitemapPosts(ctx context.Context, arg ListSitemapPostsParams) ([]ListSitemapPostsRow, error)
+	ListSitemapShards(ctx context.Context, shardSize int64) ([]ListSitemapShardsRow, error)
+	ListTags(ctx context.Context, arg ListTagsParams) ([]ListTagsRow, error)
+	ListTakenSlugs(ctx context.Context, arg ListTakenSlugsParams) ([]string, error)
+	LockSlug(ctx context.Context, slug string) error
+	PublishDuePosts(ctx context.Context, arg PublishDuePostsParams) ([]Post, error)
+	PurgePost(ctx context.Context, id int32) (int64, error)
+	RestorePost(ctx context.Context, id int32) (Post, error)
+	SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error)
+	UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (Author, error)
+	UpdateCommentStatus(ctx context.Context, arg UpdateCommentStatusParams) (Comment, error)
 	UpdatePostById(ctx context.Context, arg UpdatePostByIdParams) (Post, error)
+	UpdatePostStatus(ctx context.Context, arg UpdatePostStatusParams) (Post, error)
+	UpsertPostCover(ctx context.Context, arg UpsertPostCoverParams) (PostCover, error)
+	UpsertTags(ctx context.Context, names []string) ([]Tag, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAddReactionTx(t *testing.T) {
+	store := NewStore(testDB)
+	createdPost := populateDBWithValidRandomPost(t)
+	arg := CreatePostReactionParams{PostID: createdPost.ID, ClientID: faker.UUIDDigit(), Type: ReactionTypeLike}
+
+	added, err := store.AddReactionTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, added)
+
+	// reacting twice is counted once
+	added, err = store.AddReactionTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, added)
+
+	_, err = store.AddReactionTx(context.Background(), CreatePostReactionParams{PostID: createdPost.ID, ClientID: faker.UUIDDigit(), Type: ReactionTypeLike})
+	require.NoError(t, err)
+
+	counters, err := testQueries.GetReactionCountsByPostIds(context.Background(), []int32{createdPost.ID})
+	require.NoError(t, err)
+	require.Equal(t, []PostReactionCounter{{PostID: createdPost.ID, Type: ReactionTypeLike, Count: 2}}, counters)
+}
+
+func TestRemoveReactionTx(t *testing.T) {
+	store := NewStore(testDB)
+	createdPost := populateDBWithValidRandomPost(t)
+	clientID := faker.UUIDDigit()
+
+	_, err := store.AddReactionTx(context.Background(), CreatePostReactionParams{PostID: createdPost.ID, ClientID: clientID, Type: ReactionTypeWow})
+	require.NoError(t, err)
+
+	arg := DeletePostReactionParams{PostID: createdPost.ID, ClientID: clientID, Type: ReactionTypeWow}
+	removed, err := store.RemoveReactionTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, removed)
+
+	removed, err = store.RemoveReactionTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, removed)
+
+	counters, err := testQueries.GetReactionCountsByPostIds(context.Background(), []int32{createdPost.ID})
+	require.NoError(t, err)
+	require.Empty(t, counters)
+}
+
+func TestListPosts_SortedByLikes(t *testing.T) {
+	store := NewStore(testDB)
+	var createdPosts []Post
+	for i := 0; i < 3; i++ {
+		createdPosts = append(createdPosts, populateDBWithValidRandomPost(t))
+	}
+	for i, post := range createdPosts {
+		for j := 0; j < i; j++ {
+			_, err := store.AddReactionTx(context.Background(), CreatePostReactionParams{PostID: post.ID, ClientID: faker.UUIDDigit(), Type: ReactionTypeLike})
+			require.NoError(t, err)
+		}
+	}
+
+	arg := ListPostsByLikesDescParams{
+		CreatedAfter: sql.NullTime{Time: createdPosts[0].CreatedAt.Add(-1), Valid: true},
+		Status:       PostStatusDraft,
+		PageSize:     10,
+	}
+	posts, err := testQueries.ListPostsByLikesDesc(context.Background(), arg)
+
+	require.NoError(t, err)
+	require.Len(t, posts, 3)
+	require.Equal(t, createdPosts[2].ID, posts[0].ID)
+	require.Equal(t, createdPosts[1].ID, posts[1].ID)
+	require.Equal(t, createdPosts[0].ID, posts[2].ID)
+}
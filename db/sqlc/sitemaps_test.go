@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestGetSitemapState(t *testing.T) {
+	state, err := testQueries.GetSitemapState(context.Background())
+	require.NoError(t, err)
+
+	// drafts are not in sitemaps
+	createdPost := populateDBWithValidRandomPost(t)
+	unchanged, err := testQueries.GetSitemapState(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, state.Posts, unchanged.Posts)
+	require.Equal(t, state.IDSum, unchanged.IDSum)
+
+	post, err := testQueries.UpdatePostStatus(context.Background(), UpdatePostStatusParams{
+		ID:         createdPost.ID,
+		FromStatus: PostStatusDraft,
+		ToStatus:   PostStatusPublished,
+	})
+	require.NoError(t, err)
+	changed, err := testQueries.GetSitemapState(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, state.Posts+1, changed.Posts)
+	require.Equal(t, state.IDSum+int64(post.ID), changed.IDSum)
+	require.WithinDuration(t, post.UpdatedAt, changed.LastModified, 0)
+}
+
+func TestStreamSitemapPosts(t *testing.T) {
+	store := NewStore(testDB)
+	var published []Post
+	for i := 0; i < 3; i++ {
+		createdPost := populateDBWithValidRandomPost(t)
+		post, err := testQueries.UpdatePostStatus(context.Background(), UpdatePostStatusParams{
+			ID:         createdPost.ID,
+			FromStatus: PostStatusDraft,
+			ToStatus:   PostStatusPublished,
+		})
+		require.NoError(t, err)
+		published = append(published, post)
+	}
+	draft := populateDBWithValidRandomPost(t)
+	require.Greater(t, draft.ID, published[2].ID)
+
+	var streamed []ListSitemapPostsRow
+	err := store.StreamSitemapPosts(context.Background(), ListSitemapPostsParams{FirstID: published[1].ID, ShardSize: 10}, func(row ListSitemapPostsRow) error {
+		streamed = append(streamed, row)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, streamed, 2)
+	for i, row := range streamed {
+		require.Equal(t, published[i+1].ID, row.ID)
+		require.Equal(t, published[i+1].Slug, row.Slug)
+		require.WithinDuration(t, published[i+1].UpdatedAt, row.UpdatedAt, 0)
+	}
+
+	listed, err := testQueries.ListSitemapPosts(context.Background(), ListSitemapPostsParams{FirstID: published[1].ID, ShardSize: 10})
+	require.NoError(t, err)
+	require.Equal(t, streamed, listed)
+}
+
+func TestListSitemapShards(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		createdPost := populateDBWithValidRandomPost(t)
+		_, err := testQueries.UpdatePostStatus(context.Background(), UpdatePostStatusParams{
+			ID:         createdPost.ID,
+			FromStatus: PostStatusDraft,
+			ToStatus:   PostStatusPublished,
+		})
+		require.NoError(t, err)
+	}
+
+	shards, err := testQueries.ListSitemapShards(context.Background(), 1)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(shards), 2)
+	for i := 1; i < len(shards); i++ {
+		require.Greater(t, shards[i].FirstID, shards[i-1].FirstID)
+	}
+
+	single, err := testQueries.ListSitemapShards(context.Background(), 50000*1000)
+	require.NoError(t, err)
+	require.Len(t, single, 1)
+	require.Equal(t, shards[0].FirstID, single[0].FirstID)
+}
@@ -1,12 +1,37 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"promova-test-task/util"
+	"time"
 )
 
+const (
+	defaultTxRetries = 3
+	txRetryBackoff   = 10 * time.Millisecond
+)
+
+// ErrVersionMismatch is returned when a post was changed since the version the caller expected.
+var ErrVersionMismatch = errors.New("post was modified concurrently")
+
 // Store provides all functions to run individual queries as well as transactions
 type Store interface {
 	Querier
+	ExecTx(ctx context.Context, fn func(*Queries) error, options ...TxOption) error
+	CreatePostTx(ctx context.Context, arg CreatePostTxParams) (Post, error)
+	UpdatePostTx(ctx context.Context, id int32, update func(post Post) (UpdatePostTxParams, error)) (Post, error)
+	DeletePostTx(ctx context.Context, id int32, check func(post Post) error) error
+	BatchPostsTx(ctx context.Context, operations []PostOperation, atomic bool) ([]PostOperationResult, error)
+	ImportPostsTx(ctx context.Context, posts []CreatePostTxParams) ([]ImportPostResult, error)
+	AddReactionTx(ctx context.Context, arg CreatePostReactionParams) (bool, error)
+	RemoveReactionTx(ctx context.Context, arg DeletePostReactionParams) (bool, error)
+	ReplacePostCoverTx(ctx context.Context, arg UpsertPostCoverParams) (PostCover, *PostCover, error)
+	StreamSitemapPosts(ctx context.Context, arg ListSitemapPostsParams, fn func(ListSitemapPostsRow) error) error
+	StreamExportPosts(ctx context.Context, arg ListExportPostsParams, fn func(ListExportPostsRow) error) error
 }
 
 // SQLStore provides all functions to run individual queries as well as transactions
@@ -21,3 +46,335 @@ func NewStore(db *sql.DB) Store {
 		Queries: New(db),
 	}
 }
+
+type txConfig struct {
+	isolation sql.IsolationLevel
+	retries   int
+}
+
+// TxOption configures a transaction run by ExecTx
+type TxOption func(*txConfig)
+
+// WithIsolation runs the transaction with the given isolation level instead of the database default
+func WithIsolation(level sql.IsolationLevel) TxOption {
+	return func(config *txConfig) {
+		config.isolation = level
+	}
+}
+
+// WithRetries sets how many times a transaction is retried after a serialization failure
+func WithRetries(retries int) TxOption {
+	return func(config *txConfig) {
+		config.retries = retries
+	}
+}
+
+// ExecTx executes a function within a database transaction.
+// The transaction is rolled back when fn returns an error or panics, and the whole of fn is run again
+// when the database aborts the transaction with a serialization failure, so fn must not have side effects
+// outside of the transaction.
+func (store *SQLStore) ExecTx(ctx context.Context, fn func(*Queries) error, options ...TxOption) error {
+	config := txConfig{isolation: sql.LevelDefault, retries: defaultTxRetries}
+	for _, option := range options {
+		option(&config)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := store.execTx(ctx, &sql.TxOptions{Isolation: config.isolation}, fn)
+		if err == nil || !isSerializationFailure(err) || attempt >= config.retries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt+1) * txRetryBackoff):
+		}
+	}
+}
+
+func (store *SQLStore) execTx(ctx context.Context, options *sql.TxOptions, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, options)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	q := New(tx)
+	err = fn(q)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("tx err: %w, rb err: %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func isSerializationFailure(err error) bool {
+	var pqError *pq.Error
+	return errors.As(err, &pqError) && pqError.Code == "40001"
+}
+
+// CreatePostTxParams is a new post together with the names of its tags
+type CreatePostTxParams struct {
+	CreatePostParams
+	Tags []string
+}
+
+// UpdatePostTxParams is the new state of a post. Tags replaces the tags of the post unless it is nil
+type UpdatePostTxParams struct {
+	UpdatePostByIdParams
+	Tags []string
+}
+
+// CreatePostTx creates the post with a unique slug made from its title and tags it,
+// creating the tags that do not exist yet.
+func (store *SQLStore) CreatePostTx(ctx context.Context, arg CreatePostTxParams) (Post, error) {
+	var result Post
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		var err error
+		arg.Slug, err = allocateSlug(ctx, q, 0, arg.Title)
+		if err != nil {
+			return err
+		}
+
+		result, err = q.CreatePost(ctx, arg.CreatePostParams)
+		if err != nil {
+			return err
+		}
+
+		if len(arg.Tags) == 0 {
+			return nil
+		}
+		return setPostTags(ctx, q, result.ID, arg.Tags)
+	})
+
+	return result, err
+}
+
+// UpdatePostTx locks the post, lets update build the new state from it and saves the current title
+// and content with its format as a new revision before updating the post. Concurrent updates of the same post wait
+// for each other, so update always sees the latest version and revisions get consecutive numbers.
+// A new title gets the post a new slug, and the old one is kept as an alias redirecting to the post.
+// An error returned by update aborts the transaction and is passed through.
+func (store *SQLStore) UpdatePostTx(ctx context.Context, id int32, update func(post Post) (UpdatePostTxParams, error)) (Post, error) {
+	var result Post
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = updatePostInTx(ctx, q, id, update)
+		return err
+	})
+
+	return result, err
+}
+
+// DeletePostTx locks the post, lets check veto the deletion and moves the post to the trash.
+// An error returned by check aborts the transaction and is passed through.
+func (store *SQLStore) DeletePostTx(ctx context.Context, id int32, check func(post Post) error) error {
+	return store.ExecTx(ctx, func(q *Queries) error {
+		return deletePostInTx(ctx, q, id, check)
+	})
+}
+
+// AddReactionTx records the reaction of a client to a post and counts it, unless the client already reacted
+// to the post the same way. It reports whether the reaction is new.
+func (store *SQLStore) AddReactionTx(ctx context.Context, arg CreatePostReactionParams) (bool, error) {
+	var added bool
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		created, err := q.CreatePostReaction(ctx, arg)
+		if err != nil {
+			return err
+		}
+		added = created > 0
+		if !added {
+			return nil
+		}
+		return q.AddReactionCount(ctx, AddReactionCountParams{PostID: arg.PostID, Type: arg.Type, Delta: 1})
+	})
+
+	return added, err
+}
+
+// RemoveReactionTx takes back the reaction of a client to a post and uncounts it.
+// It reports whether there was such a reaction.
+func (store *SQLStore) RemoveReactionTx(ctx context.Context, arg DeletePostReactionParams) (bool, error) {
+	var removed bool
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		deleted, err := q.DeletePostReaction(ctx, arg)
+		if err != nil {
+			return err
+		}
+		removed = deleted > 0
+		if !removed {
+			return nil
+		}
+		return q.AddReactionCount(ctx, AddReactionCountParams{PostID: arg.PostID, Type: arg.Type, Delta: -1})
+	})
+
+	return removed, err
+}
+
+// ReplacePostCoverTx makes the uploaded image the cover of the post, waiting to be processed.
+// It returns the new cover together with the one it replaced, if any, so that the blobs of the previous cover
+// can be removed. The post is locked, so concurrent uploads to the same post replace each other in turn.
+func (store *SQLStore) ReplacePostCoverTx(ctx context.Context, arg UpsertPostCoverParams) (PostCover, *PostCover, error) {
+	var cover PostCover
+	var previous *PostCover
+
+	err := store.ExecTx(ctx, func(q *Queries) error {
+		if _, err := q.GetPostByIdForUpdate(ctx, arg.PostID); err != nil {
+			return err
+		}
+
+		current, err := q.GetPostCoverForUpdate(ctx, arg.PostID)
+		switch {
+		case err == nil:
+			previous = &current
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+
+		cover, err = q.UpsertPostCover(ctx, arg)
+		return err
+	})
+
+	return cover, previous, err
+}
+
+// updatePostInTx is UpdatePostTx within the transaction of q.
+func updatePostInTx(ctx context.Context, q *Queries, id int32, update func(post Post) (UpdatePostTxParams, error)) (Post, error) {
+	post, err := q.GetPostByIdForUpdate(ctx, id)
+	if err != nil {
+		return Post{}, err
+	}
+
+	arg, err := update(post)
+	if err != nil {
+		return Post{}, err
+	}
+	arg.ID = post.ID
+
+	if arg.Title != post.Title {
+		slug, err := reslugPost(ctx, q, post, arg.Title)
+		if err != nil {
+			return Post{}, err
+		}
+		arg.Slug = sql.NullString{String: slug, Valid: true}
+	}
+
+	_, err = q.CreatePostRevision(ctx, CreatePostRevisionParams{
+		PostID:        post.ID,
+		Title:         post.Title,
+		Content:       post.Content,
+		ContentFormat: post.ContentFormat,
+	})
+	if err != nil {
+		return Post{}, err
+	}
+
+	// the row is locked, so an update that matches nothing means the expected version is stale
+	result, err := q.UpdatePostById(ctx, arg.UpdatePostByIdParams)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Post{}, ErrVersionMismatch
+	}
+	if err != nil {
+		return Post{}, err
+	}
+
+	if arg.Tags == nil {
+		return result, nil
+	}
+	return result, setPostTags(ctx, q, result.ID, arg.Tags)
+}
+
+// deletePostInTx is DeletePostTx within the transaction of q.
+func deletePostInTx(ctx context.Context, q *Queries, id int32, check func(post Post) error) error {
+	post, err := q.GetPostByIdForUpdate(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = check(post); err != nil {
+		return err
+	}
+
+	deleted, err := q.DeletePost(ctx, DeletePostParams{
+		ID:              post.ID,
+		ExpectedVersion: sql.NullInt32{Int32: post.Version, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrVersionMismatch
+	}
+	return nil
+}
+
+// setPostTags makes the named tags the only tags of the post, creating the ones that do not exist yet.
+func setPostTags(ctx context.Context, q *Queries, postID int32, names []string) error {
+	if err := q.DeletePostTags(ctx, postID); err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	tags, err := q.UpsertTags(ctx, names)
+	if err != nil {
+		return err
+	}
+
+	tagIDs := make([]int32, 0, len(tags))
+	for _, tag := range tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	return q.AddPostTags(ctx, AddPostTagsParams{PostID: postID, TagIds: tagIDs})
+}
+
+// allocateSlug makes a slug from the title that no other post uses, neither as its slug nor as an alias.
+// Allocations of the same slug wait for each other until the end of the transaction, so concurrent posts
+// with the same title get different suffixes.
+func allocateSlug(ctx context.Context, q *Queries, postID int32, title string) (string, error) {
+	base := util.Slugify(title)
+	if err := q.LockSlug(ctx, base); err != nil {
+		return "", err
+	}
+
+	taken, err := q.ListTakenSlugs(ctx, ListTakenSlugsParams{Base: base, PostID: postID})
+	if err != nil {
+		return "", err
+	}
+	return util.UniqueSlug(base, taken), nil
+}
+
+// reslugPost allocates a slug for the new title of the post and keeps the current one as an alias.
+// A post renamed back to an earlier title gets its old slug back.
+func reslugPost(ctx context.Context, q *Queries, post Post, title string) (string, error) {
+	slug, err := allocateSlug(ctx, q, post.ID, title)
+	if err != nil || slug == post.Slug {
+		return slug, err
+	}
+
+	err = q.DeletePostSlugAlias(ctx, DeletePostSlugAliasParams{Slug: slug, PostID: post.ID})
+	if err != nil {
+		return "", err
+	}
+	err = q.CreatePostSlugAlias(ctx, CreatePostSlugAliasParams{Slug: post.Slug, PostID: post.ID})
+	if err != nil {
+		return "", err
+	}
+	return slug, nil
+}
@@ -0,0 +1,332 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/go-faker/faker/v4"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"promova-test-task/util"
+	"strings"
+	"testing"
+)
+
+func TestExecTx_RollbackOnError(t *testing.T) {
+	store := NewStore(testDB)
+	createdPost := populateDBWithValidRandomPost(t)
+	errAbort := errors.New("abort")
+
+	err := store.ExecTx(context.Background(), func(q *Queries) error {
+		_, err := q.UpdatePostById(context.Background(), UpdatePostByIdParams{ID: createdPost.ID, Title: faker.Sentence(), Content: faker.Paragraph(), ContentFormat: ContentFormatPlain})
+		require.NoError(t, err)
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+
+	post, err := testQueries.GetPostById(context.Background(), createdPost.ID)
+	checkFetchedPostIsValid(t, err, post, createdPost)
+}
+
+func TestExecTx_RollbackOnPanic(t *testing.T) {
+	store := NewStore(testDB)
+	createdPost := populateDBWithValidRandomPost(t)
+
+	require.Panics(t, func() {
+		_ = store.ExecTx(context.Background(), func(q *Queries) error {
+			_, err := q.UpdatePostById(context.Background(), UpdatePostByIdParams{ID: createdPost.ID, Title: faker.Sentence(), Content: faker.Paragraph(), ContentFormat: ContentFormatPlain})
+			require.NoError(t, err)
+			panic("abort")
+		})
+	})
+
+	post, err := testQueries.GetPostById(context.Background(), createdPost.ID)
+	checkFetchedPostIsValid(t, err, post, createdPost)
+}
+
+func TestExecTx_RetryOnSerializationFailure(t *testing.T) {
+	store := NewStore(testDB)
+
+	attempts := 0
+	err := store.ExecTx(context.Background(), func(q *Queries) error {
+		attempts++
+		if attempts == 1 {
+			return &pq.Error{Code: "40001"}
+		}
+		return nil
+	}, WithIsolation(sql.LevelSerializable))
+
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts)
+}
+
+func TestExecTx_RetriesExhausted(t *testing.T) {
+	store := NewStore(testDB)
+
+	attempts := 0
+	err := store.ExecTx(context.Background(), func(q *Queries) error {
+		attempts++
+		return &pq.Error{Code: "40001"}
+	}, WithRetries(2))
+
+	var pqError *pq.Error
+	require.ErrorAs(t, err, &pqError)
+	require.Equal(t, 3, attempts)
+}
+
+func TestUpdatePostTx(t *testing.T) {
+	store := NewStore(testDB)
+	createdPost := populateDBWithValidRandomPost(t)
+
+	n := 3
+	for i := 0; i < n; i++ {
+		arg := UpdatePostByIdParams{Title: faker.Sentence(), Content: faker.Paragraph(), ContentFormat: ContentFormatPlain}
+		post, err := store.UpdatePostTx(context.Background(), createdPost.ID, func(post Post) (UpdatePostTxParams, error) {
+			return UpdatePostTxParams{UpdatePostByIdParams: arg}, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, arg.Title, post.Title)
+		require.Equal(t, arg.Content, post.Content)
+	}
+
+	revisions, err := testQueries.ListPostRevisions(context.Background(), ListPostRevisionsParams{PostID: createdPost.ID, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, revisions, n)
+	for i, revision := range revisions {
+		require.Equal(t, int32(n-i), revision.Revision)
+	}
+
+	first, err := testQueries.GetPostRevision(context.Background(), GetPostRevisionParams{PostID: createdPost.ID, Revision: 1})
+	require.NoError(t, err)
+	require.Equal(t, createdPost.Title, first.Title)
+	require.Equal(t, createdPost.Content, first.Content)
+
+	// the revisions of a trashed post are gone with it
+	_, err = testQueries.DeletePost(context.Background(), DeletePostParams{ID: createdPost.ID})
+	require.NoError(t, err)
+	_, err = testQueries.GetPostRevision(context.Background(), GetPostRevisionParams{PostID: createdPost.ID, Revision: 1})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUpdatePostTx_ContentFormat(t *testing.T) {
+	store := NewStore(testDB)
+	createdPost := populateDBWithValidRandomPost(t)
+
+	arg := UpdatePostByIdParams{
+		Title:         faker.Sentence(),
+		Content:       "# Release notes",
+		ContentFormat: ContentFormatMarkdown,
+		ContentHtml:   "<h1>Release notes</h1>",
+	}
+	post, err := store.UpdatePostTx(context.Background(), createdPost.ID, func(post Post) (UpdatePostTxParams, error) {
+		return UpdatePostTxParams{UpdatePostByIdParams: arg}, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, ContentFormatMarkdown, post.ContentFormat)
+	require.Equal(t, arg.ContentHtml, post.ContentHtml)
+
+	// the revision keeps the format the content was written in
+	revision, err := testQueries.GetPostRevision(context.Background(), GetPostRevisionParams{PostID: createdPost.ID, Revision: 1})
+	require.NoError(t, err)
+	require.Equal(t, ContentFormatPlain, revision.ContentFormat)
+}
+
+func TestUpdatePostTx_Concurrent(t *testing.T) {
+	store := NewStore(testDB)
+	createdPost := populateDBWithValidRandomPost(t)
+
+	// every update appends a line to what it reads, so a lost update would show as a missing line
+	n := 5
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.UpdatePostTx(context.Background(), createdPost.ID, func(post Post) (UpdatePostTxParams, error) {
+				return UpdatePostTxParams{UpdatePostByIdParams: UpdatePostByIdParams{Title: post.Title, Content: post.Content + "\nline", ContentFormat: ContentFormatPlain}}, nil
+			})
+			errs <- err
+		}()
+	}
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	post, err := testQueries.GetPostById(context.Background(), createdPost.ID)
+	require.NoError(t, err)
+	require.Equal(t, createdPost.Version+int32(n), post.Version)
+	require.Equal(t, createdPost.Content+strings.Repeat("\nline", n), post.Content)
+
+	revisions, err := testQueries.ListPostRevisions(context.Background(), ListPostRevisionsParams{PostID: createdPost.ID, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, revisions, n)
+}
+
+func TestUpdatePostTx_UpdateFails(t *testing.T) {
+	store := NewStore(testDB)
+	createdPost := populateDBWithValidRandomPost(t)
+	errRejected := errors.New("rejected")
+
+	post, err := store.UpdatePostTx(context.Background(), createdPost.ID, func(post Post) (UpdatePostTxParams, error) {
+		return UpdatePostTxParams{}, errRejected
+	})
+	require.ErrorIs(t, err, errRejected)
+	require.Empty(t, post)
+
+	revisions, err := testQueries.ListPostRevisions(context.Background(), ListPostRevisionsParams{PostID: createdPost.ID, PageSize: 10})
+	require.NoError(t, err)
+	require.Empty(t, revisions)
+}
+
+func TestUpdatePostTx_VersionMismatch(t *testing.T) {
+	store := NewStore(testDB)
+	createdPost := populateDBWithValidRandomPost(t)
+
+	post, err := store.UpdatePostTx(context.Background(), createdPost.ID, func(post Post) (UpdatePostTxParams, error) {
+		return UpdatePostTxParams{UpdatePostByIdParams: UpdatePostByIdParams{
+			Title:           faker.Sentence(),
+			Content:         faker.Paragraph(),
+			ContentFormat:   ContentFormatPlain,
+			ExpectedVersion: sql.NullInt32{Int32: post.Version + 1, Valid: true},
+		}}, nil
+	})
+	require.ErrorIs(t, err, ErrVersionMismatch)
+	require.Empty(t, post)
+
+	revisions, err := testQueries.ListPostRevisions(context.Background(), ListPostRevisionsParams{PostID: createdPost.ID, PageSize: 10})
+	require.NoError(t, err)
+	require.Empty(t, revisions)
+}
+
+func TestUpdatePostTx_NotFound(t *testing.T) {
+	store := NewStore(testDB)
+
+	post, err := store.UpdatePostTx(context.Background(), -1, func(post Post) (UpdatePostTxParams, error) {
+		return UpdatePostTxParams{UpdatePostByIdParams: UpdatePostByIdParams{Title: faker.Sentence(), Content: faker.Paragraph(), ContentFormat: ContentFormatPlain}}, nil
+	})
+
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	require.Empty(t, post)
+}
+
+func TestCreatePostTx_UniqueSlugs(t *testing.T) {
+	store := NewStore(testDB)
+	title := "Slug collision " + faker.UUIDDigit()
+
+	var slugs []string
+	for i := 0; i < 3; i++ {
+		post, err := store.CreatePostTx(context.Background(), CreatePostTxParams{
+			CreatePostParams: CreatePostParams{Title: title, Content: faker.Paragraph(), ContentFormat: ContentFormatPlain},
+		})
+		require.NoError(t, err)
+		slugs = append(slugs, post.Slug)
+	}
+
+	base := util.Slugify(title)
+	require.Equal(t, []string{base, base + "-2", base + "-3"}, slugs)
+}
+
+func TestUpdatePostTx_KeepsOldSlugAsAlias(t *testing.T) {
+	store := NewStore(testDB)
+	createdPost, err := store.CreatePostTx(context.Background(), CreatePostTxParams{
+		CreatePostParams: CreatePostParams{Title: "Old title " + faker.UUIDDigit(), Content: faker.Paragraph(), ContentFormat: ContentFormatPlain},
+	})
+	require.NoError(t, err)
+
+	rename := func(title string) Post {
+		post, err := store.UpdatePostTx(context.Background(), createdPost.ID, func(post Post) (UpdatePostTxParams, error) {
+			return UpdatePostTxParams{UpdatePostByIdParams: UpdatePostByIdParams{Title: title, Content: post.Content, ContentFormat: ContentFormatPlain}}, nil
+		})
+		require.NoError(t, err)
+		return post
+	}
+
+	renamedPost := rename("New title " + faker.UUIDDigit())
+	require.Equal(t, util.Slugify(renamedPost.Title), renamedPost.Slug)
+
+	post, err := testQueries.GetPostBySlugAlias(context.Background(), createdPost.Slug)
+	require.NoError(t, err)
+	require.Equal(t, renamedPost.Slug, post.Slug)
+
+	// renaming back reclaims the old slug and keeps the newer one as an alias instead
+	restoredPost := rename(createdPost.Title)
+	require.Equal(t, createdPost.Slug, restoredPost.Slug)
+
+	_, err = testQueries.GetPostBySlugAlias(context.Background(), createdPost.Slug)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	post, err = testQueries.GetPostBySlugAlias(context.Background(), renamedPost.Slug)
+	require.NoError(t, err)
+	require.Equal(t, createdPost.ID, post.ID)
+}
+
+func TestUpdatePostTx_RenamedPostRedirectsFromEverySlug(t *testing.T) {
+	store := NewStore(testDB)
+	suffix := faker.UUIDDigit()
+	takenPost, err := store.CreatePostTx(context.Background(), CreatePostTxParams{
+		CreatePostParams: CreatePostParams{Title: "Taken title " + suffix, Content: faker.Paragraph(), ContentFormat: ContentFormatPlain},
+	})
+	require.NoError(t, err)
+	createdPost, err := store.CreatePostTx(context.Background(), CreatePostTxParams{
+		CreatePostParams: CreatePostParams{Title: "First title " + suffix, Content: faker.Paragraph(), ContentFormat: ContentFormatPlain},
+	})
+	require.NoError(t, err)
+
+	rename := func(title string) Post {
+		post, err := store.UpdatePostTx(context.Background(), createdPost.ID, func(post Post) (UpdatePostTxParams, error) {
+			return UpdatePostTxParams{UpdatePostByIdParams: UpdatePostByIdParams{Title: title, Content: post.Content, ContentFormat: ContentFormatPlain}}, nil
+		})
+		require.NoError(t, err)
+		return post
+	}
+	// requireRedirect checks an old slug no longer names a post but leads to the current one, as getPostBySlug redirects
+	requireRedirect := func(slug string, current Post) {
+		_, err := testQueries.GetPostBySlug(context.Background(), slug)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		post, err := testQueries.GetPostBySlugAlias(context.Background(), slug)
+		require.NoError(t, err)
+		require.Equal(t, current.Slug, post.Slug)
+	}
+
+	secondPost := rename("Second title " + suffix)
+	thirdPost := rename("Third title " + suffix)
+	require.Equal(t, util.Slugify(thirdPost.Title), thirdPost.Slug)
+	post, err := testQueries.GetPostBySlug(context.Background(), thirdPost.Slug)
+	require.NoError(t, err)
+	require.Equal(t, createdPost.ID, post.ID)
+	requireRedirect(createdPost.Slug, thirdPost)
+	requireRedirect(secondPost.Slug, thirdPost)
+
+	// back to the first title, the first slug is the post's own again and the later ones redirect to it
+	restoredPost := rename(createdPost.Title)
+	require.Equal(t, createdPost.Slug, restoredPost.Slug)
+	requireRedirect(secondPost.Slug, restoredPost)
+	requireRedirect(thirdPost.Slug, restoredPost)
+
+	// the title of another post gives a slug of its own
+	collidingPost := rename(takenPost.Title)
+	require.Equal(t, takenPost.Slug+"-2", collidingPost.Slug)
+	requireRedirect(createdPost.Slug, collidingPost)
+}
+
+func TestDeletePostTx(t *testing.T) {
+	store := NewStore(testDB)
+	createdPost := populateDBWithValidRandomPost(t)
+	errRejected := errors.New("rejected")
+
+	err := store.DeletePostTx(context.Background(), createdPost.ID, func(post Post) error {
+		return errRejected
+	})
+	require.ErrorIs(t, err, errRejected)
+
+	err = store.DeletePostTx(context.Background(), createdPost.ID, func(post Post) error {
+		require.Equal(t, createdPost.ID, post.ID)
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.GetPostById(context.Background(), createdPost.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = store.DeletePostTx(context.Background(), createdPost.ID, func(post Post) error {
+		return nil
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
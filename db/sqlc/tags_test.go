@@ -0,0 +1,130 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCreatePostTx_WithTags(t *testing.T) {
+	store := NewStore(testDB)
+	tag := faker.Word() + faker.Word()
+
+	post, err := store.CreatePostTx(context.Background(), CreatePostTxParams{
+		CreatePostParams: CreatePostParams{Title: faker.Sentence(), Content: faker.Paragraph(), ContentFormat: ContentFormatPlain},
+		Tags:             []string{tag, tag + "-other"},
+	})
+	require.NoError(t, err)
+
+	rows, err := testQueries.GetTagsByPostIds(context.Background(), []int32{post.ID})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, tag, rows[0].Name)
+	require.Equal(t, tag+"-other", rows[1].Name)
+}
+
+func TestUpdatePostTx_ReplacesTags(t *testing.T) {
+	store := NewStore(testDB)
+	tag := faker.Word() + faker.Word()
+	post, err := store.CreatePostTx(context.Background(), CreatePostTxParams{
+		CreatePostParams: CreatePostParams{Title: faker.Sentence(), Content: faker.Paragraph(), ContentFormat: ContentFormatPlain},
+		Tags:             []string{tag},
+	})
+	require.NoError(t, err)
+
+	update := func(tags []string) {
+		_, err := store.UpdatePostTx(context.Background(), post.ID, func(post Post) (UpdatePostTxParams, error) {
+			return UpdatePostTxParams{
+				UpdatePostByIdParams: UpdatePostByIdParams{Title: post.Title, Content: post.Content, ContentFormat: ContentFormatPlain},
+				Tags:                 tags,
+			}, nil
+		})
+		require.NoError(t, err)
+	}
+
+	// nil keeps the tags
+	update(nil)
+	rows, err := testQueries.GetTagsByPostIds(context.Background(), []int32{post.ID})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+
+	update([]string{tag + "-new"})
+	rows, err = testQueries.GetTagsByPostIds(context.Background(), []int32{post.ID})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, tag+"-new", rows[0].Name)
+
+	update([]string{})
+	rows, err = testQueries.GetTagsByPostIds(context.Background(), []int32{post.ID})
+	require.NoError(t, err)
+	require.Empty(t, rows)
+}
+
+func TestListPosts_ByTags(t *testing.T) {
+	store := NewStore(testDB)
+	first, second := faker.Word()+faker.Word(), faker.Word()+faker.Word()
+
+	createPost := func(tags ...string) Post {
+		post, err := store.CreatePostTx(context.Background(), CreatePostTxParams{
+			CreatePostParams: CreatePostParams{Title: faker.Sentence(), Content: faker.Paragraph(), ContentFormat: ContentFormatPlain},
+			Tags:             tags,
+		})
+		require.NoError(t, err)
+		return post
+	}
+	onlyFirst := createPost(first)
+	both := createPost(first, second)
+	createPost()
+
+	list := func(tagMatch string) []Post {
+		posts, err := testQueries.ListPostsByID(context.Background(), ListPostsByIDParams{
+			Status:   PostStatusDraft,
+			Tags:     []string{first, second},
+			TagMatch: tagMatch,
+			CursorID: sql.NullInt32{Int32: onlyFirst.ID - 1, Valid: true},
+			PageSize: 10,
+		})
+		require.NoError(t, err)
+		return posts
+	}
+
+	anyPosts := list("any")
+	require.Len(t, anyPosts, 2)
+	require.Equal(t, onlyFirst.ID, anyPosts[0].ID)
+	require.Equal(t, both.ID, anyPosts[1].ID)
+
+	allPosts := list("all")
+	require.Len(t, allPosts, 1)
+	require.Equal(t, both.ID, allPosts[0].ID)
+}
+
+func TestListTags(t *testing.T) {
+	store := NewStore(testDB)
+	tag := faker.Word() + faker.Word()
+
+	post, err := store.CreatePostTx(context.Background(), CreatePostTxParams{
+		CreatePostParams: CreatePostParams{Title: faker.Sentence(), Content: faker.Paragraph(), ContentFormat: ContentFormatPlain},
+		Tags:             []string{tag},
+	})
+	require.NoError(t, err)
+	_, err = testQueries.UpdatePostStatus(context.Background(), UpdatePostStatusParams{
+		ToStatus:   PostStatusPublished,
+		ID:         post.ID,
+		FromStatus: PostStatusDraft,
+	})
+	require.NoError(t, err)
+
+	tags, err := testQueries.ListTags(context.Background(), ListTagsParams{PageSize: 1000})
+	require.NoError(t, err)
+
+	var found bool
+	for _, row := range tags {
+		if row.Name == tag {
+			found = true
+			require.Equal(t, int32(1), row.PostCount)
+		}
+	}
+	require.True(t, found)
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestAddPostViews(t *testing.T) {
+	createdPost := populateDBWithValidRandomPost(t)
+	bucket := time.Date(2100, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, count := range []int64{3, 4} {
+		err := testQueries.AddPostViews(context.Background(), AddPostViewsParams{
+			PostIds: []int32{createdPost.ID},
+			Counts:  []int64{count},
+			Bucket:  bucket,
+		})
+		require.NoError(t, err)
+	}
+
+	counts, err := testQueries.GetViewCountsByPostIds(context.Background(), []int32{createdPost.ID})
+	require.NoError(t, err)
+	require.Equal(t, []PostViewCount{{PostID: createdPost.ID, Count: 7}}, counts)
+}
+
+func TestAddPostViews_SkipsMissingPosts(t *testing.T) {
+	createdPost := populateDBWithValidRandomPost(t)
+
+	err := testQueries.AddPostViews(context.Background(), AddPostViewsParams{
+		PostIds: []int32{createdPost.ID, -1},
+		Counts:  []int64{1, 1},
+		Bucket:  time.Date(2100, 1, 2, 10, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	counts, err := testQueries.GetViewCountsByPostIds(context.Background(), []int32{createdPost.ID, -1})
+	require.NoError(t, err)
+	require.Equal(t, []PostViewCount{{PostID: createdPost.ID, Count: 1}}, counts)
+}
+
+func TestListPopularPosts(t *testing.T) {
+	since := time.Date(2101, 1, 1, 0, 0, 0, 0, time.UTC)
+	var postIDs []int32
+	for i := 0; i < 3; i++ {
+		createdPost := populateDBWithValidRandomPost(t)
+		_, err := testQueries.UpdatePostStatus(context.Background(), UpdatePostStatusParams{
+			ID:         createdPost.ID,
+			FromStatus: PostStatusDraft,
+			ToStatus:   PostStatusPublished,
+		})
+		require.NoError(t, err)
+		postIDs = append(postIDs, createdPost.ID)
+	}
+
+	views := []AddPostViewsParams{
+		// outside of the window
+		{PostIds: []int32{postIDs[0]}, Counts: []int64{100}, Bucket: since.Add(-time.Hour)},
+		{PostIds: postIDs, Counts: []int64{1, 2, 3}, Bucket: since},
+		{PostIds: postIDs[1:2], Counts: []int64{5}, Bucket: since.Add(time.Hour)},
+	}
+	for _, arg := range views {
+		require.NoError(t, testQueries.AddPostViews(context.Background(), arg))
+	}
+
+	rows, err := testQueries.ListPopularPosts(context.Background(), ListPopularPostsParams{Since: since, PageSize: 2})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, postIDs[1], rows[0].ID)
+	require.Equal(t, int64(7), rows[0].Views)
+	require.Equal(t, postIDs[2], rows[1].ID)
+	require.Equal(t, int64(3), rows[1].Views)
+}
+
+func TestDeletePostViewBucketsBefore(t *testing.T) {
+	createdPost := populateDBWithValidRandomPost(t)
+	bucket := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	err := testQueries.AddPostViews(context.Background(), AddPostViewsParams{
+		PostIds: []int32{createdPost.ID},
+		Counts:  []int64{1},
+		Bucket:  bucket,
+	})
+	require.NoError(t, err)
+
+	deleted, err := testQueries.DeletePostViewBucketsBefore(context.Background(), bucket.Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	// the total survives the bucket
+	counts, err := testQueries.GetViewCountsByPostIds(context.Background(), []int32{createdPost.ID})
+	require.NoError(t, err)
+	require.Equal(t, []PostViewCount{{PostID: createdPost.ID, Count: 1}}, counts)
+}
@@ -0,0 +1,137 @@
+// Package imaging decodes, resizes and re-encodes the images posts are illustrated with. It is pure Go,
+// so it needs no image libraries installed next to the binary.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	xdraw "golang.org/x/image/draw"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+	ContentTypeWebP = "image/webp"
+
+	jpegQuality = 85
+)
+
+// ErrUnsupported is returned for images that are neither JPEG, PNG nor WebP.
+var ErrUnsupported = errors.New("image must be a JPEG, PNG or WebP")
+
+// Encoded is an image written in a format browsers display.
+type Encoded struct {
+	Data        []byte
+	ContentType string
+	Extension   string
+}
+
+// Decode reads a JPEG, PNG or WebP image and turns it upright according to its EXIF orientation.
+func Decode(data []byte) (image.Image, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if format == "jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+	return img, nil
+}
+
+// DecodeConfig reads the dimensions of a JPEG, PNG or WebP image from its header, without decoding the pixels.
+func DecodeConfig(r io.Reader) (image.Config, error) {
+	config, _, err := image.DecodeConfig(r)
+	if errors.Is(err, image.ErrFormat) {
+		return image.Config{}, ErrUnsupported
+	}
+	return config, err
+}
+
+// Resize scales the image down to the given width, keeping its aspect ratio. Images already narrow enough are
+// returned as they are, they are never scaled up.
+func Resize(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() <= width {
+		return img
+	}
+
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+	resized := image.NewNRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(resized, resized.Bounds(), img, bounds, xdraw.Src, nil)
+	return resized
+}
+
+// Encode writes opaque images as JPEG and images with transparency as PNG. Nothing but the pixels is written,
+// so the result carries no metadata.
+func Encode(img image.Image) (Encoded, error) {
+	var buffer bytes.Buffer
+	if isOpaque(img) {
+		if err := jpeg.Encode(&buffer, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return Encoded{}, err
+		}
+		return Encoded{Data: buffer.Bytes(), ContentType: ContentTypeJPEG, Extension: ".jpg"}, nil
+	}
+
+	if err := png.Encode(&buffer, img); err != nil {
+		return Encoded{}, err
+	}
+	return Encoded{Data: buffer.Bytes(), ContentType: ContentTypePNG, Extension: ".png"}, nil
+}
+
+func isOpaque(img image.Image) bool {
+	if opaque, ok := img.(interface{ Opaque() bool }); ok {
+		return opaque.Opaque()
+	}
+	return false
+}
+
+// orient applies one of the eight EXIF orientations, which combine a rotation by a multiple of 90 degrees
+// with an optional mirroring.
+func orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	// orientations 5 to 8 are rotated by 90 degrees one way or the other, which swaps the sides
+	transposed := orientation >= 5
+	dstWidth, dstHeight := width, height
+	if transposed {
+		dstWidth, dstHeight = height, width
+	}
+
+	oriented := image.NewNRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			var srcX, srcY int
+			switch orientation {
+			case 2:
+				srcX, srcY = width-1-x, y
+			case 3:
+				srcX, srcY = width-1-x, height-1-y
+			case 4:
+				srcX, srcY = x, height-1-y
+			case 5:
+				srcX, srcY = y, x
+			case 6:
+				srcX, srcY = y, height-1-x
+			case 7:
+				srcX, srcY = width-1-y, height-1-x
+			case 8:
+				srcX, srcY = width-1-y, x
+			}
+			oriented.Set(x, y, img.At(bounds.Min.X+srcX, bounds.Min.Y+srcY))
+		}
+	}
+	return oriented
+}
@@ -0,0 +1,180 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/require"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// exifSegment is an APP1 segment with a little endian TIFF structure holding only the orientation.
+func exifSegment(orientation uint16) []byte {
+	tiff := []byte("II*\x00\x08\x00\x00\x00")
+	tiff = binary.LittleEndian.AppendUint16(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, exifOrientationTag)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 3)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xff, 0xe1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+func encodeJPEG(t *testing.T, width, height int, orientation uint16) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if y < height/2 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{A: 255})
+			}
+		}
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buffer, img, nil))
+	data := buffer.Bytes()
+	return append(append(append([]byte{}, data[:2]...), exifSegment(orientation)...), data[2:]...)
+}
+
+// commentSegment is a COM segment, standing for the metadata that has to be dropped.
+func commentSegment(comment string) []byte {
+	segment := []byte{0xff, 0xfe}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(comment)+2))
+	return append(segment, comment...)
+}
+
+func TestStripMetadata_JPEG(t *testing.T) {
+	data := encodeJPEG(t, 40, 20, 1)
+	data = append(append(append([]byte{}, data[:2]...), commentSegment("shot at 50.45N 30.52E")...), data[2:]...)
+
+	stripped, err := StripMetadata(data, ContentTypeJPEG)
+	require.NoError(t, err)
+	require.NotContains(t, string(stripped), "Exif")
+	require.NotContains(t, string(stripped), "50.45N")
+	require.Len(t, stripped, len(data)-len(exifSegment(1))-len(commentSegment("shot at 50.45N 30.52E")))
+
+	img, err := jpeg.Decode(bytes.NewReader(stripped))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 40, 20), img.Bounds())
+}
+
+func TestStripMetadata_JPEGKeepsOrientation(t *testing.T) {
+	data := encodeJPEG(t, 40, 20, 6)
+	require.Equal(t, 6, jpegOrientation(data))
+
+	stripped, err := StripMetadata(data, ContentTypeJPEG)
+	require.NoError(t, err)
+	require.Equal(t, 6, jpegOrientation(stripped))
+	require.Equal(t, orientationSegment(6), stripped[2:2+len(orientationSegment(6))])
+
+	// stripping again changes nothing
+	again, err := StripMetadata(stripped, ContentTypeJPEG)
+	require.NoError(t, err)
+	require.Equal(t, stripped, again)
+
+	img, err := Decode(stripped)
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 20, 40), img.Bounds())
+}
+
+func TestStripMetadata_PNG(t *testing.T) {
+	var buffer bytes.Buffer
+	require.NoError(t, png.Encode(&buffer, image.NewNRGBA(image.Rect(0, 0, 4, 4))))
+	data := buffer.Bytes()
+
+	text := []byte("tEXtAuthor\x00Jane Doe")
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(text)-4))
+	chunk = append(chunk, text...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(text))
+	// right after the IHDR chunk
+	headerEnd := len(pngSignature) + 25
+	withText := append(append(append([]byte{}, data[:headerEnd]...), chunk...), data[headerEnd:]...)
+
+	stripped, err := StripMetadata(withText, ContentTypePNG)
+	require.NoError(t, err)
+	require.Equal(t, data, stripped)
+}
+
+func TestStripMetadata_WebP(t *testing.T) {
+	riffChunk := func(fourCC string, data []byte) []byte {
+		chunk := append([]byte(fourCC), binary.LittleEndian.AppendUint32(nil, uint32(len(data)))...)
+		chunk = append(chunk, data...)
+		if len(data)%2 == 1 {
+			chunk = append(chunk, 0)
+		}
+		return chunk
+	}
+	riff := func(chunks ...[]byte) []byte {
+		body := []byte("WEBP")
+		for _, chunk := range chunks {
+			body = append(body, chunk...)
+		}
+		return append(append([]byte("RIFF"), binary.LittleEndian.AppendUint32(nil, uint32(len(body)))...), body...)
+	}
+
+	header := []byte{webpEXIFFlag | webpXMPFlag | 0x10, 0, 0, 0, 3, 0, 0, 3, 0, 0}
+	cleanHeader := []byte{0x10, 0, 0, 0, 3, 0, 0, 3, 0, 0}
+	bitstream := riffChunk("VP8 ", []byte("frame"))
+	data := riff(riffChunk("VP8X", header), bitstream, riffChunk("EXIF", []byte("exif")), riffChunk("XMP ", []byte("<xmp/>")))
+
+	stripped, err := StripMetadata(data, ContentTypeWebP)
+	require.NoError(t, err)
+	require.Equal(t, riff(riffChunk("VP8X", cleanHeader), bitstream), stripped)
+}
+
+func TestStripMetadata_Malformed(t *testing.T) {
+	_, err := StripMetadata([]byte("not an image"), ContentTypeJPEG)
+	require.ErrorIs(t, err, errMalformed)
+
+	_, err = StripMetadata([]byte("GIF89a"), "image/gif")
+	require.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestDecode_AppliesOrientation(t *testing.T) {
+	img, err := Decode(encodeJPEG(t, 40, 20, 6))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 20, 40), img.Bounds())
+
+	// the red top half ends up as the right half once turned clockwise
+	r, _, _, _ := img.At(15, 20).RGBA()
+	require.Greater(t, r, uint32(0xc000))
+	r, _, _, _ = img.At(4, 20).RGBA()
+	require.Less(t, r, uint32(0x4000))
+}
+
+func TestResize(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 400, 300))
+
+	require.Equal(t, image.Rect(0, 0, 100, 75), Resize(img, 100).Bounds())
+	// never scaled up
+	require.Equal(t, img, Resize(img, 800))
+}
+
+func TestEncode(t *testing.T) {
+	opaque := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	for x := 0; x < 2; x++ {
+		for y := 0; y < 2; y++ {
+			opaque.Set(x, y, color.RGBA{G: 255, A: 255})
+		}
+	}
+
+	encoded, err := Encode(opaque)
+	require.NoError(t, err)
+	require.Equal(t, ContentTypeJPEG, encoded.ContentType)
+	require.Equal(t, ".jpg", encoded.Extension)
+
+	encoded, err = Encode(image.NewNRGBA(image.Rect(0, 0, 2, 2)))
+	require.NoError(t, err)
+	require.Equal(t, ContentTypePNG, encoded.ContentType)
+	require.Equal(t, ".png", encoded.Extension)
+}
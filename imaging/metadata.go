@@ -0,0 +1,210 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+var errMalformed = errors.New("malformed image file")
+
+// StripMetadata removes EXIF, XMP, IPTC and text metadata from a JPEG, PNG or WebP file without re-encoding it,
+// so that the pixels stay untouched while location, camera and other personal details are dropped.
+// The EXIF orientation of a JPEG is the only detail kept, without it the image would be displayed sideways.
+func StripMetadata(data []byte, contentType string) ([]byte, error) {
+	switch contentType {
+	case ContentTypeJPEG:
+		return stripJPEG(data)
+	case ContentTypePNG:
+		return stripPNG(data)
+	case ContentTypeWebP:
+		return stripWebP(data)
+	}
+	return nil, ErrUnsupported
+}
+
+// jpegMetadataMarkers are the segments holding EXIF and XMP (APP1), IPTC (APP13) and comments.
+var jpegMetadataMarkers = map[byte]bool{0xe1: true, 0xed: true, 0xfe: true}
+
+func stripJPEG(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0xff || data[1] != 0xd8 {
+		return nil, errMalformed
+	}
+
+	orientation := jpegOrientation(data)
+	stripped := bytes.NewBuffer(make([]byte, 0, len(data)))
+	stripped.Write(data[:2])
+	for offset := 2; ; {
+		if offset+4 > len(data) || data[offset] != 0xff {
+			return nil, errMalformed
+		}
+		marker := data[offset+1]
+		// the orientation goes right after the JFIF header, which has to come first
+		if orientation > 1 && marker != 0xe0 {
+			stripped.Write(orientationSegment(orientation))
+			orientation = 1
+		}
+		// the entropy coded data follows the start of scan, everything from there on is kept as is
+		if marker == 0xda {
+			stripped.Write(data[offset:])
+			return stripped.Bytes(), nil
+		}
+
+		end := offset + 2 + int(binary.BigEndian.Uint16(data[offset+2:]))
+		if end > len(data) {
+			return nil, errMalformed
+		}
+		if !jpegMetadataMarkers[marker] {
+			stripped.Write(data[offset:end])
+		}
+		offset = end
+	}
+}
+
+// orientationSegment is an APP1 segment with EXIF data holding nothing but the orientation.
+func orientationSegment(orientation int) []byte {
+	segment := []byte{0xff, 0xe1, 0, 34}
+	segment = append(segment, "Exif\x00\x00"...)
+	// big endian TIFF header pointing to the first IFD right after it
+	segment = append(segment, "MM\x00\x2a\x00\x00\x00\x08"...)
+	// a single entry: the orientation as one SHORT, then no next IFD
+	segment = binary.BigEndian.AppendUint16(segment, 1)
+	segment = binary.BigEndian.AppendUint16(segment, exifOrientationTag)
+	segment = binary.BigEndian.AppendUint16(segment, 3)
+	segment = binary.BigEndian.AppendUint32(segment, 1)
+	segment = binary.BigEndian.AppendUint16(segment, uint16(orientation))
+	return append(segment, 0, 0, 0, 0, 0, 0)
+}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// pngMetadataChunks are the ancillary chunks holding EXIF, text and the modification time.
+var pngMetadataChunks = map[string]bool{"eXIf": true, "tEXt": true, "zTXt": true, "iTXt": true, "tIME": true}
+
+func stripPNG(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, errMalformed
+	}
+
+	stripped := bytes.NewBuffer(make([]byte, 0, len(data)))
+	stripped.Write(pngSignature)
+	for offset := len(pngSignature); offset < len(data); {
+		if offset+8 > len(data) {
+			return nil, errMalformed
+		}
+		// length, type, data and CRC
+		end := offset + 12 + int(binary.BigEndian.Uint32(data[offset:]))
+		if end > len(data) || end < offset {
+			return nil, errMalformed
+		}
+		if !pngMetadataChunks[string(data[offset+4:offset+8])] {
+			stripped.Write(data[offset:end])
+		}
+		offset = end
+	}
+	return stripped.Bytes(), nil
+}
+
+const (
+	webpXMPFlag  = 0x04
+	webpEXIFFlag = 0x08
+)
+
+func stripWebP(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, errMalformed
+	}
+
+	stripped := bytes.NewBuffer(make([]byte, 0, len(data)))
+	stripped.Write(data[:12])
+	for offset := 12; offset < len(data); {
+		if offset+8 > len(data) {
+			return nil, errMalformed
+		}
+		size := int(binary.LittleEndian.Uint32(data[offset+4:]))
+		// chunks are padded to an even size
+		end := offset + 8 + size + size%2
+		if end > len(data) || end < offset {
+			return nil, errMalformed
+		}
+
+		switch fourCC := string(data[offset : offset+4]); fourCC {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := append([]byte(nil), data[offset:end]...)
+			if len(chunk) > 8 {
+				chunk[8] &^= webpEXIFFlag | webpXMPFlag
+			}
+			stripped.Write(chunk)
+		default:
+			stripped.Write(data[offset:end])
+		}
+		offset = end
+	}
+
+	result := stripped.Bytes()
+	binary.LittleEndian.PutUint32(result[4:], uint32(len(result)-8))
+	return result, nil
+}
+
+// jpegOrientation reads the EXIF orientation of a JPEG file, 1 meaning the pixels are stored upright.
+func jpegOrientation(data []byte) int {
+	if len(data) < 2 || data[0] != 0xff || data[1] != 0xd8 {
+		return 1
+	}
+
+	for offset := 2; offset+4 <= len(data) && data[offset] == 0xff; {
+		marker := data[offset+1]
+		if marker == 0xda {
+			break
+		}
+		end := offset + 2 + int(binary.BigEndian.Uint16(data[offset+2:]))
+		if end > len(data) {
+			break
+		}
+		segment := data[offset+4 : end]
+		if marker == 0xe1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		offset = end
+	}
+	return 1
+}
+
+const exifOrientationTag = 0x0112
+
+// exifOrientation looks the orientation tag up in the first IFD of the TIFF structure holding EXIF data.
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) || ifd < 0 {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == exifOrientationTag {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
@@ -0,0 +1,77 @@
+// Package markup renders post content to HTML that is safe to embed into pages. Whatever the source format,
+// the result is sanitized against an allowlist of elements and attributes, so scripts, styles, event handlers
+// and javascript: links never reach readers.
+package markup
+
+import (
+	"bytes"
+	"errors"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+	stdhtml "html"
+	"regexp"
+	"strings"
+)
+
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// ErrUnknownFormat is returned for content that is neither plain text, Markdown nor HTML.
+var ErrUnknownFormat = errors.New("content format must be plain, markdown or html")
+
+var (
+	// markdown passes the HTML embedded in Markdown through, it is sanitized along with the rest
+	markdown = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+	policy = newPolicy()
+
+	blankLines = regexp.MustCompile(`\n([ \t]*\n)+`)
+)
+
+// Render turns the content written in the given format into sanitized HTML.
+func Render(format string, content string) (string, error) {
+	switch format {
+	case FormatPlain:
+		return renderPlain(content), nil
+	case FormatMarkdown:
+		var buffer bytes.Buffer
+		if err := markdown.Convert([]byte(content), &buffer); err != nil {
+			return "", err
+		}
+		return policy.Sanitize(buffer.String()), nil
+	case FormatHTML:
+		return policy.Sanitize(content), nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// newPolicy allows the formatting, links, images and tables user generated content needs,
+// along with the disabled checkboxes of Markdown task lists.
+func newPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+	return policy
+}
+
+// renderPlain escapes the text and makes a paragraph of every block of lines separated by blank lines.
+// The migration adding the rendered content does the same for the posts written before formats existed.
+func renderPlain(content string) string {
+	content = stdhtml.EscapeString(strings.ReplaceAll(content, "\r\n", "\n"))
+
+	paragraphs := make([]string, 0)
+	for _, paragraph := range blankLines.Split(content, -1) {
+		if len(strings.Trim(paragraph, " \t\n")) == 0 {
+			continue
+		}
+		paragraphs = append(paragraphs, "<p>"+strings.Trim(paragraph, "\n")+"</p>")
+	}
+	return strings.Join(paragraphs, "\n")
+}
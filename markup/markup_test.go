@@ -0,0 +1,59 @@
+package markup
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	testCases := []struct {
+		name     string
+		format   string
+		content  string
+		expected string
+	}{
+		{
+			name:     "positive_Render_Plain",
+			format:   FormatPlain,
+			content:  "Fish & <chips>\r\nwith \"salt\"\n \n\n\nIt's 'fine'\n\n   \n",
+			expected: "<p>Fish &amp; &lt;chips&gt;\nwith &#34;salt&#34;</p>\n<p>It&#39;s &#39;fine&#39;</p>",
+		},
+		{
+			name:     "positive_Render_PlainEmpty",
+			format:   FormatPlain,
+			content:  "\n\n  \n",
+			expected: "",
+		},
+		{
+			name:     "positive_Render_Markdown",
+			format:   FormatMarkdown,
+			content:  "# Title\n\nSome **bold** text and a [link](https://example.com).\n\n- [x] done\n",
+			expected: "<h1>Title</h1>\n<p>Some <strong>bold</strong> text and a <a href=\"https://example.com\" rel=\"nofollow\">link</a>.</p>\n<ul>\n<li><input checked=\"\" disabled=\"\" type=\"checkbox\"> done</li>\n</ul>\n",
+		},
+		{
+			name:     "positive_Render_MarkdownEmbeddedHTML",
+			format:   FormatMarkdown,
+			content:  "Hello <script>alert(1)</script><em onclick=\"steal()\">world</em>\n\n[click](javascript:alert(1))",
+			expected: "<p>Hello <em>world</em></p>\n<p>click</p>\n",
+		},
+		{
+			name:     "positive_Render_HTML",
+			format:   FormatHTML,
+			content:  "<p style=\"color: red\">Hi <img src=\"x\" onerror=\"steal()\"></p><iframe src=\"https://evil.example\"></iframe>",
+			expected: "<p>Hi <img src=\"x\"></p>",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			rendered, err := Render(testCase.format, testCase.content)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, rendered)
+		})
+	}
+}
+
+func TestRender_UnknownFormat(t *testing.T) {
+	_, err := Render("rtf", "{\\rtf1 hello}")
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"promova-test-task/util"
+)
+
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// BlobStore keeps uploaded files under slash separated keys and tells where clients can download them.
+type BlobStore interface {
+	// Put stores size bytes read from body under the key, replacing what was stored there.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get opens the blob stored under the key.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under the key. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL is where clients download the blob stored under the key.
+	URL(key string) string
+}
+
+// NewBlobStore creates the blob store selected by the configuration.
+func NewBlobStore(config util.Config) (BlobStore, error) {
+	switch config.BlobBackend {
+	case BackendLocal:
+		return NewLocalStore(config.BlobLocalDir)
+	case BackendS3:
+		return NewS3Store(S3Config{
+			Endpoint:  config.S3Endpoint,
+			Region:    config.S3Region,
+			Bucket:    config.S3Bucket,
+			AccessKey: config.S3AccessKey,
+			SecretKey: config.S3SecretKey,
+			UseSSL:    config.S3UseSSL,
+			PublicURL: config.S3PublicURL,
+		})
+	}
+	return nil, fmt.Errorf("unknown blob backend %q", config.BlobBackend)
+}
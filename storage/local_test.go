@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	store, err := NewLocalStore(filepath.Join(t.TempDir(), "media"))
+	require.NoError(t, err)
+
+	key := "posts/1/cover.png"
+	err = store.Put(context.Background(), key, strings.NewReader("image"), 5, "image/png")
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(store.Dir(), "posts", "1", "cover.png"))
+	require.NoError(t, err)
+	require.Equal(t, "image", string(data))
+	require.Equal(t, "/media/posts/1/cover.png", store.URL(key))
+
+	reader, err := store.Get(context.Background(), key)
+	require.NoError(t, err)
+	data, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.Equal(t, "image", string(data))
+
+	require.NoError(t, store.Delete(context.Background(), key))
+	_, err = os.Stat(filepath.Join(store.Dir(), "posts", "1", "cover.png"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	// deleting twice is fine
+	require.NoError(t, store.Delete(context.Background(), key))
+}
+
+func TestLocalStore_ShortBody(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	err = store.Put(context.Background(), "short.txt", strings.NewReader("abc"), 10, "text/plain")
+	require.Error(t, err)
+
+	entries, err := os.ReadDir(store.Dir())
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestLocalStore_InvalidKey(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "/etc/passwd", "../outside", "posts/../../outside", "posts//1"} {
+		err = store.Put(context.Background(), key, strings.NewReader("x"), 1, "text/plain")
+		require.ErrorIs(t, err, errInvalidKey, key)
+	}
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type fakeObject struct {
+	data        []byte
+	contentType string
+}
+
+// fakeS3 is a stand-in for an S3 compatible service, keeping the objects of path-style requests in memory.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]fakeObject
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := readPayload(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.objects[r.URL.Path] = fakeObject{data: data, contentType: r.Header.Get("Content-Type")}
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		object, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", object.contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(object.data)))
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", "Wed, 01 May 2024 12:00:00 GMT")
+		_, _ = w.Write(object.data)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// readPayload reads the request body, decoding it when it is signed chunk by chunk, as clients do over plain HTTP.
+func readPayload(r *http.Request) ([]byte, error) {
+	if r.Header.Get("X-Amz-Content-Sha256") != "STREAMING-AWS4-HMAC-SHA256-PAYLOAD" {
+		return io.ReadAll(r.Body)
+	}
+
+	var data bytes.Buffer
+	reader := bufio.NewReader(r.Body)
+	for {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.ParseInt(strings.SplitN(strings.TrimSpace(header), ";", 2)[0], 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return data.Bytes(), nil
+		}
+		if _, err = io.CopyN(&data, reader, size); err != nil {
+			return nil, err
+		}
+		if _, err = reader.Discard(2); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	fake := &fakeS3{objects: make(map[string]fakeObject)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	endpoint, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	store, err := NewS3Store(S3Config{
+		Endpoint:  endpoint.Host,
+		Region:    "us-east-1",
+		Bucket:    "media",
+		AccessKey: "access",
+		SecretKey: "secret",
+	})
+	require.NoError(t, err)
+
+	key := "posts/1/clip.mp4"
+	err = store.Put(context.Background(), key, strings.NewReader("video"), 5, "video/mp4")
+	require.NoError(t, err)
+	require.Equal(t, fakeObject{data: []byte("video"), contentType: "video/mp4"}, fake.objects["/media/"+key])
+	require.Equal(t, server.URL+"/media/"+key, store.URL(key))
+
+	reader, err := store.Get(context.Background(), key)
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.Equal(t, "video", string(data))
+
+	require.NoError(t, store.Delete(context.Background(), key))
+	require.Empty(t, fake.objects)
+}
+
+func TestS3Store_PublicURL(t *testing.T) {
+	store, err := NewS3Store(S3Config{
+		Endpoint:  "s3.example.com",
+		Bucket:    "media",
+		UseSSL:    true,
+		PublicURL: "https://cdn.example.com/",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://cdn.example.com/posts/1/cover.png", store.URL("posts/1/cover.png"))
+
+	store, err = NewS3Store(S3Config{Endpoint: "s3.example.com", Bucket: "media", UseSSL: true})
+	require.NoError(t, err)
+	require.Equal(t, "https://s3.example.com/media/posts/1/cover.png", store.URL("posts/1/cover.png"))
+}
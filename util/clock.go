@@ -0,0 +1,15 @@
+package util
+
+import "time"
+
+// Clock tells the current time, so that time-dependent code can be tested with a fixed one.
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock is the wall clock.
+type RealClock struct{}
+
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
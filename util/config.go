@@ -3,15 +3,32 @@ package util
 import (
 	"github.com/spf13/viper"
 	"log"
+	"time"
 )
 
 // Config stores all configuration of the application
 // The values are read by viper from a config file or environment variables.
 type Config struct {
-	DBDriver      string `mapstructure:"DB_DRIVER"`
-	DBSource      string `mapstructure:"DB_SOURCE"`
-	MigrationURL  string `mapstructure:"MIGRATION_URL"`
-	ServerAddress string `mapstructure:"SERVER_ADDRESS"`
+	DBDriver            string        `mapstructure:"DB_DRIVER"`
+	DBSource            string        `mapstructure:"DB_SOURCE"`
+	MigrationURL        string        `mapstructure:"MIGRATION_URL"`
+	ServerAddress       string        `mapstructure:"SERVER_ADDRESS"`
+	BaseURL             string        `mapstructure:"BASE_URL"`
+	PublishPollInterval time.Duration `mapstructure:"PUBLISH_POLL_INTERVAL"`
+	ViewFlushInterval   time.Duration `mapstructure:"VIEW_FLUSH_INTERVAL"`
+	AdminToken          string        `mapstructure:"ADMIN_TOKEN"`
+	AttachmentMaxSize   int64         `mapstructure:"ATTACHMENT_MAX_SIZE"`
+	CoverMaxSize        int64         `mapstructure:"COVER_MAX_SIZE"`
+	CoverPollInterval   time.Duration `mapstructure:"COVER_POLL_INTERVAL"`
+	BlobBackend         string        `mapstructure:"BLOB_BACKEND"`
+	BlobLocalDir        string        `mapstructure:"BLOB_LOCAL_DIR"`
+	S3Endpoint          string        `mapstructure:"S3_ENDPOINT"`
+	S3Region            string        `mapstructure:"S3_REGION"`
+	S3Bucket            string        `mapstructure:"S3_BUCKET"`
+	S3AccessKey         string        `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey         string        `mapstructure:"S3_SECRET_KEY"`
+	S3UseSSL            bool          `mapstructure:"S3_USE_SSL"`
+	S3PublicURL         string        `mapstructure:"S3_PUBLIC_URL"`
 }
 
 // LoadConfig reads configuration from a file or environment variables.
@@ -20,6 +37,23 @@ func LoadConfig(pathToConfigFile string) (config Config, err error) {
 	viper.SetConfigName("app")
 	viper.SetConfigType("env")
 
+	viper.SetDefault("BASE_URL", "http://localhost:8080")
+	viper.SetDefault("PUBLISH_POLL_INTERVAL", 10*time.Second)
+	viper.SetDefault("VIEW_FLUSH_INTERVAL", 5*time.Second)
+	viper.SetDefault("ADMIN_TOKEN", "")
+	viper.SetDefault("ATTACHMENT_MAX_SIZE", 10<<20)
+	viper.SetDefault("COVER_MAX_SIZE", 10<<20)
+	viper.SetDefault("COVER_POLL_INTERVAL", 2*time.Second)
+	viper.SetDefault("BLOB_BACKEND", "local")
+	viper.SetDefault("BLOB_LOCAL_DIR", "media")
+	viper.SetDefault("S3_ENDPOINT", "")
+	viper.SetDefault("S3_REGION", "us-east-1")
+	viper.SetDefault("S3_BUCKET", "")
+	viper.SetDefault("S3_ACCESS_KEY", "")
+	viper.SetDefault("S3_SECRET_KEY", "")
+	viper.SetDefault("S3_USE_SSL", true)
+	viper.SetDefault("S3_PUBLIC_URL", "")
+
 	// overrides read values from config file with env vars if such exist
 	viper.AutomaticEnv()
 
@@ -29,6 +63,19 @@ func LoadConfig(pathToConfigFile string) (config Config, err error) {
 	if err = viper.Unmarshal(&config); err != nil {
 		log.Fatalf("unable to decode into struct, %v", err)
 	}
-	log.Printf("Loaded config: %+v", config)
+	log.Printf("Loaded config: %+v", config.redacted())
 	return
 }
+
+// redactedValue stands in for the secrets of a config that is logged
+const redactedValue = "[REDACTED]"
+
+// redacted returns a copy of the config fit for logging, with the secrets left out.
+func (c Config) redacted() Config {
+	for _, secret := range []*string{&c.AdminToken, &c.S3AccessKey, &c.S3SecretKey} {
+		if len(*secret) > 0 {
+			*secret = redactedValue
+		}
+	}
+	return c
+}
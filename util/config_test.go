@@ -0,0 +1,28 @@
+package util
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestConfigRedacted(t *testing.T) {
+	config := Config{
+		ServerAddress: "0.0.0.0:8080",
+		AdminToken:    "admin-secret",
+		S3Bucket:      "media",
+		S3AccessKey:   "access-secret",
+		S3SecretKey:   "secret-secret",
+	}
+
+	redacted := config.redacted()
+	require.Equal(t, redactedValue, redacted.AdminToken)
+	require.Equal(t, redactedValue, redacted.S3AccessKey)
+	require.Equal(t, redactedValue, redacted.S3SecretKey)
+	require.Equal(t, config.ServerAddress, redacted.ServerAddress)
+	require.Equal(t, config.S3Bucket, redacted.S3Bucket)
+	require.NotContains(t, fmt.Sprintf("%+v", redacted), "-secret")
+	require.Equal(t, "admin-secret", config.AdminToken)
+
+	require.Empty(t, Config{}.redacted().S3SecretKey)
+}
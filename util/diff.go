@@ -0,0 +1,83 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxDiffLines is the most lines either text may have, as LineDiff keeps a table of every pair of their lines
+const MaxDiffLines = 1000
+
+var ErrDiffTooLarge = fmt.Errorf("only texts of up to %d lines can be compared", MaxDiffLines)
+
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffLine is a single line of a line-level diff.
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// LineDiff compares a and b line by line and returns the edit script turning a into b,
+// based on their longest common subsequence of lines. Texts longer than MaxDiffLines return ErrDiffTooLarge.
+func LineDiff(a string, b string) ([]DiffLine, error) {
+	from := splitLines(a)
+	to := splitLines(b)
+	if len(from) > MaxDiffLines || len(to) > MaxDiffLines {
+		return nil, ErrDiffTooLarge
+	}
+
+	// common[i][j] is the length of the longest common subsequence of from[i:] and to[j:]
+	common := make([][]int, len(from)+1)
+	for i := range common {
+		common[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else if common[i+1][j] >= common[i][j+1] {
+				common[i][j] = common[i+1][j]
+			} else {
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	diff := make([]DiffLine, 0, len(from)+len(to))
+	i, j := 0, 0
+	for i < len(from) && j < len(to) {
+		switch {
+		case from[i] == to[j]:
+			diff = append(diff, DiffLine{Op: DiffEqual, Text: from[i]})
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			diff = append(diff, DiffLine{Op: DiffDelete, Text: from[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Op: DiffInsert, Text: to[j]})
+			j++
+		}
+	}
+	for ; i < len(from); i++ {
+		diff = append(diff, DiffLine{Op: DiffDelete, Text: from[i]})
+	}
+	for ; j < len(to); j++ {
+		diff = append(diff, DiffLine{Op: DiffInsert, Text: to[j]})
+	}
+	return diff, nil
+}
+
+func splitLines(s string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
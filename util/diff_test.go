@@ -0,0 +1,65 @@
+package util
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestLineDiff(t *testing.T) {
+	testCases := []struct {
+		name     string
+		from     string
+		to       string
+		expected []DiffLine
+	}{
+		{
+			name:     "positive_LineDiff_Equal",
+			from:     "first\nsecond",
+			to:       "first\nsecond\n",
+			expected: []DiffLine{{Op: DiffEqual, Text: "first"}, {Op: DiffEqual, Text: "second"}},
+		},
+		{
+			name: "positive_LineDiff_Changed",
+			from: "first\nsecond\nthird",
+			to:   "first\nchanged\nthird\nfourth",
+			expected: []DiffLine{
+				{Op: DiffEqual, Text: "first"},
+				{Op: DiffDelete, Text: "second"},
+				{Op: DiffInsert, Text: "changed"},
+				{Op: DiffEqual, Text: "third"},
+				{Op: DiffInsert, Text: "fourth"},
+			},
+		},
+		{
+			name:     "positive_LineDiff_FromEmpty",
+			from:     "",
+			to:       "only",
+			expected: []DiffLine{{Op: DiffInsert, Text: "only"}},
+		},
+		{
+			name:     "positive_LineDiff_ToEmpty",
+			from:     "only",
+			to:       "",
+			expected: []DiffLine{{Op: DiffDelete, Text: "only"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			diff, err := LineDiff(testCase.from, testCase.to)
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, diff)
+		})
+	}
+}
+
+func TestLineDiff_TooLarge(t *testing.T) {
+	long := strings.Repeat("line\n", MaxDiffLines+1)
+
+	_, err := LineDiff("short", long)
+	require.ErrorIs(t, err, ErrDiffTooLarge)
+
+	_, err = LineDiff(long, "short")
+	require.ErrorIs(t, err, ErrDiffTooLarge)
+}
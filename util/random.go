@@ -23,3 +23,23 @@ func GenerateRandomPost() RandomPost {
 		UpdatedAt: time.Now(),
 	}
 }
+
+type RandomAuthor struct {
+	ID        int32
+	Name      string
+	Email     string
+	Bio       string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func GenerateRandomAuthor() RandomAuthor {
+	return RandomAuthor{
+		ID:        rand.Int31(),
+		Name:      faker.Name(),
+		Email:     faker.Email(),
+		Bio:       faker.Sentence(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,46 @@
+package util
+
+import (
+	"fmt"
+	"github.com/gosimple/slug"
+	"strings"
+)
+
+const (
+	maxSlugLength = 80
+	fallbackSlug  = "post"
+)
+
+// Slugify turns a title into a lowercase, hyphen separated slug, transliterating letters outside of ASCII.
+// Long titles are cut at a word boundary, and titles without a single letter or digit become "post".
+func Slugify(title string) string {
+	s := slug.Make(title)
+	if len(s) > maxSlugLength {
+		if i := strings.LastIndex(s[:maxSlugLength+1], "-"); i > 0 {
+			s = s[:i]
+		} else {
+			s = s[:maxSlugLength]
+		}
+	}
+	if len(s) == 0 {
+		return fallbackSlug
+	}
+	return s
+}
+
+// UniqueSlug returns base when it is not taken yet, otherwise base with the lowest free suffix starting at -2.
+func UniqueSlug(base string, taken []string) string {
+	takenSet := make(map[string]bool, len(taken))
+	for _, s := range taken {
+		takenSet[s] = true
+	}
+	if !takenSet[base] {
+		return base
+	}
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", base, n)
+		if !takenSet[candidate] {
+			return candidate
+		}
+	}
+}
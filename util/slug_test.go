@@ -0,0 +1,57 @@
+package util
+
+import (
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	testCases := []struct {
+		name     string
+		title    string
+		expected string
+	}{
+		{
+			name:     "positive_Slugify_Latin",
+			title:    "  Hello, World! Release 2.0  ",
+			expected: "hello-world-release-2-0",
+		},
+		{
+			name:     "positive_Slugify_Transliterates",
+			title:    "Привет мир",
+			expected: "privet-mir",
+		},
+		{
+			name:     "positive_Slugify_Diacritics",
+			title:    "Crème brûlée",
+			expected: "creme-brulee",
+		},
+		{
+			name:     "positive_Slugify_NoLetters",
+			title:    "?!",
+			expected: "post",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			require.Equal(t, testCase.expected, Slugify(testCase.title))
+		})
+	}
+}
+
+func TestSlugify_CutsLongTitlesAtWordBoundary(t *testing.T) {
+	slug := Slugify(strings.Repeat("word ", 30))
+
+	require.LessOrEqual(t, len(slug), maxSlugLength)
+	require.True(t, strings.HasSuffix(slug, "-word"))
+}
+
+func TestUniqueSlug(t *testing.T) {
+	require.Equal(t, "hello", UniqueSlug("hello", nil))
+	require.Equal(t, "hello", UniqueSlug("hello", []string{"hello-2"}))
+	require.Equal(t, "hello-2", UniqueSlug("hello", []string{"hello"}))
+	require.Equal(t, "hello-4", UniqueSlug("hello", []string{"hello-3", "hello", "hello-2"}))
+	require.Equal(t, "hello-3", UniqueSlug("hello", []string{"hello", "hello-2", "hello-4"}))
+}
@@ -0,0 +1,203 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"path"
+	db "promova-test-task/db/sqlc"
+	"promova-test-task/imaging"
+	"promova-test-task/storage"
+	"promova-test-task/util"
+	"sync"
+	"time"
+)
+
+// coverClaimTimeout is how long a cover may stay claimed before another processor takes it over,
+// assuming the one that claimed it died.
+const coverClaimTimeout = 5 * time.Minute
+
+type coverVariant struct {
+	name  string
+	width int
+}
+
+// coverVariants are the sizes covers are resized to, for lists, post pages and full width headers.
+var coverVariants = []coverVariant{
+	{name: "thumbnail", width: 320},
+	{name: "medium", width: 960},
+	{name: "large", width: 1920},
+}
+
+// CoverProcessor strips the metadata of uploaded covers and generates their resized variants in the background,
+// so that uploads return as soon as the original is stored. Covers are claimed with FOR UPDATE SKIP LOCKED,
+// so several replicas can poll the same table without processing a cover twice.
+type CoverProcessor struct {
+	store    db.Store
+	blobs    storage.BlobStore
+	clock    util.Clock
+	interval time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewCoverProcessor creates a cover processor polling for uploaded covers every interval.
+func NewCoverProcessor(store db.Store, blobs storage.BlobStore, clock util.Clock, interval time.Duration) *CoverProcessor {
+	return &CoverProcessor{
+		store:    store,
+		blobs:    blobs,
+		clock:    clock,
+		interval: interval,
+	}
+}
+
+// Start polls in the background until Stop is called or ctx is done.
+func (p *CoverProcessor) Start(ctx context.Context) {
+	ctx, p.cancel = context.WithCancel(ctx)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := p.ProcessPending(ctx); err != nil && ctx.Err() == nil {
+				log.Println("failed to process covers:", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops polling and waits for the cover in flight to finish.
+func (p *CoverProcessor) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+}
+
+// ProcessPending processes the covers waiting for their variants and returns how many were processed.
+// A cover left claimed by an error is taken over again once its claim times out.
+func (p *CoverProcessor) ProcessPending(ctx context.Context) (int, error) {
+	processed := 0
+	for {
+		now := p.clock.Now()
+		cover, err := p.store.ClaimPostCover(ctx, db.ClaimPostCoverParams{
+			Now:         now,
+			StaleBefore: now.Add(-coverClaimTimeout),
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return processed, nil
+			}
+			return processed, err
+		}
+
+		if err = p.process(ctx, cover); err != nil {
+			return processed, err
+		}
+		processed++
+	}
+}
+
+// process replaces the original with a copy without metadata and stores the variants next to it.
+// Images that cannot be read mark the cover as failed, since processing them again would not help.
+func (p *CoverProcessor) process(ctx context.Context, cover db.PostCover) error {
+	original, err := p.readBlob(ctx, cover.BlobKey)
+	if err != nil {
+		return err
+	}
+
+	stripped, err := imaging.StripMetadata(original, cover.ContentType)
+	if err != nil {
+		return p.fail(ctx, cover, err)
+	}
+	img, err := imaging.Decode(original)
+	if err != nil {
+		return p.fail(ctx, cover, err)
+	}
+
+	if err = p.blobs.Put(ctx, cover.BlobKey, bytes.NewReader(stripped), int64(len(stripped)), cover.ContentType); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(coverVariants))
+	for _, variant := range coverVariants {
+		encoded, err := imaging.Encode(imaging.Resize(img, variant.width))
+		if err != nil {
+			return err
+		}
+
+		key := path.Join(path.Dir(cover.BlobKey), variant.name+encoded.Extension)
+		if err = p.blobs.Put(ctx, key, bytes.NewReader(encoded.Data), int64(len(encoded.Data)), encoded.ContentType); err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+
+	bounds := img.Bounds()
+	completed, err := p.store.CompletePostCover(ctx, db.CompletePostCoverParams{
+		PostID:       cover.PostID,
+		BlobKey:      cover.BlobKey,
+		Width:        int32(bounds.Dx()),
+		Height:       int32(bounds.Dy()),
+		ThumbnailKey: sql.NullString{String: keys[0], Valid: true},
+		MediumKey:    sql.NullString{String: keys[1], Valid: true},
+		LargeKey:     sql.NullString{String: keys[2], Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	if completed == 0 {
+		return p.discard(ctx, cover, append(keys, cover.BlobKey))
+	}
+	return nil
+}
+
+// discard removes the blobs written for a cover that was replaced or deleted while it was being processed.
+// Variant keys only depend on the original, so when another processor completed the same cover meanwhile
+// the blobs are the ones it refers to and are kept.
+func (p *CoverProcessor) discard(ctx context.Context, cover db.PostCover, keys []string) error {
+	covers, err := p.store.GetPostCoversByPostIds(ctx, []int32{cover.PostID})
+	if err != nil {
+		return err
+	}
+	if len(covers) > 0 && covers[0].BlobKey == cover.BlobKey {
+		return nil
+	}
+
+	for _, key := range keys {
+		if err = p.blobs.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *CoverProcessor) fail(ctx context.Context, cover db.PostCover, cause error) error {
+	log.Printf("failed to process the cover of post %d: %v", cover.PostID, cause)
+	_, err := p.store.FailPostCover(ctx, db.FailPostCoverParams{PostID: cover.PostID, BlobKey: cover.BlobKey})
+	return err
+}
+
+func (p *CoverProcessor) readBlob(ctx context.Context, key string) ([]byte, error) {
+	reader, err := p.blobs.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
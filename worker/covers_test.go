@@ -0,0 +1,234 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"os"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"promova-test-task/imaging"
+	"testing"
+	"time"
+)
+
+type memoryBlobStore struct {
+	blobs map[string][]byte
+}
+
+func (s *memoryBlobStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	s.blobs[key] = data
+	return nil
+}
+
+func (s *memoryBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := s.blobs[key]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryBlobStore) Delete(ctx context.Context, key string) error {
+	delete(s.blobs, key)
+	return nil
+}
+
+func (s *memoryBlobStore) URL(key string) string {
+	return "/media/" + key
+}
+
+// randomJPEG encodes a photo wider than the large variant, with a comment standing for its metadata.
+func randomJPEG(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 2400, 1200))
+	for x := 0; x < 2400; x++ {
+		img.Set(x, x/2, color.RGBA{R: 255, A: 255})
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buffer, img, nil))
+	data := buffer.Bytes()
+	comment := []byte{0xff, 0xfe, 0, 8, 'g', 'p', 's', ':', '4', '2'}
+	return append(append(append([]byte{}, data[:2]...), comment...), data[2:]...)
+}
+
+func TestProcessPendingCovers(t *testing.T) {
+	clock := fixedClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	claimArg := db.ClaimPostCoverParams{Now: clock.now, StaleBefore: clock.now.Add(-coverClaimTimeout)}
+	cover := db.PostCover{
+		PostID:      7,
+		BlobKey:     "covers/7/abc/original.jpg",
+		ContentType: imaging.ContentTypeJPEG,
+		Width:       2400,
+		Height:      1200,
+		Status:      db.CoverStatusProcessing,
+	}
+	completeArg := db.CompletePostCoverParams{
+		PostID:       cover.PostID,
+		BlobKey:      cover.BlobKey,
+		Width:        2400,
+		Height:       1200,
+		ThumbnailKey: sql.NullString{String: "covers/7/abc/thumbnail.jpg", Valid: true},
+		MediumKey:    sql.NullString{String: "covers/7/abc/medium.jpg", Valid: true},
+		LargeKey:     sql.NullString{String: "covers/7/abc/large.jpg", Valid: true},
+	}
+
+	testCases := []struct {
+		name          string
+		original      []byte
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, blobs *memoryBlobStore, processed int, err error)
+	}{
+		{
+			name: "positive_ProcessPending_NothingPending",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ClaimPostCover(gomock.Any(), gomock.Eq(claimArg)).
+					Times(1).
+					Return(db.PostCover{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, blobs *memoryBlobStore, processed int, err error) {
+				require.NoError(t, err)
+				require.Zero(t, processed)
+			},
+		},
+		{
+			name:     "positive_ProcessPending_Variants",
+			original: randomJPEG(t),
+			buildStubs: func(querier *mockdb.MockStore) {
+				gomock.InOrder(
+					querier.EXPECT().
+						ClaimPostCover(gomock.Any(), gomock.Eq(claimArg)).
+						Times(1).
+						Return(cover, nil),
+					querier.EXPECT().
+						CompletePostCover(gomock.Any(), gomock.Eq(completeArg)).
+						Times(1).
+						Return(int64(1), nil),
+					querier.EXPECT().
+						ClaimPostCover(gomock.Any(), gomock.Eq(claimArg)).
+						Times(1).
+						Return(db.PostCover{}, sql.ErrNoRows),
+				)
+			},
+			checkResponse: func(t *testing.T, blobs *memoryBlobStore, processed int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, processed)
+				require.Len(t, blobs.blobs, 4)
+				require.NotContains(t, string(blobs.blobs[cover.BlobKey]), "gps:42")
+
+				for key, width := range map[string]int{
+					completeArg.ThumbnailKey.String: 320,
+					completeArg.MediumKey.String:    960,
+					completeArg.LargeKey.String:     1920,
+				} {
+					config, err := imaging.DecodeConfig(bytes.NewReader(blobs.blobs[key]))
+					require.NoError(t, err)
+					require.Equal(t, width, config.Width)
+					require.Equal(t, width/2, config.Height)
+				}
+			},
+		},
+		{
+			name:     "positive_ProcessPending_Replaced",
+			original: randomJPEG(t),
+			buildStubs: func(querier *mockdb.MockStore) {
+				gomock.InOrder(
+					querier.EXPECT().
+						ClaimPostCover(gomock.Any(), gomock.Eq(claimArg)).
+						Times(1).
+						Return(cover, nil),
+					querier.EXPECT().
+						CompletePostCover(gomock.Any(), gomock.Eq(completeArg)).
+						Times(1).
+						Return(int64(0), nil),
+					querier.EXPECT().
+						GetPostCoversByPostIds(gomock.Any(), gomock.Eq([]int32{cover.PostID})).
+						Times(1).
+						Return([]db.PostCover{{PostID: cover.PostID, BlobKey: "covers/7/def/original.png"}}, nil),
+					querier.EXPECT().
+						ClaimPostCover(gomock.Any(), gomock.Eq(claimArg)).
+						Times(1).
+						Return(db.PostCover{}, sql.ErrNoRows),
+				)
+			},
+			checkResponse: func(t *testing.T, blobs *memoryBlobStore, processed int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, processed)
+				require.Empty(t, blobs.blobs)
+			},
+		},
+		{
+			name:     "positive_ProcessPending_Unreadable",
+			original: []byte("\xff\xd8 not quite a JPEG"),
+			buildStubs: func(querier *mockdb.MockStore) {
+				gomock.InOrder(
+					querier.EXPECT().
+						ClaimPostCover(gomock.Any(), gomock.Eq(claimArg)).
+						Times(1).
+						Return(cover, nil),
+					querier.EXPECT().
+						FailPostCover(gomock.Any(), gomock.Eq(db.FailPostCoverParams{PostID: cover.PostID, BlobKey: cover.BlobKey})).
+						Times(1).
+						Return(int64(1), nil),
+					querier.EXPECT().
+						ClaimPostCover(gomock.Any(), gomock.Eq(claimArg)).
+						Times(1).
+						Return(db.PostCover{}, sql.ErrNoRows),
+				)
+				querier.EXPECT().
+					CompletePostCover(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, blobs *memoryBlobStore, processed int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, processed)
+				require.Len(t, blobs.blobs, 1)
+			},
+		},
+		{
+			name: "negative_ProcessPending_MissingBlob",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					ClaimPostCover(gomock.Any(), gomock.Eq(claimArg)).
+					Times(1).
+					Return(cover, nil)
+				querier.EXPECT().
+					FailPostCover(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, blobs *memoryBlobStore, processed int, err error) {
+				require.ErrorIs(t, err, os.ErrNotExist)
+				require.Zero(t, processed)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			blobs := &memoryBlobStore{blobs: make(map[string][]byte)}
+			if testCase.original != nil {
+				blobs.blobs[cover.BlobKey] = testCase.original
+			}
+			processor := NewCoverProcessor(store, blobs, clock, time.Minute)
+
+			processed, err := processor.ProcessPending(context.Background())
+			testCase.checkResponse(t, blobs, processed, err)
+		})
+	}
+}
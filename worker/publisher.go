@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"context"
+	"log"
+	db "promova-test-task/db/sqlc"
+	"promova-test-task/util"
+	"sync"
+	"time"
+)
+
+const publishBatchSize = 100
+
+// Publisher makes scheduled posts visible once their publish time comes.
+// Due posts are claimed with FOR UPDATE SKIP LOCKED, so several replicas can poll the same table
+// without publishing a post twice.
+type Publisher struct {
+	store    db.Store
+	clock    util.Clock
+	interval time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewPublisher creates a publisher polling for due posts every interval.
+func NewPublisher(store db.Store, clock util.Clock, interval time.Duration) *Publisher {
+	return &Publisher{
+		store:    store,
+		clock:    clock,
+		interval: interval,
+	}
+}
+
+// Start polls in the background until Stop is called or ctx is done.
+func (p *Publisher) Start(ctx context.Context) {
+	ctx, p.cancel = context.WithCancel(ctx)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := p.PublishDue(ctx); err != nil && ctx.Err() == nil {
+				log.Println("failed to publish scheduled posts:", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops polling and waits for the batch in flight to finish.
+func (p *Publisher) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+}
+
+// PublishDue publishes all posts due by now and returns how many were published.
+func (p *Publisher) PublishDue(ctx context.Context) (int, error) {
+	published := 0
+	for {
+		posts, err := p.store.PublishDuePosts(ctx, db.PublishDuePostsParams{
+			Now:       p.clock.Now(),
+			BatchSize: publishBatchSize,
+		})
+		if err != nil {
+			return published, err
+		}
+		published += len(posts)
+
+		if len(posts) < publishBatchSize {
+			return published, nil
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"testing"
+	"time"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+func TestPublishDue(t *testing.T) {
+	clock := fixedClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	arg := db.PublishDuePostsParams{Now: clock.now, BatchSize: publishBatchSize}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, published int, err error)
+	}{
+		{
+			name: "positive_PublishDue_NothingDue",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					PublishDuePosts(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Post{}, nil)
+			},
+			checkResponse: func(t *testing.T, published int, err error) {
+				require.NoError(t, err)
+				require.Zero(t, published)
+			},
+		},
+		{
+			name: "positive_PublishDue_SeveralBatches",
+			buildStubs: func(querier *mockdb.MockStore) {
+				gomock.InOrder(
+					querier.EXPECT().
+						PublishDuePosts(gomock.Any(), gomock.Eq(arg)).
+						Times(1).
+						Return(make([]db.Post, publishBatchSize), nil),
+					querier.EXPECT().
+						PublishDuePosts(gomock.Any(), gomock.Eq(arg)).
+						Times(1).
+						Return(make([]db.Post, 3), nil),
+				)
+			},
+			checkResponse: func(t *testing.T, published int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, publishBatchSize+3, published)
+			},
+		},
+		{
+			name: "negative_PublishDue_InternalError",
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					PublishDuePosts(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, published int, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+				require.Zero(t, published)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			publisher := NewPublisher(store, clock, time.Minute)
+
+			published, err := publisher.PublishDue(context.Background())
+			testCase.checkResponse(t, published, err)
+		})
+	}
+}
+
+func TestPublisher_StartStop(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	clock := fixedClock{now: time.Now()}
+	polled := make(chan struct{}, 1)
+
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().
+		PublishDuePosts(gomock.Any(), gomock.Eq(db.PublishDuePostsParams{Now: clock.now, BatchSize: publishBatchSize})).
+		MinTimes(1).
+		DoAndReturn(func(ctx context.Context, arg db.PublishDuePostsParams) ([]db.Post, error) {
+			select {
+			case polled <- struct{}{}:
+			default:
+			}
+			return []db.Post{}, nil
+		})
+
+	publisher := NewPublisher(store, clock, time.Hour)
+	publisher.Start(context.Background())
+
+	select {
+	case <-polled:
+	case <-time.After(time.Second):
+		t.Fatal("publisher did not poll on start")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		publisher.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("publisher did not stop")
+	}
+}
@@ -0,0 +1,154 @@
+package worker
+
+import (
+	"context"
+	"log"
+	db "promova-test-task/db/sqlc"
+	"promova-test-task/util"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// ViewBucketSize is the granularity of the view counts kept for popularity windows.
+	ViewBucketSize = time.Hour
+	// ViewRetention is how long bucketed view counts are kept, so it bounds the popularity window.
+	ViewRetention = 30 * 24 * time.Hour
+)
+
+type viewKey struct {
+	bucket time.Time
+	postID int32
+}
+
+// ViewCounter aggregates post views in memory and writes them periodically, one statement per time bucket,
+// so that a popular post costs a single row update per interval instead of one write per view.
+type ViewCounter struct {
+	store    db.Store
+	clock    util.Clock
+	interval time.Duration
+
+	mu           sync.Mutex
+	pending      map[viewKey]int64
+	prunedBefore time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewViewCounter creates a view counter flushing the counted views every interval.
+func NewViewCounter(store db.Store, clock util.Clock, interval time.Duration) *ViewCounter {
+	return &ViewCounter{
+		store:    store,
+		clock:    clock,
+		interval: interval,
+		pending:  make(map[viewKey]int64),
+	}
+}
+
+// RecordView counts a view of the post. It only touches memory, the view is written on the next flush.
+func (c *ViewCounter) RecordView(postID int32) {
+	key := viewKey{bucket: c.clock.Now().UTC().Truncate(ViewBucketSize), postID: postID}
+
+	c.mu.Lock()
+	c.pending[key]++
+	c.mu.Unlock()
+}
+
+// Start flushes in the background until Stop is called or ctx is done.
+func (c *ViewCounter) Start(ctx context.Context) {
+	ctx, c.cancel = context.WithCancel(ctx)
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if err := c.Flush(ctx); err != nil && ctx.Err() == nil {
+				log.Println("failed to flush post views:", err)
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic flushes and writes the views counted since the last one.
+// It is meant to be called once the HTTP server is shut down, so that no view is recorded after it.
+func (c *ViewCounter) Stop(ctx context.Context) error {
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.wg.Wait()
+	return c.Flush(ctx)
+}
+
+// Flush writes the views counted since the last flush and drops the buckets that fell out of the retention.
+// Views that could not be written are kept for the next flush.
+func (c *ViewCounter) Flush(ctx context.Context) error {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make(map[viewKey]int64)
+	c.mu.Unlock()
+
+	batches := make(map[time.Time]*db.AddPostViewsParams)
+	for key := range pending {
+		if _, ok := batches[key.bucket]; !ok {
+			batches[key.bucket] = &db.AddPostViewsParams{Bucket: key.bucket}
+		}
+		batches[key.bucket].PostIds = append(batches[key.bucket].PostIds, key.postID)
+	}
+	buckets := make([]time.Time, 0, len(batches))
+	for bucket, batch := range batches {
+		buckets = append(buckets, bucket)
+		sort.Slice(batch.PostIds, func(i, j int) bool { return batch.PostIds[i] < batch.PostIds[j] })
+		for _, postID := range batch.PostIds {
+			batch.Counts = append(batch.Counts, pending[viewKey{bucket: bucket, postID: postID}])
+		}
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Before(buckets[j]) })
+
+	for i, bucket := range buckets {
+		if err := c.store.AddPostViews(ctx, *batches[bucket]); err != nil {
+			c.restore(batches, buckets[i:])
+			return err
+		}
+	}
+
+	return c.prune(ctx)
+}
+
+// restore puts the views of the unwritten buckets back, merging them with the views counted meanwhile.
+func (c *ViewCounter) restore(batches map[time.Time]*db.AddPostViewsParams, buckets []time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, bucket := range buckets {
+		batch := batches[bucket]
+		for i, postID := range batch.PostIds {
+			c.pending[viewKey{bucket: bucket, postID: postID}] += batch.Counts[i]
+		}
+	}
+}
+
+// prune deletes the buckets older than the retention, at most once per bucket.
+func (c *ViewCounter) prune(ctx context.Context) error {
+	before := c.clock.Now().UTC().Truncate(ViewBucketSize).Add(-ViewRetention)
+	if !before.After(c.prunedBefore) {
+		return nil
+	}
+
+	if _, err := c.store.DeletePostViewBucketsBefore(ctx, before); err != nil {
+		return err
+	}
+	c.prunedBefore = before
+	return nil
+}
@@ -0,0 +1,179 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	mockdb "promova-test-task/db/mock"
+	db "promova-test-task/db/sqlc"
+	"testing"
+	"time"
+)
+
+type steppingClock struct {
+	now *time.Time
+}
+
+func (c steppingClock) Now() time.Time {
+	return *c.now
+}
+
+func TestViewCounter_Flush(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 59, 0, 0, time.UTC)
+	bucket := now.Truncate(ViewBucketSize)
+	nextBucket := bucket.Add(ViewBucketSize)
+	pruneBefore := nextBucket.Add(-ViewRetention)
+
+	testCases := []struct {
+		name       string
+		views      func(counter *ViewCounter, now *time.Time)
+		buildStubs func(store *mockdb.MockStore)
+		checkFlush func(t *testing.T, counter *ViewCounter, err error)
+	}{
+		{
+			name: "positive_Flush_AggregatesViews",
+			views: func(counter *ViewCounter, now *time.Time) {
+				counter.RecordView(2)
+				counter.RecordView(1)
+				counter.RecordView(2)
+				*now = now.Add(time.Minute)
+				counter.RecordView(2)
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				gomock.InOrder(
+					querier.EXPECT().
+						AddPostViews(gomock.Any(), gomock.Eq(db.AddPostViewsParams{PostIds: []int32{1, 2}, Counts: []int64{1, 2}, Bucket: bucket})).
+						Times(1).
+						Return(nil),
+					querier.EXPECT().
+						AddPostViews(gomock.Any(), gomock.Eq(db.AddPostViewsParams{PostIds: []int32{2}, Counts: []int64{1}, Bucket: nextBucket})).
+						Times(1).
+						Return(nil),
+					querier.EXPECT().
+						DeletePostViewBucketsBefore(gomock.Any(), gomock.Eq(pruneBefore)).
+						Times(1).
+						Return(int64(0), nil),
+				)
+			},
+			checkFlush: func(t *testing.T, counter *ViewCounter, err error) {
+				require.NoError(t, err)
+				require.Empty(t, counter.pending)
+			},
+		},
+		{
+			name:  "positive_Flush_NothingCounted",
+			views: func(counter *ViewCounter, now *time.Time) {},
+			buildStubs: func(querier *mockdb.MockStore) {
+				querier.EXPECT().
+					AddPostViews(gomock.Any(), gomock.Any()).
+					Times(0)
+				querier.EXPECT().
+					DeletePostViewBucketsBefore(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+			},
+			checkFlush: func(t *testing.T, counter *ViewCounter, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "negative_Flush_KeepsUnwrittenViews",
+			views: func(counter *ViewCounter, now *time.Time) {
+				counter.RecordView(1)
+				*now = now.Add(time.Minute)
+				counter.RecordView(1)
+				counter.RecordView(1)
+			},
+			buildStubs: func(querier *mockdb.MockStore) {
+				gomock.InOrder(
+					querier.EXPECT().
+						AddPostViews(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(nil),
+					querier.EXPECT().
+						AddPostViews(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(sql.ErrConnDone),
+				)
+				querier.EXPECT().
+					DeletePostViewBucketsBefore(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkFlush: func(t *testing.T, counter *ViewCounter, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+				require.Equal(t, map[viewKey]int64{{bucket: nextBucket, postID: 1}: 2}, counter.pending)
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			store := mockdb.NewMockStore(controller)
+			testCase.buildStubs(store)
+			clockNow := now
+			counter := NewViewCounter(store, steppingClock{now: &clockNow}, time.Minute)
+
+			testCase.views(counter, &clockNow)
+			err := counter.Flush(context.Background())
+			testCase.checkFlush(t, counter, err)
+		})
+	}
+}
+
+func TestViewCounter_PrunesOncePerBucket(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().
+		AddPostViews(gomock.Any(), gomock.Any()).
+		Times(2).
+		Return(nil)
+	store.EXPECT().
+		DeletePostViewBucketsBefore(gomock.Any(), gomock.Eq(now.Add(-ViewRetention))).
+		Times(1).
+		Return(int64(3), nil)
+
+	counter := NewViewCounter(store, steppingClock{now: &now}, time.Minute)
+	for i := 0; i < 2; i++ {
+		counter.RecordView(1)
+		require.NoError(t, counter.Flush(context.Background()))
+	}
+}
+
+func TestViewCounter_StopFlushes(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := mockdb.NewMockStore(controller)
+	store.EXPECT().
+		AddPostViews(gomock.Any(), gomock.Eq(db.AddPostViewsParams{PostIds: []int32{7}, Counts: []int64{1}, Bucket: now})).
+		Times(1).
+		Return(nil)
+	store.EXPECT().
+		DeletePostViewBucketsBefore(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(int64(0), nil)
+
+	counter := NewViewCounter(store, steppingClock{now: &now}, time.Hour)
+	counter.Start(context.Background())
+	counter.RecordView(7)
+
+	stopped := make(chan error)
+	go func() {
+		stopped <- counter.Stop(context.Background())
+	}()
+
+	select {
+	case err := <-stopped:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("view counter did not stop")
+	}
+}